@@ -24,6 +24,65 @@ var SessionSecret string
 // Database (env `CA_DATABASE`) is the PostgreSQL's connection string.
 var Database string
 
+// TrustProxy (env `CA_TRUST_PROXY`) indicates if the server is behind a reverse
+// proxy, and so if the clients' IP addresses should be read from the
+// X-Forwarded-For header.
+// Default: false.
+var TrustProxy bool
+
+// RateLimit (env `CA_RATE_LIMIT`) is the maximum number of requests per minute
+// that can be made to the sign in, sign up and password recovery pages, from
+// the same IP address or for the same account.
+// Default: 10.
+var RateLimit int
+
+// PublicRateLimit (env `CA_PUBLIC_RATE_LIMIT`) is the maximum number of requests
+// per minute that can be made to the public recipes and collections and to the
+// calendar feeds from the same IP address, so that their codes cannot be guessed.
+// Default: 30.
+var PublicRateLimit int
+
+// LockoutThreshold (env `CA_LOCKOUT_THRESHOLD`) is the number of failed attempts
+// in a row after which an IP address or an account is locked out.
+// Default: 5.
+var LockoutThreshold int
+
+// LockoutDuration (env `CA_LOCKOUT_DURATION`) is the duration of the first lockout,
+// in minutes. It doubles at every further failed attempt.
+// Default: 5.
+var LockoutDuration int
+
+// EmailInterval (env `CA_EMAIL_INTERVAL`) is the minimum number of minutes
+// between two requests that send an email to the same account (like the
+// password recovery), so that nobody can flood someone else's inbox.
+// Default: 5.
+var EmailInterval int
+
+// EventsRetention (env `CA_EVENTS_RETENTION`) is the number of days after which
+// the security events of the users (sign ins, password changes...) are deleted.
+// Default: 90.
+var EventsRetention int
+
+// OIDCIssuer (env `CA_OIDC_ISSUER`) is the URL of an OpenID Connect provider
+// the users can sign in with. If not set, the single sign-on is disabled.
+var OIDCIssuer string
+
+// OIDCClientID (env `CA_OIDC_CLIENT_ID`) is the client ID given by the provider.
+var OIDCClientID string
+
+// OIDCClientSecret (env `CA_OIDC_CLIENT_SECRET`) is the client secret given by the provider.
+var OIDCClientSecret string
+
+// OIDCName (env `CA_OIDC_NAME`) is the name of the provider shown to the users.
+// Default: "SSO".
+var OIDCName string
+
+// PasswordsDisabled (env `CA_PASSWORDS_DISABLED`) indicates if the users can only
+// sign in with the OpenID Connect provider (or with a passkey), and not with a
+// password. It requires OIDCIssuer to be set.
+// Default: false.
+var PasswordsDisabled bool
+
 // EmailEnabled (env `CA_EMAIL_ENABLED`) indicates if the server should send emails
 // or write their content in the logs.
 var EmailEnabled bool
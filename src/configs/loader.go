@@ -4,6 +4,7 @@ import (
 	"github.com/joho/godotenv"
 	"log/slog"
 	"os"
+	"strconv"
 )
 
 // LoadAndParse loads and parses the config files
@@ -44,6 +45,21 @@ func parse() {
 	Port = parseString("CA_PORT", !Test)
 	SessionSecret = parseString("CA_SESSIONSECRET", !Test)
 	Database = parseString("CA_DATABASE", true)
+	TrustProxy = parseBool("CA_TRUST_PROXY", false)
+	RateLimit = parseInt("CA_RATE_LIMIT", 10)
+	PublicRateLimit = parseInt("CA_PUBLIC_RATE_LIMIT", 30)
+	LockoutThreshold = parseInt("CA_LOCKOUT_THRESHOLD", 5)
+	LockoutDuration = parseInt("CA_LOCKOUT_DURATION", 5)
+	EmailInterval = parseInt("CA_EMAIL_INTERVAL", 5)
+	EventsRetention = parseInt("CA_EVENTS_RETENTION", 90)
+	PasswordsDisabled = parseBool("CA_PASSWORDS_DISABLED", false)
+	OIDCIssuer = parseString("CA_OIDC_ISSUER", PasswordsDisabled)
+	OIDCClientID = parseString("CA_OIDC_CLIENT_ID", OIDCIssuer != "")
+	OIDCClientSecret = parseString("CA_OIDC_CLIENT_SECRET", OIDCIssuer != "")
+	OIDCName = parseString("CA_OIDC_NAME", false)
+	if OIDCName == "" {
+		OIDCName = "SSO"
+	}
 	EmailEnabled = parseBool("CA_EMAIL_ENABLED", !Test)
 	EmailSender = parseString("CA_EMAIL_SENDER", EmailEnabled)
 	EmailServer = parseString("CA_EMAIL_SERVER", EmailEnabled)
@@ -82,3 +98,20 @@ func parseBool(env string, required bool) bool {
 
 	return false
 }
+
+// parseInt reads a positive int from the environment variables, and
+// shows an error if it's not valid. If it's not set, fallback is returned.
+func parseInt(env string, fallback int) int {
+	value := parseString(env, false)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		slog.Error("unknown config value:", "field", env)
+		os.Exit(1)
+	}
+
+	return n
+}
@@ -2,7 +2,7 @@ package configs
 
 import "fmt"
 
-const VersionCode = 9
-const VersionName = "Maracuja"
+const VersionCode = 10
+const VersionName = "Mirtillo"
 
 var Version string = fmt.Sprintf("%d (%s)", VersionCode, VersionName)
@@ -0,0 +1,237 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// maxSearchedUsers is the maximum number of users returned by SearchUsers
+const maxSearchedUsers = 50
+
+// maxAuditEntries is the maximum number of entries returned by GetAuditLog
+const maxAuditEntries = 100
+
+// AdminAction is an action an admin can take on a user
+type AdminAction string
+
+const (
+	ADMIN_DELETE              AdminAction = "delete"
+	ADMIN_DISABLE             AdminAction = "disable"
+	ADMIN_ENABLE              AdminAction = "enable"
+	ADMIN_RESEND_VERIFICATION AdminAction = "resend_verification"
+)
+
+// AuditEntry is an action taken by an admin
+type AuditEntry struct {
+	// AEID is the Audit Entry ID
+	AEID int
+
+	// Admin is the username of the admin
+	Admin string
+
+	// Action is what the admin has done
+	Action AdminAction
+
+	// Target is the username of the user the action has been taken on
+	Target string
+
+	// Time is when the action has been taken
+	Time time.Time
+}
+
+// FormatTime returns the time of the action as a string
+func (e AuditEntry) FormatTime() string {
+	return e.Time.Format(time.DateTime)
+}
+
+// DailyStats are the Stats saved at the end of a day
+type DailyStats struct {
+	Stats
+
+	// Day is the day the stats refer to
+	Day time.Time
+}
+
+// FormatDay returns the day as a string
+func (s DailyStats) FormatDay() string {
+	return s.Day.Format(time.DateOnly)
+}
+
+// SetAdmin grants or revokes the admin role of the user
+func (u User) SetAdmin(admin bool) error {
+	res, err := db.Exec(`UPDATE ca_users SET admin=$2 WHERE uid=$1;`, u.UID, admin)
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra < 1 {
+		return ERR_USER_UNKNOWN
+	}
+
+	return nil
+}
+
+// SearchUsers returns the users whose username or email contains
+// the query, ordered by username
+func SearchUsers(query string) ([]User, error) {
+	var users []User
+
+	// Escapes the wildcards of the query
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(query) + "%"
+
+	rows, err := db.Query(`SELECT uid, username, email, email_verified, admin, disabled FROM ca_users
+						   WHERE username ILIKE $1 OR email ILIKE $1 ORDER BY username LIMIT $2;`,
+		pattern, maxSearchedUsers)
+	if err != nil {
+		return users, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		u := User{fetched: true}
+		rows.Scan(&u.UID, &u.Username, &u.Email, &u.EmailVerified, &u.Admin, &u.Disabled)
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+// getTarget returns the user an admin is taking an action on,
+// making sure it's not the admin itself
+func (a User) getTarget(UID int) (User, error) {
+	if UID == a.UID {
+		return User{}, ERR_USER_SELF
+	}
+
+	return GetUser("UID", UID)
+}
+
+// LogAdminAction adds an action taken by the admin to the audit log
+func (a User) LogAdminAction(action AdminAction, target User) error {
+	return a.logAdminAction(db, action, target)
+}
+
+// logAdminAction is like LogAdminAction, but it uses the given
+// database or transaction
+func (a User) logAdminAction(exec interface {
+	Exec(string, ...any) (sql.Result, error)
+}, action AdminAction, target User) error {
+	_, err := exec.Exec(`INSERT INTO audit_log (admin, action, target) VALUES ($1, $2, $3);`,
+		a.Username, action, target.Username)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// takeAction executes a query on the target user, on behalf of the admin,
+// and adds it to the audit log. Either both of them succeed or none does.
+func (a User) takeAction(UID int, action AdminAction, query string, args ...any) error {
+	target, err := a.getTarget(UID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(query, append([]any{UID}, args...)...); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	if err = a.logAdminAction(tx, action, target); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// setDisabled disables or enables a user, on behalf of the admin
+func (a User) setDisabled(UID int, disabled bool, action AdminAction) error {
+	return a.takeAction(UID, action, `UPDATE ca_users SET disabled=$2 WHERE uid=$1;`, disabled)
+}
+
+// DisableUser prevents a user from signing in, and signs it out
+// from every session
+func (a User) DisableUser(UID int) error {
+	if err := a.setDisabled(UID, true, ADMIN_DISABLE); err != nil {
+		return err
+	}
+
+	return User{UID: UID}.RevokeOtherSessions("")
+}
+
+// EnableUser allows a disabled user to sign in again
+func (a User) EnableUser(UID int) error {
+	return a.setDisabled(UID, false, ADMIN_ENABLE)
+}
+
+// DeleteUser deletes a user and all of its content, on behalf of the admin
+func (a User) DeleteUser(UID int) error {
+	return a.takeAction(UID, ADMIN_DELETE, `DELETE FROM ca_users WHERE uid=$1;`)
+}
+
+// GetAuditLog returns the last actions taken by the admins
+func GetAuditLog() ([]AuditEntry, error) {
+	var entries []AuditEntry
+
+	rows, err := db.Query(`SELECT aeid, admin, action, target, time FROM audit_log
+						   ORDER BY time DESC, aeid DESC LIMIT $1;`, maxAuditEntries)
+	if err != nil {
+		return entries, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var e AuditEntry
+		rows.Scan(&e.AEID, &e.Admin, &e.Action, &e.Target, &e.Time)
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// SaveStats saves the current Stats as the ones of today
+func SaveStats() error {
+	s := GetStats()
+
+	_, err := db.Exec(`INSERT INTO stats_history (day, users, menus, sections, articles, entries, recipes)
+					   VALUES (CURRENT_DATE, $1, $2, $3, $4, $5, $6) ON CONFLICT (day) DO UPDATE SET
+					   users=EXCLUDED.users, menus=EXCLUDED.menus, sections=EXCLUDED.sections,
+					   articles=EXCLUDED.articles, entries=EXCLUDED.entries, recipes=EXCLUDED.recipes;`,
+		s.UsersNumber, s.MenusNumber, s.SectionsNumber, s.ArticlesNumber, s.EntriesNumber, s.RecipesNumber)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// GetStatsHistory returns the Stats saved in the last days,
+// from the most recent
+func GetStatsHistory(days int) ([]DailyStats, error) {
+	var history []DailyStats
+
+	rows, err := db.Query(`SELECT day, users, menus, sections, articles, entries, recipes FROM stats_history
+						   WHERE day > CURRENT_DATE - $1::INT ORDER BY day DESC;`, days)
+	if err != nil {
+		return history, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var s DailyStats
+		rows.Scan(&s.Day, &s.UsersNumber, &s.MenusNumber, &s.SectionsNumber,
+			&s.ArticlesNumber, &s.EntriesNumber, &s.RecipesNumber)
+		history = append(history, s)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,248 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+// getTestingAdmin returns an admin to be used for testing purposes
+func getTestingAdmin(t *testing.T) User {
+	admin, _ := getTestingUser(t)
+	if err := admin.SetAdmin(true); err != nil {
+		t.Fatalf("Cannot create testing admin: %s", err.Error())
+	}
+
+	admin.Admin = true
+	return admin
+}
+
+func TestUserSetAdmin(t *testing.T) {
+	user, _ := getTestingUser(t)
+
+	type data struct {
+		U     User
+		Admin bool
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.SetAdmin(d.Admin); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if got, _ := GetUser("UID", d.U.UID); got.Admin != d.Admin {
+					t.Errorf("%s: expected admin <%v>, got <%v>", msg, d.Admin, got.Admin)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"granted role to unknown user", data{U: unknownUser, Admin: true, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"(granted)", data{U: user, Admin: true}},
+			{"(revoked)", data{U: user, Admin: false}},
+		},
+	}.Run(t)
+}
+
+func TestSearchUsers(t *testing.T) {
+	user, _ := getTestingUser(t)
+
+	type data struct {
+		Query string
+
+		ExpectedUID int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			users, err := SearchUsers(d.Query)
+			if err != nil {
+				t.Errorf("%s: expected err <nil>, got <%v>", msg, err)
+			}
+
+			found := false
+			for _, u := range users {
+				found = found || u.UID == user.UID
+			}
+			if found != (d.ExpectedUID != 0) {
+				t.Errorf("%s: expected user found <%v>, got <%v>", msg, d.ExpectedUID != 0, found)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"(username)", data{Query: user.Username, ExpectedUID: user.UID}},
+			{"(email)", data{Query: user.Email, ExpectedUID: user.UID}},
+			{"(case insensitive)", data{Query: strings.ToUpper(user.Username), ExpectedUID: user.UID}},
+			{"(wildcards)", data{Query: "%_unknown_%"}},
+		},
+	}.Run(t)
+}
+
+func TestAdminDisableUser(t *testing.T) {
+	admin := getTestingAdmin(t)
+	user, password := getTestingUser(t)
+	key := getTestingSession(t, user)
+
+	type data struct {
+		UID int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := admin.DisableUser(d.UID); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if _, err = SignIn(user.Username, password); err != ERR_USER_DISABLED {
+					t.Errorf("%s: expected sign in err <%v>, got <%v>", msg, ERR_USER_DISABLED, err)
+				} else if err = user.TouchSession(key, "agent", "127.0.0.1"); err != ERR_SESSION_NOT_FOUND {
+					t.Errorf("%s: session not revoked", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"disabled unknown user", data{UID: unknownUser.UID, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"disabled itself", data{UID: admin.UID, ExpectedErr: ERR_USER_SELF}},
+			{"", data{UID: user.UID}},
+		},
+	}.Run(t)
+}
+
+func TestAdminDisableUserPublicContent(t *testing.T) {
+	admin := getTestingAdmin(t)
+	user, _ := getTestingUser(t)
+	RID, _ := user.Recipes().New("recipe")
+	user.Recipes().Edit(RID, Recipe{Name: "recipe", Tags: []string{"PASTA"}})
+	recipeCode, _ := user.Recipes().Share(RID)
+	collectionCode, _ := user.Collections().Publish("PASTA")
+	token, _ := user.EnableCalendar()
+	user.SetProfile("Hi!")
+
+	admin.DisableUser(user.UID)
+
+	if _, err := GetPublicRecipe(recipeCode); err != ERR_RECIPE_NOT_FOUND {
+		t.Errorf("recipe: expected err <%v>, got <%v>", ERR_RECIPE_NOT_FOUND, err)
+	}
+	if _, err := GetPublicCollection(collectionCode); err != ERR_COLLECTION_NOT_FOUND {
+		t.Errorf("collection: expected err <%v>, got <%v>", ERR_COLLECTION_NOT_FOUND, err)
+	}
+	if _, err := GetCalendarUser(token); err != ERR_CALENDAR_NOT_FOUND {
+		t.Errorf("calendar: expected err <%v>, got <%v>", ERR_CALENDAR_NOT_FOUND, err)
+	}
+	if _, err := GetPublicProfile(user.Username); err != ERR_PROFILE_NOT_FOUND {
+		t.Errorf("profile: expected err <%v>, got <%v>", ERR_PROFILE_NOT_FOUND, err)
+	}
+
+	admin.EnableUser(user.UID)
+
+	if _, err := GetPublicRecipe(recipeCode); err != nil {
+		t.Errorf("enabled recipe: expected err <nil>, got <%v>", err)
+	}
+}
+
+func TestAdminEnableUser(t *testing.T) {
+	admin := getTestingAdmin(t)
+	user, password := getTestingUser(t)
+	admin.DisableUser(user.UID)
+
+	type data struct {
+		UID int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := admin.EnableUser(d.UID); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if _, err = SignIn(user.Username, password); err != nil {
+					t.Errorf("%s: expected sign in err <nil>, got <%v>", msg, err)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"enabled unknown user", data{UID: unknownUser.UID, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"enabled itself", data{UID: admin.UID, ExpectedErr: ERR_USER_SELF}},
+			{"", data{UID: user.UID}},
+		},
+	}.Run(t)
+}
+
+func TestAdminDeleteUser(t *testing.T) {
+	admin := getTestingAdmin(t)
+	user, _ := getTestingUser(t)
+
+	type data struct {
+		UID int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := admin.DeleteUser(d.UID); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if _, err = GetUser("UID", d.UID); err != ERR_USER_UNKNOWN {
+					t.Errorf("%s: user not deleted", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"deleted itself", data{UID: admin.UID, ExpectedErr: ERR_USER_SELF}},
+			{"", data{UID: user.UID}},
+			{"deleted user twice", data{UID: user.UID, ExpectedErr: ERR_USER_UNKNOWN}},
+		},
+	}.Run(t)
+}
+
+func TestGetAuditLog(t *testing.T) {
+	admin := getTestingAdmin(t)
+	user, _ := getTestingUser(t)
+	admin.DisableUser(user.UID)
+	admin.EnableUser(user.UID)
+	admin.EnableUser(admin.UID)
+
+	entries, err := GetAuditLog()
+	if err != nil {
+		t.Fatalf("expected err <nil>, got <%v>", err)
+	}
+
+	var actions []AdminAction
+	for _, e := range entries {
+		if e.Admin == admin.Username {
+			if e.Target != user.Username {
+				t.Errorf("expected target <%s>, got <%s>", user.Username, e.Target)
+			}
+			actions = append(actions, e.Action)
+		}
+	}
+	if len(actions) != 2 || actions[0] != ADMIN_ENABLE || actions[1] != ADMIN_DISABLE {
+		t.Errorf("expected actions <[%s %s]>, got <%v>", ADMIN_ENABLE, ADMIN_DISABLE, actions)
+	}
+}
+
+func TestGetStatsHistory(t *testing.T) {
+	if err := SaveStats(); err != nil {
+		t.Fatalf("Cannot save stats: %s", err.Error())
+	}
+	getTestingUser(t)
+	if err := SaveStats(); err != nil {
+		t.Fatalf("Cannot save stats again: %s", err.Error())
+	}
+
+	history, err := GetStatsHistory(30)
+	if err != nil {
+		t.Fatalf("expected err <nil>, got <%v>", err)
+	} else if len(history) != 1 {
+		t.Fatalf("expected <1> day, got <%d>", len(history))
+	} else if history[0].UsersNumber != GetStats().UsersNumber {
+		t.Errorf("expected <%d> users, got <%d>", GetStats().UsersNumber, history[0].UsersNumber)
+	}
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CalendarDay is a day of a menu anchored to the calendar,
+// as shown in the calendar feed
+type CalendarDay struct {
+	Day
+
+	// Menu is the name of the menu
+	Menu string
+
+	// Updated is when the day has been last changed
+	Updated time.Time
+}
+
+// CalendarArticle is an article of the storage that expires,
+// as shown in the calendar feed
+type CalendarArticle struct {
+	Article
+
+	// Updated is when the article has been last changed
+	Updated time.Time
+}
+
+// GetCalendarToken returns the secret token of the user's calendar
+// feeds, or an empty string if they are disabled
+func (u User) GetCalendarToken() (string, error) {
+	var token string
+
+	err := db.QueryRow(`SELECT token FROM calendars WHERE uid=$1;`, u.UID).Scan(&token)
+	if err == sql.ErrNoRows {
+		// Makes sure the user exists
+		_, err := GetUser("UID", u.UID)
+		return token, err
+	} else if err != nil {
+		return token, ERR_UNKNOWN
+	}
+
+	return token, nil
+}
+
+// EnableCalendar generates a new secret token for the user's calendar
+// feeds (invalidating the old one, if present), then returns it
+func (u User) EnableCalendar() (string, error) {
+	// Ensures the user exists
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return "", err
+	}
+
+	// Saves the new token
+	token := generateCode()
+	_, err := db.Exec(`INSERT INTO calendars (uid, token) VALUES ($1, $2)
+					   ON CONFLICT (uid) DO UPDATE SET token=EXCLUDED.token;`, u.UID, token)
+	if err != nil {
+		return "", ERR_UNKNOWN
+	}
+
+	return token, nil
+}
+
+// DisableCalendar deletes the secret token of the user's calendar feeds
+func (u User) DisableCalendar() error {
+	// Ensures the user exists
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return err
+	}
+
+	// Deletes the token
+	if _, err := db.Exec(`DELETE FROM calendars WHERE uid=$1;`, u.UID); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// GetCalendarUser returns the user that owns a calendar token.
+// The calendars of disabled users are not found.
+func GetCalendarUser(token string) (User, error) {
+	var UID int
+
+	err := db.QueryRow(`SELECT c.uid FROM calendars c INNER JOIN ca_users u ON u.uid=c.uid
+						WHERE c.token=$1 AND NOT u.disabled;`, token).Scan(&UID)
+	if err == sql.ErrNoRows {
+		return User{}, ERR_CALENDAR_NOT_FOUND
+	} else if err != nil {
+		return User{}, ERR_UNKNOWN
+	}
+
+	return GetUser("UID", UID)
+}
+
+// GetCalendar returns the days of all the menus anchored to the calendar,
+// ordered by date
+func (m Menus) GetCalendar() ([]CalendarDay, error) {
+	var days []CalendarDay
+
+	// Queries the days
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT d.mid, d.name, d.position, d.meals, d.updated, m.name, m.start, m.slots
+						   FROM days d INNER JOIN menus m ON m.mid=d.mid
+						   WHERE m.uid=$1 AND m.start IS NOT NULL
+						   ORDER BY m.start + d.position, m.mid;`, m.uid)
+	if err != nil {
+		return days, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var day CalendarDay
+		var start time.Time
+		rows.Scan(&day.MID, &day.Name, &day.Position, pq.Array(&day.Meals), &day.Updated, &day.Menu, &start, pq.Array(&day.Slots))
+
+		day.setDate(&start)
+		if len(day.Slots) == 0 {
+			day.Slots = nil
+		}
+		days = append(days, day)
+	}
+
+	// If no days have been found, makes sure the user exists
+	if len(days) == 0 {
+		_, err := GetUser("UID", m.uid)
+		return days, err
+	}
+
+	return days, nil
+}
+
+// GetCalendar returns all the articles of the storage that have an
+// expiration, ordered by expiration date
+func (s Storage) GetCalendar() ([]CalendarArticle, error) {
+	var articles []Article
+	var updated []time.Time
+
+	// Queries the articles
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT a.sid, a.aid, a.name, a.expiration, a.quantity, a.category, a.opened, a.frozen, a.updated
+						   FROM articles a INNER JOIN sections s ON s.sid=a.sid
+						   WHERE s.uid=$1 AND a.expiration!=$2 ORDER BY a.expiration, a.aid;`, s.uid, defaultExpiration)
+	if err != nil {
+		return nil, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var a Article
+		var u time.Time
+		rows.Scan(&a.SID, &a.AID, &a.Name, &a.Expiration, &a.Quantity, &a.Category, &a.Opened, &a.Frozen, &u)
+		articles = append(articles, a)
+		updated = append(updated, u)
+	}
+
+	// If no articles have been found, makes sure the user exists
+	if len(articles) == 0 {
+		_, err := GetUser("UID", s.uid)
+		return nil, err
+	}
+
+	// Computes the effective expirations
+	if err = s.setEffectiveExpirations(articles); err != nil {
+		return nil, err
+	}
+
+	calendar := make([]CalendarArticle, len(articles))
+	for i, a := range articles {
+		calendar[i] = CalendarArticle{Article: a, Updated: updated[i]}
+	}
+
+	return calendar, nil
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUserEnableCalendar(t *testing.T) {
+	u, _ := getTestingUser(t)
+
+	type data struct {
+		U User
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			old, _ := d.U.GetCalendarToken()
+
+			token, err := d.U.EnableCalendar()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if got, _ := d.U.GetCalendarToken(); got != token {
+					t.Errorf("%s: expected token <%s>, got <%s>", msg, token, got)
+				} else if owner, err := GetCalendarUser(token); err != nil || owner.UID != d.U.UID {
+					t.Errorf("%s: token not linked to the user: got <%v>, <%v>", msg, owner.UID, err)
+				} else if _, err := GetCalendarUser(old); old != "" && err != ERR_CALENDAR_NOT_FOUND {
+					t.Errorf("%s: old token still valid: got err <%v>", msg, err)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user enabled calendar", data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"", data{U: u}},
+			{"(regenerated)", data{U: u}},
+		},
+	}.Run(t)
+}
+
+func TestUserDisableCalendar(t *testing.T) {
+	u, _ := getTestingUser(t)
+	token, _ := u.EnableCalendar()
+
+	type data struct {
+		U User
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.DisableCalendar(); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if got, _ := d.U.GetCalendarToken(); got != "" {
+					t.Errorf("%s: expected no token, got <%s>", msg, got)
+				} else if _, err := GetCalendarUser(token); err != ERR_CALENDAR_NOT_FOUND {
+					t.Errorf("%s: token still valid: got err <%v>", msg, err)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user disabled calendar", data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"", data{U: u}},
+			{"(again)", data{U: u}},
+		},
+	}.Run(t)
+}
+
+func TestMenusGetCalendar(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	start := time.Date(2024, time.March, 30, 0, 0, 0, 0, time.UTC)
+	MID, _ := m.New("test", []string{"d0", "d1"}, 2)
+	m.SetStart(MID, &start)
+	m.New("not anchored", []string{"d0"}, 1)
+
+	emptyU, _ := getTestingUser(t)
+
+	type data struct {
+		M Menus
+
+		ExpectedErr   error
+		ExpectedDates []string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			days, err := d.M.GetCalendar()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if len(days) != len(d.ExpectedDates) {
+				t.Errorf("%s: expected <%d> days, got <%d>", msg, len(d.ExpectedDates), len(days))
+			} else {
+				for i, day := range days {
+					if day.Menu != "test" || day.FormatDate() != d.ExpectedDates[i] {
+						t.Errorf("%s: expected day on <%s>, got <%v>", msg, d.ExpectedDates[i], day)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"got calendar of unknown user", data{M: unknownUser.Menus(), ExpectedErr: ERR_USER_UNKNOWN}},
+			{"(empty)", data{M: emptyU.Menus()}},
+			{"", data{M: m, ExpectedDates: []string{"2024-03-30", "2024-03-31"}}},
+		},
+	}.Run(t)
+
+	// Ensures the days are marked as updated when their meals change
+	before, _ := m.GetCalendar()
+	time.Sleep(10 * time.Millisecond)
+	m.SetDayMeals(MID, 1, []string{"pasta", "soup"})
+	after, _ := m.GetCalendar()
+	if len(before) != 2 || len(after) != 2 {
+		t.Fatalf("expected <2> days, got <%d> and <%d>", len(before), len(after))
+	} else if !after[1].Updated.After(before[1].Updated) {
+		t.Errorf("day not updated: before <%v>, after <%v>", before[1].Updated, after[1].Updated)
+	} else if !after[0].Updated.Equal(before[0].Updated) {
+		t.Errorf("unchanged day updated: before <%v>, after <%v>", before[0].Updated, after[0].Updated)
+	}
+}
+
+func TestStorageGetCalendar(t *testing.T) {
+	u, _ := getTestingUser(t)
+	s := u.Storage()
+	SID, _ := s.NewSection("Fridge")
+	section := strconv.Itoa(SID)
+	s.AddArticles(
+		StringArticle{Section: section, Name: "milk", Expiration: "2024-03-30"},
+		StringArticle{Section: section, Name: "salt"},
+		StringArticle{Section: section, Name: "eggs", Expiration: "2024-03-31"},
+	)
+
+	emptyU, _ := getTestingUser(t)
+
+	type data struct {
+		S Storage
+
+		ExpectedErr   error
+		ExpectedNames []string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			articles, err := d.S.GetCalendar()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if len(articles) != len(d.ExpectedNames) {
+				t.Errorf("%s: expected <%d> articles, got <%d>", msg, len(d.ExpectedNames), len(articles))
+			} else {
+				for i, article := range articles {
+					if article.Name != d.ExpectedNames[i] {
+						t.Errorf("%s: expected article <%s>, got <%s>", msg, d.ExpectedNames[i], article.Name)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user got calendar", data{S: unknownUser.Storage(), ExpectedErr: ERR_USER_UNKNOWN}},
+			{"(empty)", data{S: emptyU.Storage()}},
+			{"", data{S: s, ExpectedNames: []string{"milk", "eggs"}}},
+		},
+	}.Run(t)
+
+	// Ensures the articles are marked as updated when they change
+	before, _ := s.GetCalendar()
+	time.Sleep(10 * time.Millisecond)
+	s.EditArticle(before[1].AID, StringArticle{Section: section, Name: "eggs", Expiration: "2024-04-01"})
+	after, _ := s.GetCalendar()
+	if len(before) != 2 || len(after) != 2 {
+		t.Fatalf("expected <2> articles, got <%d> and <%d>", len(before), len(after))
+	} else if !after[1].Updated.After(before[1].Updated) {
+		t.Errorf("article not updated: before <%v>, after <%v>", before[1].Updated, after[1].Updated)
+	} else if !after[0].Updated.Equal(before[0].Updated) {
+		t.Errorf("unchanged article updated: before <%v>, after <%v>", before[0].Updated, after[0].Updated)
+	}
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// errCBOR is returned when some CBOR data cannot be decoded
+var errCBOR = errors.New("invalid cbor")
+
+// cborMaxDepth is the maximum nesting of arrays and maps
+const cborMaxDepth = 16
+
+// decodeCBOR decodes the first CBOR item (RFC 8949) in data, and returns
+// it with the remaining bytes. It supports only what WebAuthn uses:
+// integers (as int64), byte strings, text strings, arrays ([]any),
+// maps (map[any]any, with int64 or string keys), booleans and null.
+func decodeCBOR(data []byte) (any, []byte, error) {
+	return decodeCBORItem(data, 0)
+}
+
+// decodeCBORHead reads the major type and the argument of an item
+func decodeCBORHead(data []byte) (major byte, arg uint64, rest []byte, err error) {
+	if len(data) < 1 {
+		return 0, 0, nil, errCBOR
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+
+	switch {
+	case info < 24:
+		return major, uint64(info), data, nil
+	case info == 24 && len(data) >= 1:
+		return major, uint64(data[0]), data[1:], nil
+	case info == 25 && len(data) >= 2:
+		return major, uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case info == 26 && len(data) >= 4:
+		return major, uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case info == 27 && len(data) >= 8:
+		return major, binary.BigEndian.Uint64(data), data[8:], nil
+	}
+
+	// Indefinite lengths and reserved values are not supported
+	return 0, 0, nil, errCBOR
+}
+
+// decodeCBORItem is like decodeCBOR, keeping track of the nesting
+func decodeCBORItem(data []byte, depth int) (any, []byte, error) {
+	if depth > cborMaxDepth {
+		return nil, nil, errCBOR
+	}
+
+	major, arg, data, err := decodeCBORHead(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		// Unsigned integer
+		if arg > 1<<63-1 {
+			return nil, nil, errCBOR
+		}
+		return int64(arg), data, nil
+
+	case 1:
+		// Negative integer
+		if arg > 1<<63-1 {
+			return nil, nil, errCBOR
+		}
+		return -1 - int64(arg), data, nil
+
+	case 2, 3:
+		// Byte and text strings
+		if arg > uint64(len(data)) {
+			return nil, nil, errCBOR
+		}
+		if major == 2 {
+			return data[:arg], data[arg:], nil
+		}
+		return string(data[:arg]), data[arg:], nil
+
+	case 4:
+		// Array
+		if arg > uint64(len(data)) {
+			return nil, nil, errCBOR
+		}
+
+		array := make([]any, 0, arg)
+		for range arg {
+			var item any
+			if item, data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			array = append(array, item)
+		}
+		return array, data, nil
+
+	case 5:
+		// Map
+		if arg > uint64(len(data)) {
+			return nil, nil, errCBOR
+		}
+
+		m := make(map[any]any, arg)
+		for range arg {
+			var key, value any
+			if key, data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, errCBOR
+			}
+			if value, data, err = decodeCBORItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			m[key] = value
+		}
+		return m, data, nil
+
+	case 7:
+		// Simple values
+		switch arg {
+		case 20:
+			return false, data, nil
+		case 21:
+			return true, data, nil
+		case 22:
+			return nil, data, nil
+		}
+	}
+
+	// Tags, floats and the other simple values are not supported
+	return nil, nil, errCBOR
+}
@@ -0,0 +1,237 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Collection is a public group of recipes: all the recipes of a user
+// that have a certain tag
+type Collection struct {
+	// Tag is the tag shared by the recipes
+	Tag string
+
+	// Code is a random code used to share the collection
+	Code string
+
+	// Author is the username of the collection's owner
+	Author string
+
+	// Recipes are the recipes of the collection
+	Recipes []Recipe
+}
+
+// Collections is used to manage the published collections
+type Collections struct {
+	uid int
+}
+
+// Collections returns the collections manager for the user
+func (u User) Collections() Collections {
+	return Collections{uid: u.UID}
+}
+
+// GetAll returns the published collections (with only Tag and Code),
+// ordered by tag
+func (c Collections) GetAll() ([]Collection, error) {
+	var collections []Collection
+
+	// Queries the collections
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT tag, code FROM collections WHERE uid=$1 ORDER BY tag;`, c.uid)
+	if err != nil {
+		return collections, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var collection Collection
+		rows.Scan(&collection.Tag, &collection.Code)
+		collections = append(collections, collection)
+	}
+
+	// If no collections have been found, makes sure the user exists
+	if len(collections) == 0 {
+		_, err := GetUser("UID", c.uid)
+		return collections, err
+	}
+
+	return collections, nil
+}
+
+// Publish creates the code of the collection of the recipes with the
+// given tag. If it's already published, its code is kept, so that
+// the links that have been shared keep working.
+func (c Collections) Publish(tag string) (string, error) {
+	// Ensures the tag exists
+	var found int
+	err := db.QueryRow(`SELECT COUNT(*) FROM tags t INNER JOIN recipes r ON r.rid=t.rid
+						WHERE r.uid=$1 AND t.name=$2;`, c.uid, tag).Scan(&found)
+	if err != nil {
+		return "", ERR_UNKNOWN
+	} else if found == 0 {
+		if _, err = GetUser("UID", c.uid); err != nil {
+			return "", err
+		}
+
+		return "", ERR_TAG_NOT_FOUND
+	}
+
+	for true {
+		// Generates the code
+		code := generateCode()
+
+		// Saves it, unless there is already one
+		err := db.QueryRow(`INSERT INTO collections (uid, tag, code) VALUES ($1, $2, $3)
+							ON CONFLICT (uid, tag) DO UPDATE SET code=collections.code RETURNING code;`,
+			c.uid, tag, code).Scan(&code)
+		if err != nil {
+			if pqe, ok := err.(*pq.Error); ok && pqe.Code == "23505" {
+				continue
+			} else {
+				return "", ERR_UNKNOWN
+			}
+		} else {
+			return code, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Unpublish deletes the code of a collection
+func (c Collections) Unpublish(tag string) error {
+	res, err := db.Exec(`DELETE FROM collections WHERE uid=$1 AND tag=$2;`, c.uid, tag)
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra < 1 {
+		// If the query has failed, makes sure that the user exists
+		if _, err := GetUser("UID", c.uid); err != nil {
+			return err
+		}
+
+		return ERR_COLLECTION_NOT_FOUND
+	}
+
+	return nil
+}
+
+// GetPublicCollection returns a collection given its code, with
+// all the recipes (without their RID and Code), ordered by name.
+// The collections of disabled users are not found.
+func GetPublicCollection(code string) (Collection, error) {
+	var UID int
+	var collection Collection
+
+	// Scans the collection
+	err := db.QueryRow(`SELECT c.uid, c.tag, c.code, u.username FROM collections c
+						INNER JOIN ca_users u ON u.uid=c.uid WHERE c.code=$1 AND NOT u.disabled;`, code).
+		Scan(&UID, &collection.Tag, &collection.Code, &collection.Author)
+	if errors.Is(err, sql.ErrNoRows) {
+		return collection, ERR_COLLECTION_NOT_FOUND
+	} else if err != nil {
+		return collection, ERR_UNKNOWN
+	}
+
+	// Gets the recipes
+	collection.Recipes, err = getCollectionRecipes(UID, collection.Tag)
+	for i := range collection.Recipes {
+		collection.Recipes[i].RID = 0
+		collection.Recipes[i].Code = nil
+	}
+
+	return collection, err
+}
+
+// getCollectionRecipes returns the full recipes of a user that
+// have a certain tag, ordered by name
+func getCollectionRecipes(UID int, tag string) ([]Recipe, error) {
+	var recipes []Recipe
+	var RIDs []int
+
+	// Queries the recipes
+	rows, err := db.Query(`SELECT r.rid FROM recipes r INNER JOIN tags t ON t.rid=r.rid
+						   WHERE r.uid=$1 AND t.name=$2 ORDER BY r.name;`, UID, tag)
+	if err != nil {
+		return recipes, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var RID int
+		rows.Scan(&RID)
+		RIDs = append(RIDs, RID)
+	}
+
+	// Gets their content
+	r := Recipes{uid: UID}
+	for _, RID := range RIDs {
+		recipe, err := r.GetOne(RID)
+		if err != nil {
+			return recipes, err
+		}
+
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, nil
+}
+
+// SaveCollection creates a copy of every recipe of a public collection
+// and returns their RIDs. The recipes with the same name of an existing
+// one are skipped, and their names are returned. The copies remember
+// where they come from (see GetFork). If any copy fails, nothing is saved.
+func (r Recipes) SaveCollection(code string) ([]int, []string, error) {
+	var RIDs []int
+	var skipped []string
+
+	// Ensures the user exists
+	if _, err := GetUser("UID", r.uid); err != nil {
+		return RIDs, skipped, err
+	}
+
+	// Gets the collection
+	var UID int
+	var tag, author string
+	err := db.QueryRow(`SELECT c.uid, c.tag, u.username FROM collections c
+						INNER JOIN ca_users u ON u.uid=c.uid WHERE c.code=$1 AND NOT u.disabled;`, code).
+		Scan(&UID, &tag, &author)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RIDs, skipped, ERR_COLLECTION_NOT_FOUND
+	} else if err != nil {
+		return RIDs, skipped, ERR_UNKNOWN
+	}
+
+	originals, err := getCollectionRecipes(UID, tag)
+	if err != nil {
+		return RIDs, skipped, err
+	}
+
+	// Creates the copies
+	tx, err := db.Begin()
+	if err != nil {
+		return RIDs, skipped, ERR_UNKNOWN
+	}
+	defer tx.Rollback()
+
+	for _, original := range originals {
+		copiedRID, err := r.copyRecipe(tx, original, original.RID, code, true, author)
+		if err == ERR_RECIPE_DUPLICATED {
+			skipped = append(skipped, original.Name)
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		RIDs = append(RIDs, copiedRID)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, ERR_UNKNOWN
+	}
+
+	return RIDs, skipped, nil
+}
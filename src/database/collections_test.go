@@ -0,0 +1,227 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollectionsPublish(t *testing.T) {
+	u, _ := getTestingUser(t)
+	RID, _ := u.Recipes().New("recipe")
+	u.Recipes().Edit(RID, Recipe{Name: "recipe", Tags: []string{"PASTA"}})
+
+	otherU, _ := getTestingUser(t)
+
+	// published is the code of the first publication
+	var published string
+
+	type data struct {
+		C           Collections
+		Tag         string
+		Republished bool
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			code, err := d.C.Publish(d.Tag)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if collection, _ := GetPublicCollection(code); collection.Tag != d.Tag {
+					t.Errorf("%s: collection not published", msg)
+				}
+
+				if d.Republished && code != published {
+					t.Errorf("%s: expected code <%s>, got <%s>", msg, published, code)
+				}
+				published = code
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user published collection",
+				data{C: unknownUser.Collections(), Tag: "PASTA", ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"other user published collection",
+				data{C: otherU.Collections(), Tag: "PASTA", ExpectedErr: ERR_TAG_NOT_FOUND},
+			},
+			{
+				"",
+				data{C: u.Collections(), Tag: "PASTA"},
+			},
+			{
+				"(republished)",
+				data{C: u.Collections(), Tag: "PASTA", Republished: true},
+			},
+		},
+	}.Run(t)
+}
+
+func TestCollectionsUnpublish(t *testing.T) {
+	u, _ := getTestingUser(t)
+	RID, _ := u.Recipes().New("recipe")
+	u.Recipes().Edit(RID, Recipe{Name: "recipe", Tags: []string{"PASTA"}})
+	code, _ := u.Collections().Publish("PASTA")
+
+	otherU, _ := getTestingUser(t)
+
+	type data struct {
+		C   Collections
+		Tag string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.C.Unpublish(d.Tag); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if _, err = GetPublicCollection(code); err != ERR_COLLECTION_NOT_FOUND {
+					t.Errorf("%s: collection still published", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user unpublished collection",
+				data{C: unknownUser.Collections(), Tag: "PASTA", ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"other user unpublished collection",
+				data{C: otherU.Collections(), Tag: "PASTA", ExpectedErr: ERR_COLLECTION_NOT_FOUND},
+			},
+			{
+				"",
+				data{C: u.Collections(), Tag: "PASTA"},
+			},
+		},
+	}.Run(t)
+}
+
+func TestGetPublicCollection(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID1, _ := r.New("b")
+	r.Edit(RID1, Recipe{Name: "b", Ingredients: "flour", Tags: []string{"PASTA"}})
+	RID2, _ := r.New("a")
+	r.Edit(RID2, Recipe{Name: "a", Ingredients: "eggs", Tags: []string{"PASTA", "QUICK"}})
+	RID3, _ := r.New("c")
+	r.Edit(RID3, Recipe{Name: "c", Tags: []string{"QUICK"}})
+	code, _ := u.Collections().Publish("PASTA")
+
+	type data struct {
+		Code string
+
+		ExpectedErr        error
+		ExpectedCollection Collection
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			collection, err := GetPublicCollection(d.Code)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if collection.Tag != d.ExpectedCollection.Tag || collection.Author != d.ExpectedCollection.Author {
+					t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedCollection, collection)
+				} else if len(collection.Recipes) != len(d.ExpectedCollection.Recipes) {
+					t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedCollection.Recipes, collection.Recipes)
+				} else {
+					for i := range collection.Recipes {
+						compareRecipes(t, msg, d.ExpectedCollection.Recipes[i], collection.Recipes[i])
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"got unknown collection",
+				data{Code: "unknown", ExpectedErr: ERR_COLLECTION_NOT_FOUND},
+			},
+			{
+				"",
+				data{
+					Code: code,
+					ExpectedCollection: Collection{
+						Tag:    "PASTA",
+						Author: u.Username,
+						Recipes: []Recipe{
+							{Name: "a", Ingredients: "eggs", Tags: []string{"PASTA", "QUICK"}},
+							{Name: "b", Ingredients: "flour", Tags: []string{"PASTA"}},
+						},
+					},
+				},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesSaveCollection(t *testing.T) {
+	ownerU, _ := getTestingUser(t)
+	ownerR := ownerU.Recipes()
+
+	RID1, _ := ownerR.New("a")
+	ownerR.Edit(RID1, Recipe{Name: "a", Ingredients: "eggs", Tags: []string{"PASTA"}})
+	RID2, _ := ownerR.New("b")
+	ownerR.Edit(RID2, Recipe{Name: "b", Ingredients: "flour", Tags: []string{"PASTA"}})
+	code, _ := ownerU.Collections().Publish("PASTA")
+
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+	r.New("a")
+
+	type data struct {
+		R    Recipes
+		Code string
+
+		ExpectedErr     error
+		ExpectedSaved   int
+		ExpectedSkipped []string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			RIDs, skipped, err := d.R.SaveCollection(d.Code)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if len(RIDs) != d.ExpectedSaved {
+					t.Errorf("%s: expected <%d> recipes, got <%d>", msg, d.ExpectedSaved, len(RIDs))
+				}
+
+				if !reflect.DeepEqual(skipped, d.ExpectedSkipped) {
+					t.Errorf("%s: expected skipped <%v>, got <%v>", msg, d.ExpectedSkipped, skipped)
+				}
+
+				for _, RID := range RIDs {
+					if fork, err := d.R.GetFork(RID); err != nil || !fork.Collection || fork.Code != d.Code {
+						t.Errorf("%s: attribution not saved", msg)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user saved collection",
+				data{Code: code, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"saved unknown collection",
+				data{R: r, Code: "unknown", ExpectedErr: ERR_COLLECTION_NOT_FOUND},
+			},
+			{
+				"(skipped duplicates)",
+				data{R: r, Code: code, ExpectedSaved: 1, ExpectedSkipped: []string{"a"}},
+			},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// CookableRecipe is a recipe that can be cooked (at least in part) with
+// the articles in the storage
+type CookableRecipe struct {
+	// Recipe contains only RID, Name and Stars
+	Recipe Recipe
+
+	// Available are the ingredients found in the storage
+	Available []string
+
+	// Missing are the ingredients not found in the storage
+	Missing []string
+
+	// Expiring are the names of the soon-to-expire articles used
+	// by the recipe
+	Expiring []string
+
+	// Score is used to rank the recipes: every available ingredient
+	// counts 1, or more if its article is about to expire
+	Score float64
+}
+
+// isIngredient returns false for the empty lines and for the
+// headers (like "For the sauce:") of a recipe's ingredients
+func isIngredient(line string) bool {
+	line = strings.TrimSpace(line)
+	return line != "" && !strings.HasSuffix(line, ":")
+}
+
+// GetCookable ranks the user's recipes by how many of their ingredients
+// are in the storage, giving more weight to the articles that expire within
+// expiringDays from today (the expired articles are ignored).
+// The recipes that use no article are not returned.
+func (r Recipes) GetCookable(today time.Time, expiringDays int) ([]CookableRecipe, error) {
+	var cookable []CookableRecipe
+
+	// Gets the recipes
+	recipes, err := r.getSuggestable()
+	if err != nil {
+		return cookable, err
+	}
+
+	// Gets the articles that have not expired yet (considering their
+	// effective expiration)
+	section, err := Storage{uid: r.uid}.GetArticles(0, "")
+	if err != nil {
+		return cookable, err
+	}
+	today = toDate(today)
+	limit := today.AddDate(0, 0, expiringDays)
+
+	var articles []Article
+	for _, a := range section.Articles {
+		if expires := a.Expires(); expires == nil || !expires.Before(today) {
+			articles = append(articles, a)
+		}
+	}
+
+	// Matches every ingredient with the articles
+	for _, recipe := range recipes {
+		cr := CookableRecipe{Recipe: Recipe{RID: recipe.RID, Name: recipe.Name, Stars: recipe.Stars}}
+
+		for _, line := range strings.Split(recipe.Ingredients, "\n") {
+			if !isIngredient(line) {
+				continue
+			}
+			line = strings.TrimSpace(line)
+
+			i := slices.IndexFunc(articles, func(a Article) bool { return ingredientUses(line, a.Name) })
+			if i < 0 {
+				cr.Missing = append(cr.Missing, line)
+				continue
+			}
+
+			cr.Available = append(cr.Available, line)
+			if a := articles[i]; a.Expires() != nil && !a.Expires().After(limit) {
+				cr.Score += expiringWeight
+				if !slices.Contains(cr.Expiring, a.Name) {
+					cr.Expiring = append(cr.Expiring, a.Name)
+				}
+			} else {
+				cr.Score++
+			}
+		}
+
+		if len(cr.Available) > 0 {
+			cookable = append(cookable, cr)
+		}
+	}
+
+	// Sorts them by score, then by fewer missing ingredients, then by stars
+	slices.SortStableFunc(cookable, func(a, b CookableRecipe) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		} else if len(a.Missing) != len(b.Missing) {
+			return len(a.Missing) - len(b.Missing)
+		}
+		return b.Recipe.Stars - a.Recipe.Stars
+	})
+
+	// If no recipes have been found, makes sure the user exists
+	if len(cookable) == 0 {
+		_, err := GetUser("UID", r.uid)
+		return cookable, err
+	}
+
+	return cookable, nil
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRecipesGetCookable(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID1, _ := r.New("Omelette")
+	r.Edit(RID1, Recipe{Name: "Omelette", Ingredients: "3 eggs\n50 g of cheese\nsalt"})
+	RID2, _ := r.New("Spinach pie")
+	r.Edit(RID2, Recipe{Name: "Spinach pie", Ingredients: "For the filling:\n200 g of spinach\n\n2 eggs"})
+	RID3, _ := r.New("Pizza")
+	r.Edit(RID3, Recipe{Name: "Pizza", Ingredients: "flour\ntomatoes"})
+	RID4, _ := r.New("Yogurt")
+	r.Edit(RID4, Recipe{Name: "Yogurt", Ingredients: "milk"})
+
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, dateLocale)
+	SID, _ := u.Storage().NewSection("Fridge")
+	u.Storage().AddArticles(
+		StringArticle{Name: "Eggs", Section: strconv.Itoa(SID)},
+		StringArticle{Name: "Cheese", Section: strconv.Itoa(SID), Expiration: "2025-04-10"},
+		StringArticle{Name: "Spinach", Section: strconv.Itoa(SID), Expiration: "2025-03-11"},
+		StringArticle{Name: "Milk", Section: strconv.Itoa(SID), Expiration: "2025-03-09"},
+	)
+
+	emptyU, _ := getTestingUser(t)
+
+	type data struct {
+		R Recipes
+
+		ExpectedErr      error
+		ExpectedCookable []CookableRecipe
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if got, err := d.R.GetCookable(today, 3); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil && !reflect.DeepEqual(got, d.ExpectedCookable) {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedCookable, got)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"got cookable recipes of unknown user",
+				data{R: unknownUser.Recipes(), ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"(empty)",
+				data{R: emptyU.Recipes()},
+			},
+			{
+				"",
+				data{R: r, ExpectedCookable: []CookableRecipe{
+					{
+						Recipe:    Recipe{RID: RID2, Name: "Spinach pie"},
+						Available: []string{"200 g of spinach", "2 eggs"},
+						Expiring:  []string{"Spinach"},
+						Score:     expiringWeight + 1,
+					},
+					{
+						Recipe:    Recipe{RID: RID1, Name: "Omelette"},
+						Available: []string{"3 eggs", "50 g of cheese"},
+						Missing:   []string{"salt"},
+						Score:     2,
+					},
+				}},
+			},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,219 @@
+package database
+
+import (
+	"database/sql"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Cooking is an entry of the cooking log of a recipe
+type Cooking struct {
+	// CID is the Cooking ID
+	CID int
+
+	// RID is the Recipe ID
+	RID int
+
+	// Date is when the recipe has been cooked
+	Date time.Time
+
+	// Cook is who cooked the recipe
+	Cook string
+
+	// Stars is the rating given that time (0 <= Stars <= 10, where
+	// every unit is half a star), or nil if it hasn't been rated
+	Stars *int
+
+	// Comments are some notes about that time
+	Comments string
+}
+
+// FormatDate returns the date as a string
+func (c Cooking) FormatDate() string {
+	return c.Date.Format(time.DateOnly)
+}
+
+// StringCooking is a container for date, cook, stars and comments
+// as strings, used for inputs
+type StringCooking struct {
+	Date     string
+	Cook     string
+	Stars    string
+	Comments string
+}
+
+// Parse converts a StringCooking into a Cooking. The date must be
+// formatted like 2004-02-05 (time.DateOnly), and the stars must be
+// a multiple of 0.5 between 0 and 5 (or empty, if it hasn't been rated).
+func (s StringCooking) Parse() (Cooking, error) {
+	var err error
+	cooking := Cooking{Cook: s.Cook, Comments: s.Comments}
+
+	// Parses the date
+	if cooking.Date, err = time.ParseInLocation(time.DateOnly, s.Date, dateLocale); err != nil {
+		return cooking, ERR_COOKING_DATE_INVALID
+	}
+
+	// Parses the stars
+	if s.Stars != "" {
+		stars, err := strconv.ParseFloat(s.Stars, 64)
+		if err != nil || math.IsNaN(stars) || stars < 0 || stars > 5 || stars*2 != math.Trunc(stars*2) {
+			return cooking, ERR_COOKING_STARS_INVALID
+		}
+
+		halves := int(stars * 2)
+		cooking.Stars = &halves
+	}
+
+	return cooking, nil
+}
+
+// ForgottenRecipe is a recipe that has not been cooked in a while
+type ForgottenRecipe struct {
+	// Recipe contains only RID and Name
+	Recipe Recipe
+
+	// LastCooked is the last time it has been cooked
+	LastCooked time.Time
+}
+
+// FormatLastCooked returns the last cooking date as a string
+func (fr ForgottenRecipe) FormatLastCooked() string {
+	return fr.LastCooked.Format(time.DateOnly)
+}
+
+// updateStars sets the stars of a recipe to the average rating
+// of its cooking log, or to 0 if no entry has been rated.
+// It must be called only when a rating has been added or removed.
+func updateStars(RID int) error {
+	_, err := db.Exec(`UPDATE recipes SET stars=COALESCE((SELECT ROUND(AVG(stars)) FROM cookings
+					   WHERE rid=$1 AND stars IS NOT NULL), 0) WHERE rid=$1;`, RID)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// AddCooking adds an entry to the cooking log of a recipe and returns
+// its CID. The recipe's stars are updated accordingly.
+func (r Recipes) AddCooking(RID int, cooking Cooking) (int, error) {
+	var CID int
+
+	// Ensures the recipe (and the user) exist
+	if _, err := r.GetOne(RID); err != nil {
+		return CID, err
+	}
+
+	// Ensures the date is set
+	if cooking.Date.IsZero() {
+		return CID, ERR_COOKING_DATE_INVALID
+	}
+
+	// Ensures the stars are correct
+	if cooking.Stars != nil && (*cooking.Stars < 0 || *cooking.Stars > 10) {
+		return CID, ERR_COOKING_STARS_INVALID
+	}
+
+	// Saves the entry
+	err := db.QueryRow(`INSERT INTO cookings (rid, date, cook, stars, comments)
+						VALUES ($1, $2, $3, $4, $5) RETURNING cid;`,
+		RID, cooking.Date, cooking.Cook, cooking.Stars, cooking.Comments).Scan(&CID)
+	if err != nil {
+		return CID, ERR_UNKNOWN
+	}
+
+	// Updates the stars, if it has been rated
+	if cooking.Stars == nil {
+		return CID, nil
+	}
+
+	return CID, updateStars(RID)
+}
+
+// DeleteCooking deletes an entry of the cooking log of a recipe.
+// The recipe's stars are updated accordingly.
+func (r Recipes) DeleteCooking(RID int, CID int) error {
+	// Ensures the recipe (and the user) exist
+	if _, err := r.GetOne(RID); err != nil {
+		return err
+	}
+
+	// Deletes the entry
+	var stars *int
+	err := db.QueryRow(`DELETE FROM cookings WHERE rid=$1 AND cid=$2 RETURNING stars;`, RID, CID).Scan(&stars)
+	if err == sql.ErrNoRows {
+		return ERR_COOKING_NOT_FOUND
+	} else if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	// Updates the stars, if it had been rated
+	if stars == nil {
+		return nil
+	}
+
+	return updateStars(RID)
+}
+
+// GetCookingLog returns the cooking log of a recipe, from the newest
+// entry to the oldest
+func (r Recipes) GetCookingLog(RID int) ([]Cooking, error) {
+	var log []Cooking
+
+	// Ensures the recipe (and the user) exist
+	if _, err := r.GetOne(RID); err != nil {
+		return log, err
+	}
+
+	// Queries the entries
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT cid, rid, date, cook, stars, comments FROM cookings
+						   WHERE rid=$1 ORDER BY date DESC, cid DESC;`, RID)
+	if err != nil {
+		return log, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var c Cooking
+		rows.Scan(&c.CID, &c.RID, &c.Date, &c.Cook, &c.Stars, &c.Comments)
+		log = append(log, c)
+	}
+
+	return log, nil
+}
+
+// GetForgotten returns (at most) limit recipes that have been cooked at least
+// once, but not in the last days, from the one cooked the longest ago
+func (r Recipes) GetForgotten(days int, limit int) ([]ForgottenRecipe, error) {
+	var recipes []ForgottenRecipe
+
+	// Queries the recipes
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT r.rid, r.name, MAX(c.date) AS last FROM recipes r
+						   INNER JOIN cookings c ON c.rid=r.rid WHERE r.uid=$1
+						   GROUP BY r.rid, r.name HAVING MAX(c.date) < CURRENT_DATE - $2::int
+						   ORDER BY last, r.name LIMIT $3;`, r.uid, days, limit)
+	if err != nil {
+		return recipes, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var fr ForgottenRecipe
+		rows.Scan(&fr.Recipe.RID, &fr.Recipe.Name, &fr.LastCooked)
+		recipes = append(recipes, fr)
+	}
+
+	// If no recipes have been found, makes sure the user exists
+	if len(recipes) == 0 {
+		_, err := GetUser("UID", r.uid)
+		return recipes, err
+	}
+
+	return recipes, nil
+}
@@ -0,0 +1,218 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStringCookingParse(t *testing.T) {
+	type data struct {
+		Date  string
+		Stars string
+
+		ExpectedErr   error
+		ExpectedStars *int
+	}
+
+	nine := 9
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			cooking, err := StringCooking{Date: d.Date, Stars: d.Stars}.Parse()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if (cooking.Stars == nil) != (d.ExpectedStars == nil) ||
+					(cooking.Stars != nil && *cooking.Stars != *d.ExpectedStars) {
+					t.Errorf("%s: expected stars <%v>, got <%v>", msg, d.ExpectedStars, cooking.Stars)
+				}
+
+				if expected := time.Date(2025, 3, 10, 0, 0, 0, 0, dateLocale); !cooking.Date.Equal(expected) {
+					t.Errorf("%s: expected date <%v>, got <%v>", msg, expected, cooking.Date)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"parsed invalid date", data{Date: "10/03/2025", ExpectedErr: ERR_COOKING_DATE_INVALID}},
+			{"parsed NaN stars", data{Date: "2025-03-10", Stars: "NaN", ExpectedErr: ERR_COOKING_STARS_INVALID}},
+			{"parsed infinite stars", data{Date: "2025-03-10", Stars: "Inf", ExpectedErr: ERR_COOKING_STARS_INVALID}},
+			{"parsed too many stars", data{Date: "2025-03-10", Stars: "6", ExpectedErr: ERR_COOKING_STARS_INVALID}},
+			{"parsed not half stars", data{Date: "2025-03-10", Stars: "4.9", ExpectedErr: ERR_COOKING_STARS_INVALID}},
+			{"(not rated)", data{Date: "2025-03-10"}},
+			{"(rated)", data{Date: "2025-03-10", Stars: "4.5", ExpectedStars: &nine}},
+		},
+	}.Run(t)
+}
+
+func TestRecipesAddCooking(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID, _ := r.New("recipe")
+
+	otherU, _ := getTestingUser(t)
+	otherR := otherU.Recipes()
+
+	today := time.Now()
+	four, seven, twenty := 4, 7, 20
+
+	type data struct {
+		R       Recipes
+		RID     int
+		Cooking Cooking
+
+		ExpectedErr   error
+		ExpectedStars int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if _, err := d.R.AddCooking(d.RID, d.Cooking); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if recipe, _ := d.R.GetOne(d.RID); recipe.Stars != d.ExpectedStars {
+					t.Errorf("%s: expected stars <%d>, got <%d>", msg, d.ExpectedStars, recipe.Stars)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user added cooking",
+				data{R: otherR, RID: RID, Cooking: Cooking{Date: today}, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"added cooking without date",
+				data{R: r, RID: RID, ExpectedErr: ERR_COOKING_DATE_INVALID},
+			},
+			{
+				"(not rated)",
+				data{R: r, RID: RID, Cooking: Cooking{Date: today, Cook: "me"}, ExpectedStars: 0},
+			},
+			{
+				"(rated)",
+				data{R: r, RID: RID, Cooking: Cooking{Date: today, Stars: &four}, ExpectedStars: 4},
+			},
+			{
+				"(average)",
+				data{R: r, RID: RID, Cooking: Cooking{Date: today, Stars: &seven}, ExpectedStars: 6},
+			},
+			{
+				"added cooking with too many stars",
+				data{R: r, RID: RID, Cooking: Cooking{Date: today, Stars: &twenty}, ExpectedErr: ERR_COOKING_STARS_INVALID},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesDeleteCooking(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	four, eight := 4, 8
+	RID, _ := r.New("recipe")
+	CID, _ := r.AddCooking(RID, Cooking{Date: time.Now(), Stars: &four})
+	lastCID, _ := r.AddCooking(RID, Cooking{Date: time.Now(), Stars: &eight})
+
+	otherU, _ := getTestingUser(t)
+	otherR := otherU.Recipes()
+
+	type data struct {
+		R   Recipes
+		RID int
+		CID int
+
+		ExpectedErr     error
+		ExpectedEntries int
+		ExpectedStars   int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.R.DeleteCooking(d.RID, d.CID); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if log, _ := d.R.GetCookingLog(d.RID); len(log) != d.ExpectedEntries {
+					t.Errorf("%s: expected <%d> entries, got <%d>", msg, d.ExpectedEntries, len(log))
+				}
+
+				if recipe, _ := d.R.GetOne(d.RID); recipe.Stars != d.ExpectedStars {
+					t.Errorf("%s: expected stars <%d>, got <%d>", msg, d.ExpectedStars, recipe.Stars)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user deleted cooking",
+				data{R: otherR, RID: RID, CID: CID, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"deleted unknown cooking",
+				data{R: r, RID: RID, CID: CID + 100, ExpectedErr: ERR_COOKING_NOT_FOUND},
+			},
+			{
+				"",
+				data{R: r, RID: RID, CID: CID, ExpectedEntries: 1, ExpectedStars: 8},
+			},
+			{
+				"(last rated)",
+				data{R: r, RID: RID, CID: lastCID, ExpectedStars: 0},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesGetForgotten(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID1, _ := r.New("recent")
+	r.AddCooking(RID1, Cooking{Date: time.Now()})
+	RID2, _ := r.New("old")
+	r.AddCooking(RID2, Cooking{Date: time.Now().AddDate(0, -2, 0)})
+	RID3, _ := r.New("older")
+	r.AddCooking(RID3, Cooking{Date: time.Now().AddDate(-1, 0, 0)})
+	r.AddCooking(RID3, Cooking{Date: time.Now().AddDate(0, -1, -5)})
+	r.New("never")
+
+	type data struct {
+		R     Recipes
+		Limit int
+
+		ExpectedErr error
+		ExpectedIDs []int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			forgotten, err := d.R.GetForgotten(30, d.Limit)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				var IDs []int
+				for _, fr := range forgotten {
+					IDs = append(IDs, fr.Recipe.RID)
+				}
+
+				compareIDs(t, msg, d.ExpectedIDs, IDs)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user got forgotten recipes",
+				data{R: unknownUser.Recipes(), Limit: 5, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"",
+				data{R: r, Limit: 5, ExpectedIDs: []int{RID2, RID3}},
+			},
+			{
+				"(limited)",
+				data{R: r, Limit: 1, ExpectedIDs: []int{RID2}},
+			},
+		},
+	}.Run(t)
+}
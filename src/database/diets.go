@@ -0,0 +1,145 @@
+package database
+
+import (
+	"database/sql"
+	"slices"
+
+	"github.com/lib/pq"
+)
+
+// Allergen is a group of ingredients that someone may have to avoid,
+// because of an allergy or a diet
+type Allergen string
+
+const (
+	ALLERGEN_CELERY      Allergen = "celery"
+	ALLERGEN_CRUSTACEANS Allergen = "crustaceans"
+	ALLERGEN_EGGS        Allergen = "eggs"
+	ALLERGEN_FISH        Allergen = "fish"
+	ALLERGEN_GLUTEN      Allergen = "gluten"
+	ALLERGEN_LUPIN       Allergen = "lupin"
+	ALLERGEN_MILK        Allergen = "milk"
+	ALLERGEN_MOLLUSCS    Allergen = "molluscs"
+	ALLERGEN_MUSTARD     Allergen = "mustard"
+	ALLERGEN_NUTS        Allergen = "nuts"
+	ALLERGEN_PEANUTS     Allergen = "peanuts"
+	ALLERGEN_SESAME      Allergen = "sesame"
+	ALLERGEN_SOY         Allergen = "soy"
+	ALLERGEN_SULPHITES   Allergen = "sulphites"
+
+	// These are not real allergens, but are needed by the diets
+	ALLERGEN_HONEY Allergen = "honey"
+	ALLERGEN_MEAT  Allergen = "meat"
+)
+
+// Allergens contains the allergens a user can choose to avoid
+// (the major allergens that must be declared in the EU)
+var Allergens = []Allergen{
+	ALLERGEN_CELERY, ALLERGEN_CRUSTACEANS, ALLERGEN_EGGS, ALLERGEN_FISH,
+	ALLERGEN_GLUTEN, ALLERGEN_LUPIN, ALLERGEN_MILK, ALLERGEN_MOLLUSCS,
+	ALLERGEN_MUSTARD, ALLERGEN_NUTS, ALLERGEN_PEANUTS, ALLERGEN_SESAME,
+	ALLERGEN_SOY, ALLERGEN_SULPHITES,
+}
+
+// Diet is a set of allergens that are avoided together
+type Diet string
+
+const (
+	DIET_VEGETARIAN Diet = "vegetarian"
+	DIET_VEGAN      Diet = "vegan"
+)
+
+// Diets contains, for every diet, the allergens it avoids
+var Diets = map[Diet][]Allergen{
+	DIET_VEGETARIAN: {ALLERGEN_MEAT, ALLERGEN_FISH, ALLERGEN_CRUSTACEANS, ALLERGEN_MOLLUSCS},
+	DIET_VEGAN: {ALLERGEN_MEAT, ALLERGEN_FISH, ALLERGEN_CRUSTACEANS, ALLERGEN_MOLLUSCS,
+		ALLERGEN_EGGS, ALLERGEN_MILK, ALLERGEN_HONEY},
+}
+
+// DietaryProfile contains the dietary restrictions of a user
+type DietaryProfile struct {
+	// Allergens are the allergens that must be avoided
+	Allergens []Allergen
+
+	// Diets are the diets that must be followed
+	Diets []Diet
+}
+
+// Avoided returns all the allergens that must be avoided,
+// including the ones of the diets
+func (dp DietaryProfile) Avoided() []Allergen {
+	avoided := slices.Clone(dp.Allergens)
+	for _, diet := range dp.Diets {
+		for _, a := range Diets[diet] {
+			if !slices.Contains(avoided, a) {
+				avoided = append(avoided, a)
+			}
+		}
+	}
+
+	return avoided
+}
+
+// GetDietaryProfile returns the user's dietary profile (which is
+// empty if it has never been set)
+func (u User) GetDietaryProfile() (DietaryProfile, error) {
+	var dp DietaryProfile
+	var allergens, diets []string
+
+	// Queries the profile
+	err := db.QueryRow(`SELECT allergens, diets FROM dietary_profiles WHERE uid=$1;`, u.UID).
+		Scan(pq.Array(&allergens), pq.Array(&diets))
+	if err == sql.ErrNoRows {
+		// Makes sure the user exists
+		_, err := GetUser("UID", u.UID)
+		return dp, err
+	} else if err != nil {
+		return dp, ERR_UNKNOWN
+	}
+
+	// Converts the values
+	for _, a := range allergens {
+		dp.Allergens = append(dp.Allergens, Allergen(a))
+	}
+	for _, d := range diets {
+		dp.Diets = append(dp.Diets, Diet(d))
+	}
+
+	return dp, nil
+}
+
+// SetDietaryProfile replaces the user's dietary profile
+func (u User) SetDietaryProfile(dp DietaryProfile) error {
+	var allergens, diets []string
+
+	// Ensures the user exists
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return err
+	}
+
+	// Ensures the values are valid
+	for _, a := range dp.Allergens {
+		if !slices.Contains(Allergens, a) {
+			return ERR_DIET_INVALID
+		} else if !slices.Contains(allergens, string(a)) {
+			allergens = append(allergens, string(a))
+		}
+	}
+	for _, d := range dp.Diets {
+		if _, found := Diets[d]; !found {
+			return ERR_DIET_INVALID
+		} else if !slices.Contains(diets, string(d)) {
+			diets = append(diets, string(d))
+		}
+	}
+
+	// Saves the profile
+	_, err := db.Exec(`INSERT INTO dietary_profiles (uid, allergens, diets) VALUES ($1, $2, $3)
+					   ON CONFLICT (uid) DO UPDATE SET allergens=EXCLUDED.allergens, diets=EXCLUDED.diets;`,
+		u.UID, pq.Array(allergens), pq.Array(diets))
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDietaryProfileAvoided(t *testing.T) {
+	dp := DietaryProfile{
+		Allergens: []Allergen{ALLERGEN_GLUTEN, ALLERGEN_FISH},
+		Diets:     []Diet{DIET_VEGETARIAN},
+	}
+
+	expected := []Allergen{ALLERGEN_GLUTEN, ALLERGEN_FISH, ALLERGEN_MEAT, ALLERGEN_CRUSTACEANS, ALLERGEN_MOLLUSCS}
+	if got := dp.Avoided(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected <%v>, got <%v>", expected, got)
+	}
+}
+
+func TestUserSetDietaryProfile(t *testing.T) {
+	u, _ := getTestingUser(t)
+
+	type data struct {
+		U       User
+		Profile DietaryProfile
+
+		ExpectedErr     error
+		ExpectedProfile DietaryProfile
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.SetDietaryProfile(d.Profile); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if got, _ := d.U.GetDietaryProfile(); !reflect.DeepEqual(got, d.ExpectedProfile) {
+					t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedProfile, got)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user set dietary profile",
+				data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"set unknown allergen",
+				data{U: u, Profile: DietaryProfile{Allergens: []Allergen{"chocolate"}}, ExpectedErr: ERR_DIET_INVALID},
+			},
+			{
+				"set diet-only allergen",
+				data{U: u, Profile: DietaryProfile{Allergens: []Allergen{ALLERGEN_MEAT}}, ExpectedErr: ERR_DIET_INVALID},
+			},
+			{
+				"set unknown diet",
+				data{U: u, Profile: DietaryProfile{Diets: []Diet{"carnivore"}}, ExpectedErr: ERR_DIET_INVALID},
+			},
+			{
+				"",
+				data{
+					U:               u,
+					Profile:         DietaryProfile{Allergens: []Allergen{ALLERGEN_GLUTEN, ALLERGEN_GLUTEN}, Diets: []Diet{DIET_VEGAN}},
+					ExpectedProfile: DietaryProfile{Allergens: []Allergen{ALLERGEN_GLUTEN}, Diets: []Diet{DIET_VEGAN}},
+				},
+			},
+			{
+				"(emptied)",
+				data{U: u},
+			},
+		},
+	}.Run(t)
+}
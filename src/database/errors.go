@@ -19,25 +19,69 @@ const (
 	ERR_USER_PASS_TOO_SHORT
 	ERR_USER_WRONG_CREDENTIALS
 	ERR_USER_WRONG_TOKEN
+	ERR_USER_TOKEN_EXPIRED
+	ERR_USER_MAIL_VERIFIED
+	ERR_USER_DISABLED
+	ERR_USER_SELF
+	ERR_DIET_INVALID
+	ERR_TOTP_ENABLED
+	ERR_TOTP_DISABLED
+	ERR_TOTP_WRONG_CODE
+	ERR_PASSKEY_NOT_FOUND
+	ERR_PASSKEY_INVALID
+	ERR_PASSKEY_DUPLICATED
+	ERR_PASSKEY_NAME_INVALID
+	ERR_SESSION_NOT_FOUND
+	ERR_IDENTITY_INVALID
+	ERR_IDENTITY_DUPLICATED
 
 	ERR_DAY_NOT_FOUND
 	ERR_DAY_NOT_MOVED
 	ERR_MEAL_NOT_FOUND
 	ERR_MEALS_NEGATIVE
+	ERR_SLOT_NOT_FOUND
+	ERR_SLOT_NAME_INVALID
+	ERR_MENU_START_INVALID
 	ERR_MENU_NOT_FOUND
+	ERR_SUGGEST_INVALID
+	ERR_TEMPLATE_NOT_FOUND
+	ERR_TEMPLATE_DUPLICATED
 
 	ERR_SECTION_DUPLICATED
 	ERR_SECTION_NOT_FOUND
+	ERR_SECTION_PARENT_INVALID
 	ERR_ARTICLE_NOT_FOUND
 	ERR_ARTICLE_QUANTITY_INVALID
 	ERR_ARTICLE_EXPIRATION_INVALID
 	ERR_ARTICLE_DUPLICATED
+	ERR_ARTICLE_CATEGORY_INVALID
+	ERR_ARTICLE_DATE_INVALID
+	ERR_STORAGE_TYPE_INVALID
+	ERR_SHELF_LIFE_INVALID
 
 	ERR_ENTRY_NOT_FOUND
 	ERR_ENTRY_DUPLICATED
 
 	ERR_RECIPE_NOT_FOUND
 	ERR_RECIPE_DUPLICATED
+	ERR_REVISION_NOT_FOUND
+	ERR_COOKING_NOT_FOUND
+	ERR_COOKING_DATE_INVALID
+	ERR_COOKING_STARS_INVALID
+	ERR_FORK_NOT_FOUND
+	ERR_FORK_ORIGIN_UNAVAILABLE
+	ERR_FORK_UP_TO_DATE
+	ERR_FORK_ORIGIN_CHANGED
+	ERR_TAG_NOT_FOUND
+	ERR_SHARE_NOT_FOUND
+	ERR_SHARE_LIMITS_INVALID
+
+	ERR_COLLECTION_NOT_FOUND
+	ERR_PROFILE_NOT_FOUND
+
+	ERR_NUTRIENTS_INVALID
+
+	ERR_CALENDAR_NOT_FOUND
 
 	ErrorsNumber int = iota
 )
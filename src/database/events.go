@@ -0,0 +1,134 @@
+package database
+
+import (
+	"time"
+)
+
+// maxEvents is the maximum number of events returned by GetEvents
+// and GetAllEvents
+const maxEvents = 100
+
+// SecurityEvent is something that has happened to an account
+type SecurityEvent string
+
+const (
+	EVENT_DELETED          SecurityEvent = "deleted"
+	EVENT_EMAIL_CHANGED    SecurityEvent = "email_changed"
+	EVENT_IDENTITY_LINKED  SecurityEvent = "identity_linked"
+	EVENT_PASSKEY_ADDED    SecurityEvent = "passkey_added"
+	EVENT_PASSKEY_DELETED  SecurityEvent = "passkey_deleted"
+	EVENT_PASSWORD_CHANGED SecurityEvent = "password_changed"
+	EVENT_PASSWORD_RESET   SecurityEvent = "password_reset"
+	EVENT_SESSION_REVOKED  SecurityEvent = "session_revoked"
+	EVENT_SIGNIN           SecurityEvent = "signin"
+	EVENT_SIGNIN_FAILED    SecurityEvent = "signin_failed"
+	EVENT_TOTP_DISABLED    SecurityEvent = "totp_disabled"
+	EVENT_TOTP_ENABLED     SecurityEvent = "totp_enabled"
+	EVENT_USERNAME_CHANGED SecurityEvent = "username_changed"
+)
+
+// Event is a SecurityEvent that has been recorded
+type Event struct {
+	// EID is the Event ID
+	EID int
+
+	// Username is the username the user had when the event happened
+	Username string
+
+	// Event is what has happened
+	Event SecurityEvent
+
+	// IP is the address the request came from
+	IP string
+
+	// UserAgent is the user agent of the device
+	UserAgent string
+
+	// Time is when the event happened
+	Time time.Time
+}
+
+// FormatTime returns the time of the event as a string
+func (e Event) FormatTime() string {
+	return e.Time.Format(time.DateTime)
+}
+
+// LogEvent records a security event of the user, with the IP address
+// and the user agent of the request that caused it. EVENT_DELETED is
+// logged after the user has been deleted, so only the Username field
+// is required and the event won't be linked to any user.
+func (u User) LogEvent(event SecurityEvent, IP string, userAgent string) error {
+	if event == EVENT_DELETED {
+		_, err := db.Exec(`INSERT INTO security_events (username, event, ip, user_agent) VALUES ($1, $2, $3, $4);`,
+			u.Username, event, IP, truncateUserAgent(userAgent))
+		if err != nil {
+			return ERR_UNKNOWN
+		}
+
+		return nil
+	}
+
+	res, err := db.Exec(`INSERT INTO security_events (uid, username, event, ip, user_agent)
+						 SELECT uid, username, $2, $3, $4 FROM ca_users WHERE uid=$1;`,
+		u.UID, event, IP, truncateUserAgent(userAgent))
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra < 1 {
+		return ERR_USER_UNKNOWN
+	}
+
+	return nil
+}
+
+// scanEvents reads the events returned by a query
+func scanEvents(query string, args ...any) ([]Event, error) {
+	var events []Event
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return events, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var e Event
+		rows.Scan(&e.EID, &e.Username, &e.Event, &e.IP, &e.UserAgent, &e.Time)
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// GetEvents returns the last security events of the user
+func (u User) GetEvents() ([]Event, error) {
+	events, err := scanEvents(`SELECT eid, username, event, ip, user_agent, time FROM security_events
+							   WHERE uid=$1 ORDER BY time DESC, eid DESC LIMIT $2;`, u.UID, maxEvents)
+	if err != nil {
+		return events, err
+	}
+
+	// If no events have been found, makes sure the user exists
+	if len(events) == 0 {
+		_, err := GetUser("UID", u.UID)
+		return events, err
+	}
+
+	return events, nil
+}
+
+// GetAllEvents returns the last security events of every user,
+// including the ones that have been deleted
+func GetAllEvents() ([]Event, error) {
+	return scanEvents(`SELECT eid, username, event, ip, user_agent, time FROM security_events
+					   ORDER BY time DESC, eid DESC LIMIT $1;`, maxEvents)
+}
+
+// CleanupEvents deletes the events older than the given number of days
+func CleanupEvents(retention int) error {
+	_, err := db.Exec(`DELETE FROM security_events WHERE time < NOW() - $1 * INTERVAL '1 day';`, retention)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
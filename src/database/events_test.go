@@ -0,0 +1,95 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestUserLogEvent(t *testing.T) {
+	user, _ := getTestingUser(t)
+	deleted, _ := getTestingUser(t)
+	db.Exec(`DELETE FROM ca_users WHERE uid=$1;`, deleted.UID)
+
+	type data struct {
+		U     User
+		Event SecurityEvent
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.LogEvent(d.Event, "127.0.0.1", "agent"); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				found := false
+				events, _ := GetAllEvents()
+				for _, e := range events {
+					found = found || (e.Username == d.U.Username && e.Event == d.Event)
+				}
+				if !found {
+					t.Errorf("%s: event not logged", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"logged event of unknown user", data{U: unknownUser, Event: EVENT_SIGNIN, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"", data{U: user, Event: EVENT_SIGNIN}},
+			{"(deleted)", data{U: deleted, Event: EVENT_DELETED}},
+		},
+	}.Run(t)
+}
+
+func TestUserGetEvents(t *testing.T) {
+	user, _ := getTestingUser(t)
+	user.LogEvent(EVENT_SIGNIN, "127.0.0.1", "agent")
+	user.LogEvent(EVENT_PASSWORD_CHANGED, "127.0.0.2", "other agent")
+	otherUser, _ := getTestingUser(t)
+
+	type data struct {
+		U User
+
+		Expected    []SecurityEvent
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			events, err := d.U.GetEvents()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if len(events) != len(d.Expected) {
+				t.Errorf("%s: expected <%d> events, got <%d>", msg, len(d.Expected), len(events))
+			} else {
+				for i, e := range events {
+					if e.Event != d.Expected[i] {
+						t.Errorf("%s: expected event <%s>, got <%s>", msg, d.Expected[i], e.Event)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"got events of unknown user", data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"(empty)", data{U: otherUser}},
+			{"", data{U: user, Expected: []SecurityEvent{EVENT_PASSWORD_CHANGED, EVENT_SIGNIN}}},
+		},
+	}.Run(t)
+}
+
+func TestCleanupEvents(t *testing.T) {
+	user, _ := getTestingUser(t)
+	user.LogEvent(EVENT_SIGNIN, "127.0.0.1", "agent")
+	user.LogEvent(EVENT_SIGNIN_FAILED, "127.0.0.1", "agent")
+	db.Exec(`UPDATE security_events SET time=NOW() - INTERVAL '10 days' WHERE uid=$1 AND event=$2;`,
+		user.UID, EVENT_SIGNIN)
+
+	if err := CleanupEvents(5); err != nil {
+		t.Fatalf("expected err <nil>, got <%v>", err)
+	}
+
+	events, _ := user.GetEvents()
+	if len(events) != 1 || events[0].Event != EVENT_SIGNIN_FAILED {
+		t.Errorf("expected only the recent event, got <%+v>", events)
+	}
+}
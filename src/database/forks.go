@@ -0,0 +1,296 @@
+package database
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Fork contains the attribution of a recipe saved from a shared one
+type Fork struct {
+	// RID is the Recipe ID of the copy
+	RID int
+
+	// Code is the share code from which the recipe has been saved
+	Code string
+
+	// Collection is true if Code belongs to a collection instead
+	// of a single recipe
+	Collection bool
+
+	// Author is the username of the original recipe's owner
+	Author string
+
+	// Outdated is true if the original recipe has been edited
+	// since it has been saved (or since the last pull)
+	Outdated bool
+}
+
+// ForkUpdate is the result of merging the changes made to the original
+// recipe with the ones made to the copy
+type ForkUpdate struct {
+	// Fork is the copy's attribution
+	Fork Fork
+
+	// Ingredients are the merged ingredients
+	Ingredients string
+
+	// Directions are the merged directions
+	Directions string
+
+	// Conflicts is true if both the original and the copy changed the
+	// same lines. In that case, both versions are kept.
+	Conflicts bool
+
+	// Version identifies the content of the original recipe that
+	// has been merged (see PullFork)
+	Version string
+}
+
+// forkVersion returns the version of an original recipe's content
+func forkVersion(ingredients string, directions string) string {
+	hash := sha256.Sum256([]byte(ingredients + "\x00" + directions))
+	return hex.EncodeToString(hash[:])
+}
+
+// lcsMatches returns the lines of a matched in the longest common
+// subsequence with b, as a map from a's indexes to b's ones
+func lcsMatches(a, b []string) map[int]int {
+	matches := make(map[int]int)
+	table := lcsTable(a, b)
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			matches[i] = j
+			i++
+			j++
+		} else if table[i+1][j] >= table[i][j+1] {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return matches
+}
+
+// mergeLines makes a three-way merge of two texts (local and remote) that
+// have a common ancestor (base). If both of them changed the same lines, the
+// local ones are kept, followed by the remote ones, and conflicts is true.
+func mergeLines(base, local, remote string) (merged string, conflicts bool) {
+	var result []string
+
+	o, a, b := splitLines(base), splitLines(local), splitLines(remote)
+	ma, mb := lcsMatches(o, a), lcsMatches(o, b)
+
+	i, j, k := 0, 0, 0
+	for {
+		// Finds the next base line that has been kept in both texts
+		n := i
+		for ; n < len(o); n++ {
+			_, inA := ma[n]
+			_, inB := mb[n]
+			if inA && inB {
+				break
+			}
+		}
+
+		// Finds where that line is in the two texts
+		na, nb := len(a), len(b)
+		if n < len(o) {
+			na, nb = ma[n], mb[n]
+		}
+
+		// Merges the chunks before that line
+		chunkO, chunkA, chunkB := o[i:n], a[j:na], b[k:nb]
+		if slices.Equal(chunkA, chunkO) {
+			result = append(result, chunkB...)
+		} else if slices.Equal(chunkB, chunkO) || slices.Equal(chunkA, chunkB) {
+			result = append(result, chunkA...)
+		} else {
+			conflicts = true
+			result = append(result, chunkA...)
+			result = append(result, chunkB...)
+		}
+
+		// Stops if there are no more common lines
+		if n == len(o) {
+			break
+		}
+
+		result = append(result, o[n])
+		i, j, k = n+1, na+1, nb+1
+	}
+
+	return strings.Join(result, "\n"), conflicts
+}
+
+// originPublic is the SQL condition that is true if the original
+// recipe o is still public (shared or in a published collection)
+// and its owner has not been disabled
+const originPublic = `(NOT EXISTS (SELECT 1 FROM ca_users ou WHERE ou.uid=o.uid AND ou.disabled)
+					  AND (EXISTS (SELECT 1 FROM shares s WHERE s.code=o.code AND ` + shareActive + `)
+					  OR EXISTS (SELECT 1 FROM collections c INNER JOIN tags t
+					  ON t.name=c.tag AND t.rid=o.rid WHERE c.uid=o.uid)))`
+
+// addFork saves the attribution of a copy
+func addFork(tx *sql.Tx, RID int, originRID int, code string, collection bool, author string, original Recipe) error {
+	_, err := tx.Exec(`INSERT INTO forks (rid, origin, code, collection, author, ingredients, directions)
+					   VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+		RID, originRID, code, collection, author, original.Ingredients, original.Directions)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// getOrigin returns the RID and the owner's username of a shared recipe
+func getOrigin(code string) (RID int, author string, err error) {
+	err = db.QueryRow(`SELECT r.rid, u.username FROM recipes r
+					   INNER JOIN ca_users u ON u.uid=r.uid WHERE r.code=$1;`, code).
+		Scan(&RID, &author)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = ERR_RECIPE_NOT_FOUND
+	} else if err != nil {
+		err = ERR_UNKNOWN
+	}
+
+	return
+}
+
+// GetFork returns the attribution of a recipe
+func (r Recipes) GetFork(RID int) (Fork, error) {
+	var fork Fork
+
+	// Ensures the recipe (and the user) exist
+	if _, err := r.GetOne(RID); err != nil {
+		return fork, err
+	}
+
+	// Scans the fork, checking if the original has been edited
+	err := db.QueryRow(`SELECT f.rid, f.code, f.collection, f.author, COALESCE(`+originPublic+` AND
+						(o.ingredients != f.ingredients OR o.directions != f.directions), FALSE)
+						FROM forks f LEFT JOIN recipes o ON o.rid=f.origin WHERE f.rid=$1;`, RID).
+		Scan(&fork.RID, &fork.Code, &fork.Collection, &fork.Author, &fork.Outdated)
+	if err != nil {
+		return fork, handleNoRowsError(err, r.uid, ERR_FORK_NOT_FOUND)
+	}
+
+	return fork, nil
+}
+
+// GetForkUpdate merges the changes made to the original recipe since
+// it has been saved (or since the last pull) with the ones made to the copy.
+// Only the ingredients and the directions are merged.
+// Nothing is saved: see PullFork.
+func (r Recipes) GetForkUpdate(RID int) (ForkUpdate, error) {
+	var update ForkUpdate
+	var err error
+
+	// Gets the fork
+	if update.Fork, err = r.GetFork(RID); err != nil {
+		return update, err
+	}
+
+	// Gets the copy
+	local, err := r.GetOne(RID)
+	if err != nil {
+		return update, err
+	}
+
+	// Gets the original and the common ancestor
+	var baseIngredients, baseDirections, remoteIngredients, remoteDirections string
+	err = db.QueryRow(`SELECT f.ingredients, f.directions, o.ingredients, o.directions
+					   FROM forks f INNER JOIN recipes o ON o.rid=f.origin
+					   WHERE f.rid=$1 AND `+originPublic+`;`, RID).
+		Scan(&baseIngredients, &baseDirections, &remoteIngredients, &remoteDirections)
+	if errors.Is(err, sql.ErrNoRows) {
+		return update, ERR_FORK_ORIGIN_UNAVAILABLE
+	} else if err != nil {
+		return update, ERR_UNKNOWN
+	}
+
+	// Ensures the original has been edited
+	if !update.Fork.Outdated {
+		return update, ERR_FORK_UP_TO_DATE
+	}
+
+	// Merges them
+	var c1, c2 bool
+	update.Ingredients, c1 = mergeLines(baseIngredients, local.Ingredients, remoteIngredients)
+	update.Directions, c2 = mergeLines(baseDirections, local.Directions, remoteDirections)
+	update.Conflicts = c1 || c2
+	update.Version = forkVersion(remoteIngredients, remoteDirections)
+	return update, nil
+}
+
+// PullFork replaces the ingredients and the directions of the copy (usually
+// with the ones returned by GetForkUpdate), then marks the original recipe
+// as the new common ancestor. The version must be the one returned by
+// GetForkUpdate: if the original has been edited since then,
+// ERR_FORK_ORIGIN_CHANGED is returned and nothing is saved.
+func (r Recipes) PullFork(RID int, version string, ingredients string, directions string) error {
+	// Ensures the fork (and the recipe) exist
+	if _, err := r.GetFork(RID); err != nil {
+		return err
+	}
+
+	// Gets the copy
+	recipe, err := r.GetOne(RID)
+	if err != nil {
+		return err
+	}
+
+	// Saves the copy and the common ancestor together
+	tx, err := db.Begin()
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+	defer tx.Rollback()
+
+	// Gets the original, locking the fork until the end
+	var remoteIngredients, remoteDirections string
+	var outdated bool
+	err = tx.QueryRow(`SELECT o.ingredients, o.directions, o.ingredients != f.ingredients OR o.directions != f.directions
+					   FROM forks f INNER JOIN recipes o ON o.rid=f.origin
+					   WHERE f.rid=$1 AND `+originPublic+` FOR UPDATE OF f;`, RID).
+		Scan(&remoteIngredients, &remoteDirections, &outdated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ERR_FORK_ORIGIN_UNAVAILABLE
+	} else if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	// Ensures there is something to pull, and that it is what has been merged
+	if !outdated {
+		return ERR_FORK_UP_TO_DATE
+	} else if forkVersion(remoteIngredients, remoteDirections) != version {
+		return ERR_FORK_ORIGIN_CHANGED
+	}
+
+	// Saves the new content
+	updated := recipe
+	updated.Ingredients = ingredients
+	updated.Directions = directions
+	if err = edit(tx, recipe, updated); err != nil {
+		return err
+	}
+
+	// Updates the common ancestor
+	_, err = tx.Exec(`UPDATE forks SET ingredients=$2, directions=$3 WHERE rid=$1;`,
+		RID, remoteIngredients, remoteDirections)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	if err = tx.Commit(); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestMergeLines(t *testing.T) {
+	type data struct {
+		Base   string
+		Local  string
+		Remote string
+
+		Expected          string
+		ExpectedConflicts bool
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			got, conflicts := mergeLines(d.Base, d.Local, d.Remote)
+			if got != d.Expected {
+				t.Errorf("%s: expected <%q>, got <%q>", msg, d.Expected, got)
+			}
+
+			if conflicts != d.ExpectedConflicts {
+				t.Errorf("%s: expected conflicts <%v>, got <%v>", msg, d.ExpectedConflicts, conflicts)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"(empty)",
+				data{},
+			},
+			{
+				"(only remote changed)",
+				data{Base: "a\nb\nc", Local: "a\nb\nc", Remote: "a\nx\nc", Expected: "a\nx\nc"},
+			},
+			{
+				"(only local changed)",
+				data{Base: "a\nb\nc", Local: "a\nc", Remote: "a\nb\nc", Expected: "a\nc"},
+			},
+			{
+				"(different lines changed)",
+				data{Base: "a\nb\nc", Local: "a\nb\nc\nd", Remote: "z\na\nb\nc", Expected: "z\na\nb\nc\nd"},
+			},
+			{
+				"(same change)",
+				data{Base: "a\nb", Local: "a\nx", Remote: "a\nx", Expected: "a\nx"},
+			},
+			{
+				"(conflict)",
+				data{Base: "a\nb\nc", Local: "a\ny\nc", Remote: "a\nx\nc", Expected: "a\ny\nx\nc", ExpectedConflicts: true},
+			},
+			{
+				"(conflict with repeated lines)",
+				data{Base: "a\nb\nc", Local: "a\ny\nc", Remote: "a\ny\ny\nc", Expected: "a\ny\ny\ny\nc", ExpectedConflicts: true},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesGetFork(t *testing.T) {
+	ownerU, _ := getTestingUser(t)
+	ownerR := ownerU.Recipes()
+
+	originRID, _ := ownerR.New("recipe")
+	ownerR.Edit(originRID, Recipe{Name: "recipe", Ingredients: "flour", Directions: "Mix"})
+	code, _ := ownerR.Share(originRID)
+
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+	RID, _ := r.Save(code)
+	otherRID, _ := r.New("other")
+
+	u2, _ := getTestingUser(t)
+	r2 := u2.Recipes()
+	outdatedRID, _ := r2.Save(code)
+	ownerR.Edit(originRID, Recipe{Name: "recipe", Ingredients: "flour\nwater", Directions: "Mix"})
+
+	type data struct {
+		R   Recipes
+		RID int
+
+		ExpectedErr  error
+		ExpectedFork Fork
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			fork, err := d.R.GetFork(d.RID)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil && fork != d.ExpectedFork {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedFork, fork)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user got fork",
+				data{RID: RID, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"other user got fork",
+				data{R: r2, RID: RID, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"got fork of unsaved recipe",
+				data{R: r, RID: otherRID, ExpectedErr: ERR_FORK_NOT_FOUND},
+			},
+			{
+				"(outdated)",
+				data{R: r2, RID: outdatedRID, ExpectedFork: Fork{RID: outdatedRID, Code: code, Author: ownerU.Username, Outdated: true}},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesGetForkUpdate(t *testing.T) {
+	ownerU, _ := getTestingUser(t)
+	ownerR := ownerU.Recipes()
+
+	originRID, _ := ownerR.New("recipe")
+	ownerR.Edit(originRID, Recipe{Name: "recipe", Ingredients: "flour\nwater", Directions: "Mix\nBake"})
+	code, _ := ownerR.Share(originRID)
+
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+	RID, _ := r.Save(code)
+	r.Edit(RID, Recipe{Name: "recipe", Ingredients: "flour\nwater\nsalt", Directions: "Mix\nBake"})
+
+	ownerR.Edit(originRID, Recipe{Name: "recipe", Ingredients: "flour\nwater", Directions: "Mix\nBake at 200°C"})
+
+	u2, _ := getTestingUser(t)
+	r2 := u2.Recipes()
+	unsharedRID, _ := r2.Save(code)
+
+	unsharedOriginRID, _ := ownerR.New("unshared")
+	ownerR.Edit(unsharedOriginRID, Recipe{Name: "unshared", Ingredients: "eggs"})
+	unsharedCode, _ := ownerR.Share(unsharedOriginRID)
+	unavailableRID, _ := r2.Save(unsharedCode)
+	ownerR.Edit(unsharedOriginRID, Recipe{Name: "unshared", Ingredients: "eggs\nmilk"})
+	ownerR.Unshare(unsharedOriginRID)
+
+	type data struct {
+		R   Recipes
+		RID int
+
+		ExpectedErr         error
+		ExpectedIngredients string
+		ExpectedDirections  string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			update, err := d.R.GetForkUpdate(d.RID)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if update.Ingredients != d.ExpectedIngredients || update.Directions != d.ExpectedDirections {
+					t.Errorf("%s: expected <%q, %q>, got <%q, %q>", msg, d.ExpectedIngredients,
+						d.ExpectedDirections, update.Ingredients, update.Directions)
+				}
+
+				if update.Conflicts {
+					t.Errorf("%s: unexpected conflicts", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user got update",
+				data{R: r2, RID: RID, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"got update of up to date fork",
+				data{R: r2, RID: unsharedRID, ExpectedErr: ERR_FORK_UP_TO_DATE},
+			},
+			{
+				"got update of unshared origin",
+				data{R: r2, RID: unavailableRID, ExpectedErr: ERR_FORK_ORIGIN_UNAVAILABLE},
+			},
+			{
+				"",
+				data{R: r, RID: RID, ExpectedIngredients: "flour\nwater\nsalt", ExpectedDirections: "Mix\nBake at 200°C"},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesPullFork(t *testing.T) {
+	ownerU, _ := getTestingUser(t)
+	ownerR := ownerU.Recipes()
+
+	originRID, _ := ownerR.New("recipe")
+	ownerR.Edit(originRID, Recipe{Name: "recipe", Ingredients: "flour", Directions: "Mix"})
+	code, _ := ownerR.Share(originRID)
+
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+	RID, _ := r.Save(code)
+	ownerR.Edit(originRID, Recipe{Name: "recipe", Ingredients: "flour\nwater", Directions: "Mix"})
+	stale, _ := r.GetForkUpdate(RID)
+	ownerR.Edit(originRID, Recipe{Name: "recipe", Ingredients: "flour\nwater\noil", Directions: "Mix"})
+	update, _ := r.GetForkUpdate(RID)
+
+	type data struct {
+		R       Recipes
+		RID     int
+		Version string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			err := d.R.PullFork(d.RID, d.Version, "flour\nwater\nsalt", "Mix")
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				got, _ := d.R.GetOne(d.RID)
+				expected := Recipe{RID: d.RID, Name: "recipe", Ingredients: "flour\nwater\nsalt", Directions: "Mix"}
+				compareRecipes(t, msg, expected, got)
+
+				if fork, _ := d.R.GetFork(d.RID); fork.Outdated {
+					t.Errorf("%s: fork still outdated", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user pulled fork",
+				data{R: ownerR, RID: RID, Version: update.Version, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"pulled edited origin",
+				data{R: r, RID: RID, Version: stale.Version, ExpectedErr: ERR_FORK_ORIGIN_CHANGED},
+			},
+			{
+				"",
+				data{R: r, RID: RID, Version: update.Version},
+			},
+			{
+				"pulled up to date fork",
+				data{R: r, RID: RID, Version: update.Version, ExpectedErr: ERR_FORK_UP_TO_DATE},
+			},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// linkIdentity links an identity of an OpenID Connect provider to the user
+func (u User) linkIdentity(issuer string, subject string) error {
+	_, err := db.Exec(`INSERT INTO user_identities (uid, issuer, subject) VALUES ($1, $2, $3);`, u.UID, issuer, subject)
+	if err != nil {
+		if pqe, ok := err.(*pq.Error); ok && pqe.Code == "23505" {
+			return ERR_IDENTITY_DUPLICATED
+		} else {
+			return ERR_UNKNOWN
+		}
+	}
+
+	return nil
+}
+
+// LinkIdentity links an identity of an OpenID Connect provider
+// to the user, so that it can be used to sign in
+func (u User) LinkIdentity(issuer string, subject string) error {
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return err
+	} else if subject == "" {
+		return ERR_IDENTITY_INVALID
+	}
+
+	return u.linkIdentity(issuer, subject)
+}
+
+// HasIdentity returns true if the user has linked an identity
+// of the given provider
+func (u User) HasIdentity(issuer string) (bool, error) {
+	var found bool
+
+	err := db.QueryRow(`SELECT TRUE FROM user_identities WHERE uid=$1 AND issuer=$2;`, u.UID, issuer).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, handleNoRowsError(err, u.UID, nil)
+	} else if err != nil {
+		return false, ERR_UNKNOWN
+	}
+
+	return true, nil
+}
+
+// identityUsername returns an available username for a new user,
+// based on the one suggested by the provider or on the email
+func identityUsername(suggested string, email string) string {
+	base := strings.TrimSpace(suggested)
+	if base == "" {
+		base, _, _ = strings.Cut(email, "@")
+	}
+	if len(base) > 240 {
+		base = base[:240]
+	}
+
+	username := base
+	for n := 1; checkUsername(username) != nil; n++ {
+		username = fmt.Sprintf("%s%d", base, n)
+		for len(username) < 5 {
+			username += "0"
+		}
+	}
+
+	return username
+}
+
+// SignInWithIdentity returns the user an identity of an OpenID Connect
+// provider is linked to. If it's not linked yet, it's linked to the user
+// with the same email (only if both the provider and the user have verified
+// it, otherwise the user has to sign in and link it by hand), or to a new
+// user. In the last case, created is true.
+func SignInWithIdentity(issuer string, subject string, email string, emailVerified bool, username string) (user User, created bool, err error) {
+	var UID int
+
+	if subject == "" {
+		return user, false, ERR_IDENTITY_INVALID
+	}
+
+	// Looks for the linked user
+	err = db.QueryRow(`SELECT uid FROM user_identities WHERE issuer=$1 AND subject=$2;`, issuer, subject).Scan(&UID)
+	if err == nil {
+		if user, err = GetUser("UID", UID); err == nil && user.Disabled {
+			return User{}, false, ERR_USER_DISABLED
+		}
+		return user, false, err
+	} else if err != sql.ErrNoRows {
+		return user, false, ERR_UNKNOWN
+	}
+
+	// Looks for a user with the same email
+	if _, errM := mail.ParseAddress(email); errM != nil {
+		return user, false, ERR_IDENTITY_INVALID
+	} else if user, err = GetUser("email", email); err == nil {
+		// Both sides must have verified the email, otherwise whoever
+		// has registered it first could take over the other account
+		if !emailVerified || !user.EmailVerified {
+			return User{}, false, ERR_USER_MAIL_UNAVAIL
+		} else if user.Disabled {
+			return User{}, false, ERR_USER_DISABLED
+		}
+
+		if err = user.linkIdentity(issuer, subject); err != nil {
+			return User{}, false, err
+		}
+
+		return user, false, nil
+	} else if err != ERR_USER_UNKNOWN {
+		return user, false, err
+	}
+
+	// Creates a new user, with a random password that nobody knows
+	_, hash, err := generateUserToken()
+	if err != nil {
+		return User{}, false, err
+	}
+	_, err = db.Exec(`INSERT INTO ca_users (username, email, password, newsletter, email_verified) VALUES ($1, $2, $3, $4, $5);`,
+		identityUsername(username, email), email, hash, generateNewsletterToken(), emailVerified)
+	if err != nil {
+		return User{}, false, ERR_UNKNOWN
+	}
+
+	// Links the identity
+	if user, err = GetUser("email", email); err != nil {
+		return User{}, false, err
+	} else if err = user.linkIdentity(issuer, subject); err != nil {
+		return User{}, false, err
+	}
+
+	return user, true, nil
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+const testIssuer = "https://issuer.com"
+
+var testingSubjectsN int = 0
+
+// getTestingSubject returns an unused subject
+func getTestingSubject() string {
+	testingSubjectsN++
+	return fmt.Sprintf("subject%d", testingSubjectsN)
+}
+
+func TestSignInWithIdentity(t *testing.T) {
+	existing, _ := getTestingUser(t)
+	VerifyEmail(getVerificationToken(t, existing, time.Now()), time.Now())
+	unverified, _ := getTestingUser(t)
+	unverifiedLocal, _ := getTestingUser(t)
+	linked, _ := getTestingUser(t)
+	linkedSubject := getTestingSubject()
+	linked.LinkIdentity(testIssuer, linkedSubject)
+	newEmail := generateTestingUser().Email
+	newSubject := getTestingSubject()
+
+	type data struct {
+		Subject       string
+		Email         string
+		EmailVerified bool
+
+		ExpectedUID     int
+		ExpectedCreated bool
+		ExpectedErr     error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			user, created, err := SignInWithIdentity(testIssuer, d.Subject, d.Email, d.EmailVerified, "user")
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if created != d.ExpectedCreated {
+					t.Errorf("%s: expected created <%v>, got <%v>", msg, d.ExpectedCreated, created)
+				} else if d.ExpectedUID != 0 && user.UID != d.ExpectedUID {
+					t.Errorf("%s: expected user <%d>, got <%d>", msg, d.ExpectedUID, user.UID)
+				} else if user.Email != d.Email && d.ExpectedUID != linked.UID {
+					t.Errorf("%s: expected email <%s>, got <%s>", msg, d.Email, user.Email)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"signed in without subject", data{Email: newEmail, ExpectedErr: ERR_IDENTITY_INVALID}},
+			{"signed in without email", data{Subject: getTestingSubject(), ExpectedErr: ERR_IDENTITY_INVALID}},
+			{"signed in with unverified email of another user", data{Subject: getTestingSubject(), Email: unverified.Email, ExpectedErr: ERR_USER_MAIL_UNAVAIL}},
+			{"signed in with email of unverified user", data{Subject: getTestingSubject(), Email: unverifiedLocal.Email, EmailVerified: true, ExpectedErr: ERR_USER_MAIL_UNAVAIL}},
+			{"(linked)", data{Subject: linkedSubject, ExpectedUID: linked.UID}},
+			{"(existing user)", data{Subject: getTestingSubject(), Email: existing.Email, EmailVerified: true, ExpectedUID: existing.UID}},
+			{"(new user)", data{Subject: newSubject, Email: newEmail, ExpectedCreated: true}},
+			{"(new user again)", data{Subject: newSubject, Email: newEmail}},
+		},
+	}.Run(t)
+}
+
+func TestUserLinkIdentity(t *testing.T) {
+	user, _ := getTestingUser(t)
+	otherUser, _ := getTestingUser(t)
+	subject := getTestingSubject()
+
+	type data struct {
+		U       User
+		Subject string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.LinkIdentity(testIssuer, d.Subject); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if linked, _ := d.U.HasIdentity(testIssuer); !linked {
+					t.Errorf("%s: identity not linked", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"linked identity to unknown user", data{U: unknownUser, Subject: subject, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"linked identity without subject", data{U: user, ExpectedErr: ERR_IDENTITY_INVALID}},
+			{"", data{U: user, Subject: subject}},
+			{"linked identity to two users", data{U: otherUser, Subject: subject, ExpectedErr: ERR_IDENTITY_DUPLICATED}},
+		},
+	}.Run(t)
+}
+
+func TestUserHasIdentity(t *testing.T) {
+	user, _ := getTestingUser(t)
+	user.LinkIdentity(testIssuer, getTestingSubject())
+	otherUser, _ := getTestingUser(t)
+
+	type data struct {
+		U      User
+		Issuer string
+
+		Expected    bool
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if got, err := d.U.HasIdentity(d.Issuer); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if got != d.Expected {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.Expected, got)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"checked identity of unknown user", data{U: unknownUser, Issuer: testIssuer, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"(not linked)", data{U: otherUser, Issuer: testIssuer}},
+			{"(another issuer)", data{U: user, Issuer: "https://other.com"}},
+			{"", data{U: user, Issuer: testIssuer, Expected: true}},
+		},
+	}.Run(t)
+}
+
+func TestIdentityUsername(t *testing.T) {
+	user, _ := getTestingUser(t)
+
+	type data struct {
+		Suggested string
+		Email     string
+
+		Expected string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if got := identityUsername(d.Suggested, d.Email); got != d.Expected {
+				t.Errorf("%s: expected <%s>, got <%s>", msg, d.Expected, got)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"(suggested)", data{Suggested: "newusername", Expected: "newusername"}},
+			{"(from email)", data{Email: "newemailuser@email.com", Expected: "newemailuser"}},
+			{"(too short)", data{Suggested: "abc", Expected: "abc10"}},
+			{"(unavailable)", data{Suggested: user.Username, Expected: user.Username + "1"}},
+		},
+	}.Run(t)
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"cucinassistant/configs"
 )
@@ -53,6 +54,29 @@ func Connect() *sql.DB {
 	return db
 }
 
+// StartCleanup periodically deletes the expired data,
+// waiting the given interval between each run
+func StartCleanup(interval time.Duration) {
+	go func() {
+		for {
+			if err := CleanupTokens(); err != nil {
+				slog.Error("while cleaning up tokens:", "err", err)
+			}
+			if err := CleanupSessions(); err != nil {
+				slog.Error("while cleaning up sessions:", "err", err)
+			}
+			if err := CleanupEvents(configs.EventsRetention); err != nil {
+				slog.Error("while cleaning up events:", "err", err)
+			}
+			if err := SaveStats(); err != nil {
+				slog.Error("while saving stats:", "err", err)
+			}
+
+			time.Sleep(interval)
+		}
+	}()
+}
+
 // Makes sure the database has the most recent schema
 func Check() {
 	var version int
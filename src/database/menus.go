@@ -2,6 +2,11 @@ package database
 
 import (
 	"database/sql"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/lib/pq"
 )
 
@@ -13,10 +18,23 @@ type Menu struct {
 	// Name is the name of the menu
 	Name string
 
+	// Start is the date of the first day, if the menu
+	// is anchored to the calendar (nil otherwise)
+	Start *time.Time
+
+	// Slots are the names of the meals, shared by all the days
+	// (nil if the meals are anonymous)
+	Slots []string
+
 	// Days is the list of days of which the menu is composed
 	Days []Day
 }
 
+// FormatStart returns the start date as a string
+func (m Menu) FormatStart() string {
+	return m.Start.Format(time.DateOnly)
+}
+
 // Day is a component of a menu, and contains a name and a list of meals
 type Day struct {
 	// MID is the Menu's ID
@@ -30,6 +48,94 @@ type Day struct {
 
 	// Meals contains the meals of the day
 	Meals []string
+
+	// Date is the day's date, if the menu is anchored to
+	// the calendar (nil otherwise)
+	Date *time.Time
+
+	// Slots are the slots of the menu (see Menu.Slots)
+	Slots []string
+}
+
+// Slot returns the name of the slot of the nth meal, or an empty
+// string if the meal has no slot
+func (d Day) Slot(n int) string {
+	if n < len(d.Slots) {
+		return d.Slots[n]
+	}
+
+	return ""
+}
+
+// FormatDate returns the date as a string
+func (d Day) FormatDate() string {
+	return d.Date.Format(time.DateOnly)
+}
+
+// setDate sets the day's date, given the start of the menu
+func (d *Day) setDate(start *time.Time) {
+	if start != nil {
+		date := toDate(*start).AddDate(0, 0, d.Position)
+		d.Date = &date
+	}
+}
+
+// These are the predefined slots. Their names are translated when
+// they are shown, while the ones of the custom slots are not.
+const (
+	SLOT_BREAKFAST = "breakfast"
+	SLOT_LUNCH     = "lunch"
+	SLOT_SNACK     = "snack"
+	SLOT_DINNER    = "dinner"
+)
+
+// Slots contains the predefined slots, in the order of the day
+var Slots = []string{SLOT_BREAKFAST, SLOT_LUNCH, SLOT_SNACK, SLOT_DINNER}
+
+// checkSlots returns the trimmed names of the slots, or an
+// error if some are invalid
+func checkSlots(slots []string) ([]string, error) {
+	var checked []string
+	for _, slot := range slots {
+		slot = strings.TrimSpace(slot)
+		if slot == "" || len(slot) > 64 {
+			return nil, ERR_SLOT_NAME_INVALID
+		}
+
+		checked = append(checked, slot)
+	}
+
+	return checked, nil
+}
+
+// Week contains the days of the anchored menus in a week
+type Week struct {
+	// Start is the monday of the week
+	Start time.Time
+
+	// Days contains, for each day of the week, the days of the
+	// menus on that date
+	Days [7][]Day
+}
+
+// Date returns the date of the nth day of the week
+func (w Week) Date(n int) time.Time {
+	return w.Start.AddDate(0, 0, n)
+}
+
+// Previous returns the monday of the previous week
+func (w Week) Previous() time.Time {
+	return w.Start.AddDate(0, 0, -7)
+}
+
+// Next returns the monday of the next week
+func (w Week) Next() time.Time {
+	return w.Start.AddDate(0, 0, 7)
+}
+
+// toDate returns t as a date (at midnight, in dateLocale)
+func toDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, dateLocale)
 }
 
 // Menus is used to manage all the menus
@@ -45,13 +151,13 @@ func (u User) Menus() Menus {
 // AddDay adds a new day in a menu
 func (m Menus) AddDay(MID int, name string) error {
 	// Gets the menu
-	_, err := m.GetOne(MID)
+	menu, err := m.GetOne(MID)
 	if err != nil {
 		return err
 	}
 
-	// Adds the new day
-	_, err = db.Exec(`INSERT INTO days (mid, position, name, meals) SELECT $1, max(position)+1, $2, $3 FROM days WHERE mid=$1;`, MID, name, pq.Array([]string{}))
+	// Adds the new day, with a meal per slot
+	_, err = db.Exec(`INSERT INTO days (mid, position, name, meals) SELECT $1, max(position)+1, $2, $3 FROM days WHERE mid=$1;`, MID, name, pq.Array(make([]string, len(menu.Slots))))
 	if err != nil {
 		return ERR_UNKNOWN
 	}
@@ -59,6 +165,38 @@ func (m Menus) AddDay(MID int, name string) error {
 	return nil
 }
 
+// AddSlot adds a new slot to a menu, with an empty meal in every day
+func (m Menus) AddSlot(MID int, name string) error {
+	// Ensures the name is valid
+	slots, err := checkSlots([]string{name})
+	if err != nil {
+		return err
+	}
+
+	// Gets the menu
+	menu, err := m.GetOne(MID)
+	if err != nil {
+		return err
+	}
+
+	// Adds the meal after the ones of the other slots
+	n := len(menu.Slots)
+	for _, day := range menu.Days {
+		meals := make([]string, max(len(day.Meals), n)+1)
+		copy(meals, day.Meals[:min(len(day.Meals), n)])
+		if len(day.Meals) > n {
+			copy(meals[n+1:], day.Meals[n:])
+		}
+
+		if err = m.editDay(MID, day.Position, false, true, meals, false, ""); err != nil {
+			return err
+		}
+	}
+
+	// Saves the slot
+	return m.setSlots(MID, append(menu.Slots, slots[0]))
+}
+
 // Delete deletes a menu
 func (m Menus) Delete(MID int) error {
 	// Deletes the menu
@@ -74,12 +212,16 @@ func (m Menus) Delete(MID int) error {
 	return nil
 }
 
-// Duplicate creates a copy of the menu. It returns the MID of the copy
+// Duplicate creates a copy of the menu. It returns the MID of the copy.
+// If the menu is anchored to the calendar, the copy starts the day after
+// it ends (so that a weekly menu can be repeated in the following week).
 func (m Menus) Duplicate(srcMID int) (int, error) {
 	var dstMID int
 
 	// Copies the menu
-	err := db.QueryRow(`INSERT INTO menus (uid, name) SELECT uid, name FROM menus WHERE uid=$1 AND mid=$2 returning mid;`, m.uid, srcMID).Scan(&dstMID)
+	err := db.QueryRow(`INSERT INTO menus (uid, name, start, slots)
+						SELECT uid, name, start + (SELECT COALESCE(MAX(position)+1, 0) FROM days WHERE mid=$2), slots
+						FROM menus WHERE uid=$1 AND mid=$2 returning mid;`, m.uid, srcMID).Scan(&dstMID)
 	if err != nil {
 		return 0, handleNoRowsError(err, m.uid, ERR_MENU_NOT_FOUND)
 	}
@@ -105,15 +247,14 @@ func (m Menus) editDay(MID int, day int, fetch bool, editMeals bool, meals []str
 
 	if editMeals {
 		// Saves the new meals
-		_, err := db.Exec(`UPDATE days SET meals=$3 WHERE mid=$1 AND position=$2`, MID, day, pq.Array(meals))
-		if err != nil {
-			return ERR_UNKNOWN
+		if err := setDayMeals(db, MID, day, meals); err != nil {
+			return err
 		}
 	}
 
 	if editName {
 		// Saves the new name
-		_, err := db.Exec(`UPDATE days SET name=$3 WHERE mid=$1 AND position=$2`, MID, day, name)
+		_, err := db.Exec(`UPDATE days SET name=$3, updated=NOW() WHERE mid=$1 AND position=$2`, MID, day, name)
 		if err != nil {
 			return ERR_UNKNOWN
 		}
@@ -122,6 +263,19 @@ func (m Menus) editDay(MID int, day int, fetch bool, editMeals bool, meals []str
 	return nil
 }
 
+// setDayMeals saves the meals of a day, using either the database
+// or a transaction
+func setDayMeals(exec interface {
+	Exec(string, ...any) (sql.Result, error)
+}, MID int, day int, meals []string) error {
+	_, err := exec.Exec(`UPDATE days SET meals=$3, updated=NOW() WHERE mid=$1 AND position=$2`, MID, day, pq.Array(meals))
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
 // GetAll returns a list of menus (days not included) ordered by creation date
 func (m Menus) GetAll() ([]Menu, error) {
 	var menus []Menu
@@ -155,8 +309,10 @@ func (m Menus) GetDay(MID int, dpos int) (Day, error) {
 	var day Day
 
 	// Scans the menu
-	var a int
-	err := db.QueryRow(`SELECT 1 FROM menus WHERE uid=$1 AND mid=$2;`, m.uid, MID).Scan(&a)
+	var start *time.Time
+	var slots []string
+	err := db.QueryRow(`SELECT start, slots FROM menus WHERE uid=$1 AND mid=$2;`, m.uid, MID).
+		Scan(&start, pq.Array(&slots))
 	if err != nil {
 		return day, handleNoRowsError(err, m.uid, ERR_MENU_NOT_FOUND)
 	}
@@ -168,18 +324,122 @@ func (m Menus) GetDay(MID int, dpos int) (Day, error) {
 		return day, handleNoRowsError(err, m.uid, ERR_DAY_NOT_FOUND)
 	}
 
+	day.setDate(start)
+	if len(slots) > 0 {
+		day.Slots = slots
+	}
+
 	return day, nil
 }
 
+// GetWeek returns the days of the anchored menus in the week
+// (from monday to sunday) that contains date
+func (m Menus) GetWeek(date time.Time) (Week, error) {
+	var week Week
+
+	// Finds the monday
+	date = toDate(date)
+	week.Start = date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+
+	// Queries the days
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT d.mid, d.name, d.position, d.meals, m.start, m.slots FROM days d
+						   INNER JOIN menus m ON m.mid=d.mid WHERE m.uid=$1 AND m.start IS NOT NULL
+						   AND m.start + d.position BETWEEN $2::date AND $2::date + 6
+						   ORDER BY m.start + d.position, m.mid;`, m.uid, week.Start)
+	if err != nil {
+		return week, ERR_UNKNOWN
+	}
+
+	// Puts them in the right place
+	defer rows.Close()
+	found := false
+	for rows.Next() {
+		var day Day
+		var start time.Time
+		rows.Scan(&day.MID, &day.Name, &day.Position, pq.Array(&day.Meals), &start, pq.Array(&day.Slots))
+
+		day.setDate(&start)
+		if len(day.Slots) == 0 {
+			day.Slots = nil
+		}
+		n := int(math.Round(day.Date.Sub(week.Start).Hours() / 24))
+		week.Days[n] = append(week.Days[n], day)
+		found = true
+	}
+
+	// If no days have been found, makes sure the user exists
+	if !found {
+		_, err := GetUser("UID", m.uid)
+		return week, err
+	}
+
+	return week, nil
+}
+
+// GetRecipes returns the user's recipes (with only RID, Name, Servings
+// and Ingredients) that appear in a menu as a line of a meal, indexed
+// by their MealKey
+func (m Menus) GetRecipes(MID int) (map[string]Recipe, error) {
+	// Gets the menu
+	menu, err := m.GetOne(MID)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.getRecipes(menu)
+}
+
+// getRecipes is used by GetRecipes and GetNutrition
+func (m Menus) getRecipes(menu Menu) (map[string]Recipe, error) {
+	recipes := make(map[string]Recipe)
+
+	// Collects the meals' lines
+	var lines []string
+	for _, day := range menu.Days {
+		for _, meal := range day.Meals {
+			for _, line := range strings.Split(meal, "\n") {
+				lines = append(lines, MealKey(line))
+			}
+		}
+	}
+
+	// Queries the recipes with those names
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT rid, name, servings, ingredients FROM recipes
+						   WHERE uid=$1 AND LOWER(name) = ANY($2);`, m.uid, pq.Array(lines))
+	if err != nil {
+		return recipes, ERR_UNKNOWN
+	}
+
+	// Adds them to the map
+	defer rows.Close()
+	for rows.Next() {
+		var r Recipe
+		rows.Scan(&r.RID, &r.Name, &r.Servings, &r.Ingredients)
+		recipes[MealKey(r.Name)] = r
+	}
+
+	return recipes, nil
+}
+
 // GetOne returns a specific menu, with days and meals
 func (m Menus) GetOne(MID int) (Menu, error) {
 	var menu Menu
 
 	// Scans the menu
-	err := db.QueryRow(`SELECT mid, name FROM menus WHERE uid=$1 AND mid=$2;`, m.uid, MID).Scan(&menu.MID, &menu.Name)
+	err := db.QueryRow(`SELECT mid, name, start, slots FROM menus WHERE uid=$1 AND mid=$2;`, m.uid, MID).
+		Scan(&menu.MID, &menu.Name, &menu.Start, pq.Array(&menu.Slots))
 	if err != nil {
 		return menu, handleNoRowsError(err, m.uid, ERR_MENU_NOT_FOUND)
 	}
+	if menu.Start != nil {
+		start := toDate(*menu.Start)
+		menu.Start = &start
+	}
+	if len(menu.Slots) == 0 {
+		menu.Slots = nil
+	}
 
 	// Queries the days
 	var rows *sql.Rows
@@ -196,12 +456,19 @@ func (m Menus) GetOne(MID int) (Menu, error) {
 		if err != nil {
 			return menu, ERR_UNKNOWN
 		}
+		day.setDate(menu.Start)
+		day.Slots = menu.Slots
 		menu.Days = append(menu.Days, day)
 	}
 
 	return menu, nil
 }
 
+// MealKey returns the key used to match a line of a meal to a recipe
+func MealKey(line string) string {
+	return strings.ToLower(strings.TrimSpace(line))
+}
+
 // MoveDay moves a day in the list (+1 switches it down, -1 switches it up, etc.)
 func (m Menus) MoveDay(MID int, day int, delta int) error {
 	// Gets the days
@@ -227,8 +494,32 @@ func (m Menus) MoveDay(MID int, day int, delta int) error {
 	return err
 }
 
-// New creates a new menu and return its MID
+// New creates a new menu with mealsN anonymous meals per day and
+// returns its MID
 func (m Menus) New(name string, daysNames []string, mealsN int) (int, error) {
+	// Ensures the number of meals is valid
+	if mealsN < 0 {
+		return 0, ERR_MEALS_NEGATIVE
+	}
+
+	return m.newMenu(name, daysNames, nil, func(int) []string { return make([]string, mealsN) })
+}
+
+// NewWithSlots creates a new menu with a meal per slot in each day
+// and returns its MID
+func (m Menus) NewWithSlots(name string, daysNames []string, slots []string) (int, error) {
+	// Ensures the slots are valid
+	slots, err := checkSlots(slots)
+	if err != nil {
+		return 0, err
+	}
+
+	return m.newMenu(name, daysNames, slots, func(int) []string { return make([]string, len(slots)) })
+}
+
+// newMenu is used by New, NewWithSlots and NewFromTemplate.
+// meals returns the meals of the day in the given position.
+func (m Menus) newMenu(name string, daysNames []string, slots []string, meals func(int) []string) (int, error) {
 	var MID int
 
 	// Ensures the user exists
@@ -236,11 +527,6 @@ func (m Menus) New(name string, daysNames []string, mealsN int) (int, error) {
 		return MID, err
 	}
 
-	// Ensures the number of meals is valid
-	if mealsN < 0 {
-		return MID, ERR_MEALS_NEGATIVE
-	}
-
 	// Prepares the statement for the days
 	stmt, err := db.Prepare(`INSERT INTO days (mid, position, name, meals) VALUES ($1, $2, $3, $4);`)
 	if err != nil {
@@ -248,16 +534,19 @@ func (m Menus) New(name string, daysNames []string, mealsN int) (int, error) {
 	}
 	defer stmt.Close()
 
-	// Adds the menu
-	err = db.QueryRow(`INSERT INTO menus (uid, name) VALUES ($1, $2) RETURNING mid;`, m.uid, name).Scan(&MID)
+	// Adds the menu (the slots cannot be null)
+	if slots == nil {
+		slots = []string{}
+	}
+	err = db.QueryRow(`INSERT INTO menus (uid, name, slots) VALUES ($1, $2, $3) RETURNING mid;`,
+		m.uid, name, pq.Array(slots)).Scan(&MID)
 	if err != nil {
 		return MID, ERR_UNKNOWN
 	}
 
 	// Adds the days
 	for dpos, dname := range daysNames {
-		meals := make([]string, mealsN)
-		_, err := stmt.Exec(MID, dpos, dname, pq.Array(meals))
+		_, err := stmt.Exec(MID, dpos, dname, pq.Array(meals(dpos)))
 		if err != nil {
 			return MID, ERR_UNKNOWN
 		}
@@ -289,6 +578,30 @@ func (m Menus) RemoveDay(MID int, day int) error {
 	return nil
 }
 
+// RemoveSlot removes a slot from a menu, with its meal in every day
+func (m Menus) RemoveSlot(MID int, slot int) error {
+	// Gets the menu
+	menu, err := m.GetOne(MID)
+	if err != nil {
+		return err
+	} else if slot < 0 || slot >= len(menu.Slots) {
+		return ERR_SLOT_NOT_FOUND
+	}
+
+	// Removes the meals
+	for _, day := range menu.Days {
+		if slot < len(day.Meals) {
+			meals := slices.Delete(slices.Clone(day.Meals), slot, slot+1)
+			if err = m.editDay(MID, day.Position, false, true, meals, false, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Saves the slots
+	return m.setSlots(MID, slices.Delete(menu.Slots, slot, slot+1))
+}
+
 // SetDayMeals is used to set a day's meals
 func (m Menus) SetDayMeals(MID int, day int, meals []string) error {
 	return m.editDay(MID, day, true, true, meals, false, "")
@@ -315,3 +628,52 @@ func (m Menus) SetName(MID int, name string) error {
 
 	return nil
 }
+
+// SetSlotName renames a slot of a menu
+func (m Menus) SetSlotName(MID int, slot int, name string) error {
+	// Ensures the name is valid
+	slots, err := checkSlots([]string{name})
+	if err != nil {
+		return err
+	}
+
+	// Gets the menu
+	menu, err := m.GetOne(MID)
+	if err != nil {
+		return err
+	} else if slot < 0 || slot >= len(menu.Slots) {
+		return ERR_SLOT_NOT_FOUND
+	}
+
+	// Saves the slots
+	menu.Slots[slot] = slots[0]
+	return m.setSlots(MID, menu.Slots)
+}
+
+// setSlots is used by AddSlot, RemoveSlot and SetSlotName
+func (m Menus) setSlots(MID int, slots []string) error {
+	_, err := db.Exec(`UPDATE menus SET slots=$2 WHERE mid=$1;`, MID, pq.Array(slots))
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// SetStart anchors the menu to the calendar, making its first day fall
+// on start. If start is nil, the menu is not anchored anymore.
+func (m Menus) SetStart(MID int, start *time.Time) error {
+	// Gets the menu
+	_, err := m.GetOne(MID)
+	if err != nil {
+		return err
+	}
+
+	// Saves the new start
+	_, err = db.Exec(`UPDATE menus SET start=$2 WHERE mid=$1;`, MID, start)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
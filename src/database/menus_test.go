@@ -3,6 +3,7 @@ package database
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestMenuAddDay(t *testing.T) {
@@ -127,6 +128,18 @@ func TestMenusDuplicate(t *testing.T) {
 	menu.Days[2].Meals = meals2
 	menu.Days[0].Meals = meals0
 
+	// The copy of an anchored menu starts the day after it ends
+	anchoredMID, _ := m.New("anchored", []string{"d0", "d1", "d2"}, 1)
+	start := time.Date(2024, time.March, 30, 0, 0, 0, 0, time.UTC)
+	m.SetStart(anchoredMID, &start)
+	anchored, _ := m.GetOne(anchoredMID)
+
+	copyStart := anchored.Start.AddDate(0, 0, 3)
+	anchored.Start = &copyStart
+	for i := range anchored.Days {
+		anchored.Days[i].setDate(&copyStart)
+	}
+
 	otherU, _ := getTestingUser(t)
 	otherM := otherU.Menus()
 
@@ -170,6 +183,10 @@ func TestMenusDuplicate(t *testing.T) {
 				"",
 				data{M: m, MID: MID, ExpectedMenu: menu},
 			},
+			{
+				"(anchored)",
+				data{M: m, MID: anchoredMID, ExpectedMenu: anchored},
+			},
 		},
 	}.Run(t)
 }
@@ -335,6 +352,59 @@ func TestMenusGetOne(t *testing.T) {
 	}.Run(t)
 }
 
+func TestMenusGetRecipes(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+	m := u.Menus()
+
+	RID1, _ := r.New("Pizza")
+	r.New("Pasta")
+	RID3, _ := r.New("Salad")
+
+	MID, _ := m.New("menu", []string{"day"}, 2)
+	m.SetDayMeals(MID, 0, []string{" pizza ", "SALAD\nbread"})
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M   Menus
+		MID int
+
+		ExpectedErr  error
+		ExpectedRIDs map[string]int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			recipes, err := d.M.GetRecipes(d.MID)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				RIDs := make(map[string]int)
+				for key, recipe := range recipes {
+					RIDs[key] = recipe.RID
+				}
+
+				if !reflect.DeepEqual(RIDs, d.ExpectedRIDs) {
+					t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedRIDs, RIDs)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user got recipes",
+				data{M: otherM, MID: MID, ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"",
+				data{M: m, MID: MID, ExpectedRIDs: map[string]int{"pizza": RID1, "salad": RID3}},
+			},
+		},
+	}.Run(t)
+}
+
 func TestMenuMoveDay(t *testing.T) {
 	u, _ := getTestingUser(t)
 	m := u.Menus()
@@ -701,3 +771,343 @@ func TestMenuSetName(t *testing.T) {
 		},
 	}.Run(t)
 }
+
+func TestMenuSetStart(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	MID, _ := m.New("test", []string{"d0", "d1", "d2"}, 3)
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	start := time.Date(2024, time.March, 30, 0, 0, 0, 0, time.UTC)
+
+	type data struct {
+		M     Menus
+		MID   int
+		Start *time.Time
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.M.SetStart(d.MID, d.Start); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				got, _ := d.M.GetOne(d.MID)
+				if d.Start == nil {
+					if got.Start != nil || got.Days[0].Date != nil {
+						t.Errorf("%s: expected no start, got <%v>", msg, got.Start)
+					}
+				} else if got.Start == nil || got.FormatStart() != d.Start.Format(time.DateOnly) {
+					t.Errorf("%s: expected start <%v>, got <%v>", msg, d.Start, got.Start)
+				} else if got.Days[2].FormatDate() != "2024-04-01" {
+					t.Errorf("%s: expected third day on <2024-04-01>, got <%s>", msg, got.Days[2].FormatDate())
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user set menu start",
+				data{M: otherM, MID: MID, Start: &start, ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"set start of unknown menu",
+				data{M: m, Start: &start, ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"",
+				data{M: m, MID: MID, Start: &start},
+			},
+			{
+				"(removed)",
+				data{M: m, MID: MID},
+			},
+		},
+	}.Run(t)
+}
+
+func TestMenusGetWeek(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	// The menu goes from saturday to monday
+	start := time.Date(2024, time.March, 30, 0, 0, 0, 0, time.UTC)
+	MID, _ := m.New("test", []string{"d0", "d1", "d2"}, 1)
+	m.SetStart(MID, &start)
+	m.New("not anchored", []string{"d0"}, 1)
+
+	type data struct {
+		M    Menus
+		Date time.Time
+
+		ExpectedErr   error
+		ExpectedStart string
+		ExpectedDays  map[int]string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			week, err := d.M.GetWeek(d.Date)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if got := week.Start.Format(time.DateOnly); got != d.ExpectedStart {
+					t.Errorf("%s: expected start <%s>, got <%s>", msg, d.ExpectedStart, got)
+				}
+
+				for n, days := range week.Days {
+					if name, found := d.ExpectedDays[n]; !found && len(days) > 0 {
+						t.Errorf("%s: expected no days on <%d>, got <%v>", msg, n, days)
+					} else if found && (len(days) != 1 || days[0].Name != name) {
+						t.Errorf("%s: expected <%s> on <%d>, got <%v>", msg, name, n, days)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"got week of unknown user",
+				data{M: unknownUser.Menus(), Date: start, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"(first week)",
+				data{M: m, Date: start, ExpectedStart: "2024-03-25", ExpectedDays: map[int]string{5: "d0", 6: "d1"}},
+			},
+			{
+				"(second week)",
+				data{M: m, Date: start.AddDate(0, 0, 3), ExpectedStart: "2024-04-01", ExpectedDays: map[int]string{0: "d2"}},
+			},
+			{
+				"(empty week)",
+				data{M: m, Date: start.AddDate(0, 0, 14), ExpectedStart: "2024-04-08"},
+			},
+		},
+	}.Run(t)
+}
+
+func TestMenusNewWithSlots(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	dnames := []string{"d0", "d1"}
+
+	type data struct {
+		M     Menus
+		Slots []string
+
+		ExpectedErr   error
+		ExpectedSlots []string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if MID, err := d.M.NewWithSlots("test", dnames, d.Slots); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				got, _ := d.M.GetOne(MID)
+				if !reflect.DeepEqual(got.Slots, d.ExpectedSlots) {
+					t.Errorf("%s: expected slots <%v>, got <%v>", msg, d.ExpectedSlots, got.Slots)
+				}
+
+				for _, day := range got.Days {
+					if len(day.Meals) != len(d.ExpectedSlots) || !reflect.DeepEqual(day.Slots, d.ExpectedSlots) {
+						t.Errorf("%s: day has meals <%v> and slots <%v>", msg, day.Meals, day.Slots)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user created menu",
+				data{M: unknownUser.Menus(), Slots: []string{SLOT_LUNCH}, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"created menu with empty slot",
+				data{M: m, Slots: []string{SLOT_LUNCH, " "}, ExpectedErr: ERR_SLOT_NAME_INVALID},
+			},
+			{
+				"(no slots)",
+				data{M: m},
+			},
+			{
+				"",
+				data{M: m, Slots: []string{SLOT_LUNCH, " Second dinner "}, ExpectedSlots: []string{SLOT_LUNCH, "Second dinner"}},
+			},
+		},
+	}.Run(t)
+}
+
+func TestMenuAddSlot(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	MID, _ := m.NewWithSlots("test", []string{"d0", "d1"}, []string{SLOT_LUNCH})
+	m.SetDayMeals(MID, 0, []string{"pasta", "extra"})
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M    Menus
+		MID  int
+		Name string
+
+		ExpectedErr   error
+		ExpectedSlots []string
+		ExpectedMeals [][]string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.M.AddSlot(d.MID, d.Name); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				got, _ := d.M.GetOne(d.MID)
+				if !reflect.DeepEqual(got.Slots, d.ExpectedSlots) {
+					t.Errorf("%s: expected slots <%v>, got <%v>", msg, d.ExpectedSlots, got.Slots)
+				}
+
+				for i, day := range got.Days {
+					if !reflect.DeepEqual(day.Meals, d.ExpectedMeals[i]) {
+						t.Errorf("%s: day %d: expected meals <%v>, got <%v>", msg, i, d.ExpectedMeals[i], day.Meals)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user added slot",
+				data{M: otherM, MID: MID, Name: SLOT_DINNER, ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"added slot without name",
+				data{M: m, MID: MID, ExpectedErr: ERR_SLOT_NAME_INVALID},
+			},
+			{
+				"",
+				data{
+					M: m, MID: MID, Name: SLOT_DINNER,
+					ExpectedSlots: []string{SLOT_LUNCH, SLOT_DINNER},
+					ExpectedMeals: [][]string{{"pasta", "", "extra"}, {"", ""}},
+				},
+			},
+		},
+	}.Run(t)
+}
+
+func TestMenuRemoveSlot(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	MID, _ := m.NewWithSlots("test", []string{"d0", "d1"}, []string{SLOT_LUNCH, SLOT_DINNER})
+	m.SetDayMeals(MID, 0, []string{"pasta", "soup", "extra"})
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M    Menus
+		MID  int
+		Slot int
+
+		ExpectedErr   error
+		ExpectedSlots []string
+		ExpectedMeals [][]string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.M.RemoveSlot(d.MID, d.Slot); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				got, _ := d.M.GetOne(d.MID)
+				if !reflect.DeepEqual(got.Slots, d.ExpectedSlots) {
+					t.Errorf("%s: expected slots <%v>, got <%v>", msg, d.ExpectedSlots, got.Slots)
+				}
+
+				for i, day := range got.Days {
+					if !reflect.DeepEqual(day.Meals, d.ExpectedMeals[i]) {
+						t.Errorf("%s: day %d: expected meals <%v>, got <%v>", msg, i, d.ExpectedMeals[i], day.Meals)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user removed slot",
+				data{M: otherM, MID: MID, ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"removed unknown slot",
+				data{M: m, MID: MID, Slot: 2, ExpectedErr: ERR_SLOT_NOT_FOUND},
+			},
+			{
+				"",
+				data{
+					M: m, MID: MID, Slot: 0,
+					ExpectedSlots: []string{SLOT_DINNER},
+					ExpectedMeals: [][]string{{"soup", "extra"}, {""}},
+				},
+			},
+		},
+	}.Run(t)
+}
+
+func TestMenuSetSlotName(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	MID, _ := m.NewWithSlots("test", []string{"d0"}, []string{SLOT_LUNCH, SLOT_DINNER})
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M    Menus
+		MID  int
+		Slot int
+		Name string
+
+		ExpectedErr   error
+		ExpectedSlots []string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.M.SetSlotName(d.MID, d.Slot, d.Name); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if got, _ := d.M.GetOne(d.MID); !reflect.DeepEqual(got.Slots, d.ExpectedSlots) {
+					t.Errorf("%s: expected slots <%v>, got <%v>", msg, d.ExpectedSlots, got.Slots)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user renamed slot",
+				data{M: otherM, MID: MID, Name: "brunch", ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"renamed unknown slot",
+				data{M: m, MID: MID, Slot: -1, Name: "brunch", ExpectedErr: ERR_SLOT_NOT_FOUND},
+			},
+			{
+				"renamed slot without name",
+				data{M: m, MID: MID, ExpectedErr: ERR_SLOT_NAME_INVALID},
+			},
+			{
+				"",
+				data{M: m, MID: MID, Slot: 1, Name: "brunch", ExpectedSlots: []string{SLOT_LUNCH, "brunch"}},
+			},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,384 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/lib/pq"
+)
+
+// units contains how many grams are in a unit of measurement.
+// Liquids are assumed to weigh like water.
+var units = map[string]float64{
+	"mg": 0.001,
+	"g":  1,
+	"gr": 1,
+	"kg": 1000,
+	"ml": 1,
+	"cl": 10,
+	"dl": 100,
+	"l":  1000,
+}
+
+// Nutrition contains some nutritional values (kcal and grams)
+type Nutrition struct {
+	Kcal          float64
+	Proteins      float64
+	Carbohydrates float64
+	Fats          float64
+}
+
+// add returns the sum of two nutritions
+func (n Nutrition) add(other Nutrition) Nutrition {
+	return Nutrition{
+		Kcal:          n.Kcal + other.Kcal,
+		Proteins:      n.Proteins + other.Proteins,
+		Carbohydrates: n.Carbohydrates + other.Carbohydrates,
+		Fats:          n.Fats + other.Fats,
+	}
+}
+
+// scale returns the nutrition multiplied by factor
+func (n Nutrition) scale(factor float64) Nutrition {
+	return Nutrition{
+		Kcal:          n.Kcal * factor,
+		Proteins:      n.Proteins * factor,
+		Carbohydrates: n.Carbohydrates * factor,
+		Fats:          n.Fats * factor,
+	}
+}
+
+// nutrient is an entry of the nutrients database
+type nutrient struct {
+	// Per100g are the values for 100 grams of the ingredient
+	Per100g Nutrition
+
+	// Piece is the weight (in grams) of a single piece of the
+	// ingredient, or nil if it is unknown
+	Piece *float64
+}
+
+// Ingredient is a structured ingredient line, like "200 g flour"
+type Ingredient struct {
+	// Quantity is the amount of the ingredient
+	Quantity float64
+
+	// Unit is the unit of measurement (see units), or an empty string
+	// if the quantity is a number of pieces
+	Unit string
+
+	// Name is the (lowercase) name of the ingredient
+	Name string
+}
+
+// ParseIngredient parses an ingredient line formatted like
+// "[-] quantity [unit] [of] name", where quantity can also be
+// a fraction (like 1/2) and can use a comma as the decimal separator.
+// The second value is false if the line is not structured.
+func ParseIngredient(line string) (Ingredient, bool) {
+	var i Ingredient
+
+	// Removes the bullet points, if present
+	fields := strings.Fields(strings.TrimLeft(line, "-*• \t"))
+	if len(fields) == 0 {
+		return i, false
+	}
+
+	// Separates the quantity from what follows it (like in 200g)
+	split := strings.IndexFunc(fields[0], func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != ',' && r != '/'
+	})
+	if split == 0 {
+		return i, false
+	} else if split > 0 {
+		fields = append([]string{fields[0][:split], fields[0][split:]}, fields[1:]...)
+	}
+
+	// Parses the quantity
+	var err error
+	quantity := strings.ReplaceAll(fields[0], ",", ".")
+	if num, den, found := strings.Cut(quantity, "/"); found {
+		n, errN := strconv.ParseFloat(num, 64)
+		d, errD := strconv.ParseFloat(den, 64)
+		if errN != nil || errD != nil || d == 0 {
+			return i, false
+		}
+		i.Quantity = n / d
+	} else if i.Quantity, err = strconv.ParseFloat(quantity, 64); err != nil {
+		return i, false
+	}
+
+	// Parses the unit, if present
+	fields = fields[1:]
+	if len(fields) > 0 {
+		if _, found := units[strings.ToLower(fields[0])]; found {
+			i.Unit = strings.ToLower(fields[0])
+			fields = fields[1:]
+		}
+	}
+
+	// Drops the preposition, if present
+	if len(fields) > 1 && (strings.EqualFold(fields[0], "of") || strings.EqualFold(fields[0], "di")) {
+		fields = fields[1:]
+	}
+
+	// Parses the name
+	i.Name = strings.ToLower(strings.Join(fields, " "))
+	return i, i.Name != "" && i.Quantity > 0
+}
+
+// RecipeNutrition contains the nutritional values of a recipe
+type RecipeNutrition struct {
+	// Servings is the number of servings of the recipe
+	Servings int
+
+	// Total are the values of the whole recipe
+	Total Nutrition
+
+	// PerServing are the values of a single serving
+	PerServing Nutrition
+
+	// Counted are the ingredients that have been counted
+	Counted []string
+
+	// Missing are the ingredients that have not been counted,
+	// because they are not structured or not in the nutrients
+	// database
+	Missing []string
+}
+
+// getNutrients returns the nutrients database entries for some ingredients
+func getNutrients(names []string) (map[string]nutrient, error) {
+	nutrients := make(map[string]nutrient)
+
+	// Queries the entries
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT name, kcal, proteins, carbohydrates, fats, piece
+						   FROM nutrients WHERE name = ANY($1);`, pq.Array(names))
+	if err != nil {
+		return nutrients, ERR_UNKNOWN
+	}
+
+	// Adds them to the map
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var n nutrient
+		rows.Scan(&name, &n.Per100g.Kcal, &n.Per100g.Proteins, &n.Per100g.Carbohydrates, &n.Per100g.Fats, &n.Piece)
+		nutrients[name] = n
+	}
+
+	return nutrients, nil
+}
+
+// GetNutrition computes the nutritional values of a recipe, using
+// its structured ingredients (see ParseIngredient)
+func GetNutrition(recipe Recipe) (RecipeNutrition, error) {
+	rn := RecipeNutrition{Servings: max(recipe.Servings, 1)}
+
+	// Parses the ingredients
+	var ingredients []Ingredient
+	var names []string
+	for _, line := range strings.Split(recipe.Ingredients, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		} else if i, ok := ParseIngredient(line); ok {
+			ingredients = append(ingredients, i)
+			names = append(names, i.Name)
+		} else {
+			rn.Missing = append(rn.Missing, strings.TrimSpace(line))
+		}
+	}
+
+	// Gets their nutrients
+	nutrients, err := getNutrients(names)
+	if err != nil {
+		return rn, err
+	}
+
+	// Sums them
+	for _, i := range ingredients {
+		n, found := nutrients[i.Name]
+		if !found || (i.Unit == "" && n.Piece == nil) {
+			rn.Missing = append(rn.Missing, i.Name)
+			continue
+		}
+
+		grams := i.Quantity * units[i.Unit]
+		if i.Unit == "" {
+			grams = i.Quantity * *n.Piece
+		}
+
+		rn.Total = rn.Total.add(n.Per100g.scale(grams / 100))
+		rn.Counted = append(rn.Counted, i.Name)
+	}
+
+	rn.PerServing = rn.Total.scale(1 / float64(rn.Servings))
+	return rn, nil
+}
+
+// MenuNutrition contains the nutritional values of a menu
+type MenuNutrition struct {
+	// Days contains the values of each day (in the same order
+	// of Menu.Days)
+	Days []Nutrition
+
+	// Total are the values of the whole menu
+	Total Nutrition
+
+	// Counted is true if at least one meal has been counted
+	Counted bool
+}
+
+// GetNutrition computes the nutritional values of a menu. Every line of
+// a meal that has the same name of a recipe counts as a serving of it.
+func (m Menus) GetNutrition(MID int) (MenuNutrition, error) {
+	var mn MenuNutrition
+
+	// Gets the menu
+	menu, err := m.GetOne(MID)
+	if err != nil {
+		return mn, err
+	}
+
+	// Gets the recipes in the menu
+	recipes, err := m.getRecipes(menu)
+	if err != nil {
+		return mn, err
+	}
+
+	// Computes their nutritional values
+	servings := make(map[string]Nutrition)
+	for name, recipe := range recipes {
+		rn, err := GetNutrition(recipe)
+		if err != nil {
+			return mn, err
+		} else if len(rn.Counted) > 0 {
+			servings[name] = rn.PerServing
+		}
+	}
+
+	// Sums them by day
+	for _, day := range menu.Days {
+		var dn Nutrition
+		for _, meal := range day.Meals {
+			for _, line := range strings.Split(meal, "\n") {
+				if n, found := servings[MealKey(line)]; found {
+					dn = dn.add(n)
+					mn.Counted = true
+				}
+			}
+		}
+
+		mn.Days = append(mn.Days, dn)
+		mn.Total = mn.Total.add(dn)
+	}
+
+	return mn, nil
+}
+
+// ImportNutrients reads a CSV file and saves its entries in the nutrients
+// database, replacing the existing ones. It returns the number of imported
+// entries.
+//
+// The file must contain one row per ingredient, after a header. The columns
+// are recognized by their names: the ingredient's name ("name" or
+// "description"), the energy ("kcal" or "energy"), the proteins ("protein"),
+// the carbohydrates ("carbohydrate"), the fats ("lipid" or "fat") and,
+// optionally, the weight of a piece in grams ("piece"). All the values refer
+// to 100 grams of the ingredient. The names must be the same as the ones
+// written in the recipes (like "flour", not "Wheat flour, white"), since
+// they are matched exactly, ignoring only the case. For example:
+//
+//	name,kcal,protein,carbohydrate,fat,piece
+//	flour,364,10.3,76.3,1,
+//	eggs,143,12.6,0.7,9.5,50
+func ImportNutrients(file io.Reader) (int, error) {
+	var imported int
+
+	// Reads the header
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return imported, ERR_NUTRIENTS_INVALID
+	}
+
+	// Finds the columns
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	findColumn := func(keywords ...string) int {
+		for _, keyword := range keywords {
+			for i, name := range header {
+				if strings.Contains(name, keyword) {
+					return i
+				}
+			}
+		}
+
+		return -1
+	}
+	nameCol := findColumn("name", "description")
+	pieceCol := findColumn("piece")
+	valueCols := []int{
+		findColumn("kcal", "energy"),
+		findColumn("protein"),
+		findColumn("carbohydrate"),
+		findColumn("lipid", "fat"),
+	}
+	for _, col := range append(valueCols, nameCol) {
+		if col < 0 {
+			return imported, ERR_NUTRIENTS_INVALID
+		}
+	}
+
+	// Reads the entries
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return imported, ERR_NUTRIENTS_INVALID
+		}
+
+		// Parses the values (the empty ones are zeros)
+		var values [4]float64
+		var piece *float64
+		for i, col := range append(valueCols, pieceCol) {
+			if col < 0 || col >= len(record) || strings.TrimSpace(record[col]) == "" {
+				continue
+			}
+
+			value, err := strconv.ParseFloat(strings.TrimSpace(record[col]), 64)
+			if err != nil {
+				return imported, ERR_NUTRIENTS_INVALID
+			} else if i < len(values) {
+				values[i] = value
+			} else {
+				piece = &value
+			}
+		}
+
+		// Saves the entry
+		if nameCol >= len(record) || strings.TrimSpace(record[nameCol]) == "" {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(record[nameCol]))
+		_, err = db.Exec(`INSERT INTO nutrients (name, kcal, proteins, carbohydrates, fats, piece)
+						  VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (name) DO UPDATE
+						  SET kcal=$2, proteins=$3, carbohydrates=$4, fats=$5, piece=$6;`,
+			name, values[0], values[1], values[2], values[3], piece)
+		if err != nil {
+			return imported, ERR_UNKNOWN
+		}
+
+		imported++
+	}
+
+	return imported, nil
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const testNutrients = `Description,Energy (kcal),Protein (g),Carbohydrate (g),Total lipid (fat) (g),Piece (g)
+Test Flour,350,10,75,1,
+test egg,140,12,1,10,50
+test water,,,,,
+`
+
+func TestParseIngredient(t *testing.T) {
+	type data struct {
+		Line string
+
+		ExpectedOk         bool
+		ExpectedIngredient Ingredient
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			i, ok := ParseIngredient(d.Line)
+			if ok != d.ExpectedOk {
+				t.Errorf("%s: expected ok <%v>, got <%v>", msg, d.ExpectedOk, ok)
+			} else if ok && i != d.ExpectedIngredient {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedIngredient, i)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"parsed unstructured line", data{Line: "salt"}},
+			{"parsed line without name", data{Line: "200 g"}},
+			{"parsed invalid fraction", data{Line: "1/0 l milk"}},
+			{"(unit)", data{Line: "200 g flour", ExpectedOk: true, ExpectedIngredient: Ingredient{200, "g", "flour"}}},
+			{"(attached unit)", data{Line: "- 1,5kg Potatoes", ExpectedOk: true, ExpectedIngredient: Ingredient{1.5, "kg", "potatoes"}}},
+			{"(fraction)", data{Line: "1/2 l of milk", ExpectedOk: true, ExpectedIngredient: Ingredient{0.5, "l", "milk"}}},
+			{"(pieces)", data{Line: "* 2 eggs", ExpectedOk: true, ExpectedIngredient: Ingredient{2, "", "eggs"}}},
+		},
+	}.Run(t)
+}
+
+func TestImportNutrients(t *testing.T) {
+	type data struct {
+		File string
+
+		ExpectedErr      error
+		ExpectedImported int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			imported, err := ImportNutrients(strings.NewReader(d.File))
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if imported != d.ExpectedImported {
+				t.Errorf("%s: expected <%d> entries, got <%d>", msg, d.ExpectedImported, imported)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"imported empty file", data{ExpectedErr: ERR_NUTRIENTS_INVALID}},
+			{"imported file without columns", data{File: "name,kcal\nflour,350\n", ExpectedErr: ERR_NUTRIENTS_INVALID}},
+			{"imported invalid value", data{File: "name,kcal,protein,carbohydrate,fat\nflour,a,1,1,1\n", ExpectedErr: ERR_NUTRIENTS_INVALID}},
+			{"", data{File: testNutrients, ExpectedImported: 3}},
+			{"(again)", data{File: testNutrients, ExpectedImported: 3}},
+		},
+	}.Run(t)
+}
+
+func TestGetNutrition(t *testing.T) {
+	ImportNutrients(strings.NewReader(testNutrients))
+
+	type data struct {
+		Recipe Recipe
+
+		ExpectedKcal    float64
+		ExpectedCounted int
+		ExpectedMissing int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			rn, err := GetNutrition(d.Recipe)
+			if err != nil {
+				t.Errorf("%s: expected err <nil>, got <%v>", msg, err)
+			} else if math.Abs(rn.PerServing.Kcal-d.ExpectedKcal) > 0.01 {
+				t.Errorf("%s: expected <%f> kcal, got <%f>", msg, d.ExpectedKcal, rn.PerServing.Kcal)
+			} else if len(rn.Counted) != d.ExpectedCounted || len(rn.Missing) != d.ExpectedMissing {
+				t.Errorf("%s: expected <%d> counted and <%d> missing, got <%v> and <%v>",
+					msg, d.ExpectedCounted, d.ExpectedMissing, rn.Counted, rn.Missing)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"(unstructured)",
+				data{Recipe: Recipe{Ingredients: "salt\npepper"}, ExpectedMissing: 2},
+			},
+			{
+				"(unknown ingredient)",
+				data{Recipe: Recipe{Ingredients: "100 g unknown"}, ExpectedMissing: 1},
+			},
+			{
+				"(pieces without weight)",
+				data{Recipe: Recipe{Ingredients: "2 test flour"}, ExpectedMissing: 1},
+			},
+			{
+				"",
+				data{
+					Recipe:          Recipe{Servings: 2, Ingredients: "200 g test flour\n2 test egg\n\nsalt"},
+					ExpectedKcal:    (700 + 140) / 2,
+					ExpectedCounted: 2,
+					ExpectedMissing: 1,
+				},
+			},
+		},
+	}.Run(t)
+}
+
+func TestMenusGetNutrition(t *testing.T) {
+	ImportNutrients(strings.NewReader(testNutrients))
+
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+	m := u.Menus()
+
+	RID, _ := r.New("Bread")
+	r.Edit(RID, Recipe{Name: "Bread", Servings: 4, Ingredients: "400 g test flour"})
+
+	MID, _ := m.New("menu", []string{"day 1", "day 2"}, 2)
+	m.SetDayMeals(MID, 0, []string{"bread", "Bread\nsalad"})
+	m.SetDayMeals(MID, 1, []string{"salad", ""})
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M   Menus
+		MID int
+
+		ExpectedErr  error
+		ExpectedDays []float64
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			mn, err := d.M.GetNutrition(d.MID)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if len(mn.Days) != len(d.ExpectedDays) {
+					t.Errorf("%s: expected <%d> days, got <%d>", msg, len(d.ExpectedDays), len(mn.Days))
+					return
+				}
+
+				var total float64
+				for i, kcal := range d.ExpectedDays {
+					total += kcal
+					if math.Abs(mn.Days[i].Kcal-kcal) > 0.01 {
+						t.Errorf("%s: day %d: expected <%f> kcal, got <%f>", msg, i, kcal, mn.Days[i].Kcal)
+					}
+				}
+
+				if math.Abs(mn.Total.Kcal-total) > 0.01 {
+					t.Errorf("%s: expected <%f> total kcal, got <%f>", msg, total, mn.Total.Kcal)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user got nutrition",
+				data{M: otherM, MID: MID, ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"",
+				data{M: m, MID: MID, ExpectedDays: []float64{700, 0}},
+			},
+		},
+	}.Run(t)
+}
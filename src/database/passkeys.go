@@ -0,0 +1,175 @@
+package database
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Passkey is a WebAuthn credential that can be used to sign in
+// without a password
+type Passkey struct {
+	// PKID is the Passkey ID
+	PKID int
+
+	// Name is a name chosen by the user to recognize the passkey
+	Name string
+
+	// Created is when the passkey has been added
+	Created time.Time
+
+	// LastUsed is when the passkey has been used the last time (can be nil)
+	LastUsed *time.Time
+}
+
+// FormatCreated returns the creation date as a string
+func (p Passkey) FormatCreated() string {
+	return p.Created.Format(time.DateOnly)
+}
+
+// FormatLastUsed returns the date of the last use as a string,
+// or an empty string if the passkey has never been used
+func (p Passkey) FormatLastUsed() string {
+	if p.LastUsed == nil {
+		return ""
+	}
+
+	return p.LastUsed.Format(time.DateOnly)
+}
+
+// PasskeyHandle returns the user handle (base64url-encoded) that is
+// stored by the authenticators with the passkeys of the user
+func (u User) PasskeyHandle() string {
+	return encodeBase64URL([]byte(strconv.Itoa(u.UID)))
+}
+
+// DeletePasskey revokes a passkey of the user
+func (u User) DeletePasskey(PKID int) error {
+	res, err := db.Exec(`DELETE FROM passkeys WHERE uid=$1 AND pkid=$2;`, u.UID, PKID)
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra < 1 {
+		return handleNoRowsError(sql.ErrNoRows, u.UID, ERR_PASSKEY_NOT_FOUND)
+	}
+
+	return nil
+}
+
+// GetPasskeys returns the passkeys of the user, ordered by creation
+func (u User) GetPasskeys() ([]Passkey, error) {
+	var passkeys []Passkey
+
+	// Queries the passkeys
+	rows, err := db.Query(`SELECT pkid, name, created, last_used FROM passkeys WHERE uid=$1 ORDER BY created, pkid;`, u.UID)
+	if err != nil {
+		return passkeys, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var p Passkey
+		rows.Scan(&p.PKID, &p.Name, &p.Created, &p.LastUsed)
+		passkeys = append(passkeys, p)
+	}
+
+	// If no passkeys have been found, makes sure the user exists
+	if len(passkeys) == 0 {
+		_, err := GetUser("UID", u.UID)
+		return passkeys, err
+	}
+
+	return passkeys, nil
+}
+
+// NewPasskey verifies the response of an authenticator to the creation
+// of a credential (navigator.credentials.create), with the challenge
+// that has been sent to it, then saves the passkey and returns its PKID
+func (u User) NewPasskey(name string, rp RelyingParty, challenge string, clientDataJSON []byte, attestationObject []byte) (int, error) {
+	var PKID int
+
+	// Ensures the user exists and the name is valid
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return PKID, err
+	} else if len(name) < 1 || len(name) > 64 {
+		return PKID, ERR_PASSKEY_NAME_INVALID
+	}
+
+	// Verifies the response
+	if err := checkClientData(clientDataJSON, "webauthn.create", challenge, rp); err != nil {
+		return PKID, err
+	}
+	ad, err := parseAttestationObject(attestationObject, rp)
+	if err != nil {
+		return PKID, err
+	}
+
+	// Saves the passkey
+	err = db.QueryRow(`INSERT INTO passkeys (uid, credential, public_key, sign_count, name)
+					   VALUES ($1, $2, $3, $4, $5) RETURNING pkid;`,
+		u.UID, encodeBase64URL(ad.credentialID), ad.publicKey, ad.signCount, name).Scan(&PKID)
+	if err != nil {
+		if pqe, ok := err.(*pq.Error); ok && pqe.Code == "23505" {
+			return PKID, ERR_PASSKEY_DUPLICATED
+		} else {
+			return PKID, ERR_UNKNOWN
+		}
+	}
+
+	return PKID, nil
+}
+
+// SignInWithPasskey verifies the response of an authenticator to a
+// request of authentication (navigator.credentials.get), with the
+// challenge that has been sent to it, then returns the user that owns
+// the passkey. The credential ID and the user handle are base64url-encoded;
+// the latter can be empty.
+func SignInWithPasskey(rp RelyingParty, challenge string, credentialID string, userHandle string,
+	clientDataJSON []byte, authData []byte, signature []byte) (User, error) {
+	var PKID, UID int
+	var publicKey []byte
+	var signCount int64
+
+	// Fetches the passkey
+	err := db.QueryRow(`SELECT pkid, uid, public_key, sign_count FROM passkeys WHERE credential=$1;`, credentialID).
+		Scan(&PKID, &UID, &publicKey, &signCount)
+	if err == sql.ErrNoRows {
+		return User{}, ERR_PASSKEY_NOT_FOUND
+	} else if err != nil {
+		return User{}, ERR_UNKNOWN
+	}
+
+	// Verifies the response
+	if err = checkClientData(clientDataJSON, "webauthn.get", challenge, rp); err != nil {
+		return User{}, err
+	}
+	ad, err := parseAuthenticatorData(authData, rp)
+	if err != nil {
+		return User{}, err
+	} else if err = verifyAssertion(publicKey, authData, clientDataJSON, signature); err != nil {
+		return User{}, err
+	} else if userHandle != "" && userHandle != (User{UID: UID}).PasskeyHandle() {
+		return User{}, ERR_PASSKEY_INVALID
+	}
+
+	// If the authenticator has a counter, it must have increased
+	// (otherwise the passkey may have been cloned)
+	if (ad.signCount != 0 || signCount != 0) && int64(ad.signCount) <= signCount {
+		return User{}, ERR_PASSKEY_INVALID
+	}
+
+	// Saves the counter
+	_, err = db.Exec(`UPDATE passkeys SET sign_count=$2, last_used=NOW() WHERE pkid=$1;`, PKID, ad.signCount)
+	if err != nil {
+		return User{}, ERR_UNKNOWN
+	}
+
+	user, err := GetUser("UID", UID)
+	if err == nil && user.Disabled {
+		return User{}, ERR_USER_DISABLED
+	}
+
+	return user, err
+}
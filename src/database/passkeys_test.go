@@ -0,0 +1,346 @@
+package database
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// testingRP is the relying party used in the tests
+var testingRP = RelyingParty{ID: "cucinassistant.test", Origin: "https://cucinassistant.test"}
+
+// cborPairs is a CBOR map whose keys are encoded in order
+type cborPairs [][2]any
+
+// encodeCBORHead encodes the major type and the argument of an item
+func encodeCBORHead(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg < 1<<8:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg < 1<<16:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+	default:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+	}
+}
+
+// encodeCBOR encodes integers, byte and text strings, and maps
+func encodeCBOR(v any) []byte {
+	switch v := v.(type) {
+	case int:
+		if v < 0 {
+			return encodeCBORHead(1, uint64(-1-v))
+		}
+		return encodeCBORHead(0, uint64(v))
+	case []byte:
+		return append(encodeCBORHead(2, uint64(len(v))), v...)
+	case string:
+		return append(encodeCBORHead(3, uint64(len(v))), v...)
+	case cborPairs:
+		data := encodeCBORHead(5, uint64(len(v)))
+		for _, pair := range v {
+			data = append(data, encodeCBOR(pair[0])...)
+			data = append(data, encodeCBOR(pair[1])...)
+		}
+		return data
+	}
+
+	return nil
+}
+
+// softAuthenticator is a software WebAuthn authenticator
+// with an ES256 key
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+}
+
+// newSoftAuthenticator creates a new authenticator, with a new key
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Cannot generate key: %s", err.Error())
+	}
+
+	credentialID := make([]byte, 16)
+	rand.Read(credentialID)
+	return &softAuthenticator{key: key, credentialID: credentialID}
+}
+
+// clientData returns the client data JSON built by the browser
+func (a *softAuthenticator) clientData(typ string, challenge string, origin string) []byte {
+	data, _ := json.Marshal(map[string]any{"type": typ, "challenge": challenge, "origin": origin, "crossOrigin": false})
+	return data
+}
+
+// authData returns the authenticator data, with the credential
+// public key if attested is true
+func (a *softAuthenticator) authData(rpID string, flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+
+	if attested {
+		x := make([]byte, 32)
+		y := make([]byte, 32)
+		a.key.X.FillBytes(x)
+		a.key.Y.FillBytes(y)
+
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, encodeCBOR(cborPairs{{1, 2}, {3, -7}, {-1, 1}, {-2, x}, {-3, y}})...)
+	}
+
+	return data
+}
+
+// create returns the client data and the attestation object
+// for the creation of the credential
+func (a *softAuthenticator) create(rp RelyingParty, challenge string, flags byte) ([]byte, []byte) {
+	authData := a.authData(rp.ID, flags|flagAttestedData, true)
+	object := encodeCBOR(cborPairs{{"fmt", "none"}, {"attStmt", cborPairs{}}, {"authData", authData}})
+	return a.clientData("webauthn.create", challenge, rp.Origin), object
+}
+
+// get increases the counter, then returns the client data,
+// the authenticator data and the signature for an assertion
+func (a *softAuthenticator) get(rp RelyingParty, challenge string) ([]byte, []byte, []byte) {
+	a.signCount++
+	clientData := a.clientData("webauthn.get", challenge, rp.Origin)
+	authData := a.authData(rp.ID, flagUserPresent|flagUserVerified, false)
+
+	clientDataHash := sha256.Sum256(clientData)
+	hash := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, _ := ecdsa.SignASN1(rand.Reader, a.key, hash[:])
+	return clientData, authData, signature
+}
+
+// id returns the credential ID, base64url-encoded
+func (a *softAuthenticator) id() string {
+	return encodeBase64URL(a.credentialID)
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	type data struct {
+		Data []byte
+
+		Expected    any
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			got, _, err := decodeCBOR(d.Data)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil && !reflect.DeepEqual(got, d.Expected) {
+				t.Errorf("%s: expected <%#v>, got <%#v>", msg, d.Expected, got)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"decoded empty data", data{Data: []byte{}, ExpectedErr: errCBOR}},
+			{"decoded truncated string", data{Data: []byte{0x43, 1, 2}, ExpectedErr: errCBOR}},
+			{"decoded indefinite length", data{Data: []byte{0x5f, 0x41, 1, 0xff}, ExpectedErr: errCBOR}},
+			{"decoded float", data{Data: []byte{0xf9, 0x3c, 0x00}, ExpectedErr: errCBOR}},
+			{"decoded huge array", data{Data: []byte{0x9a, 0xff, 0xff, 0xff, 0xff}, ExpectedErr: errCBOR}},
+			{"(unsigned)", data{Data: []byte{0x18, 100}, Expected: int64(100)}},
+			{"(negative)", data{Data: []byte{0x38, 0x63}, Expected: int64(-100)}},
+			{"(bytes)", data{Data: []byte{0x42, 1, 2}, Expected: []byte{1, 2}}},
+			{"(text)", data{Data: []byte{0x62, 'o', 'k'}, Expected: "ok"}},
+			{"(array)", data{Data: []byte{0x82, 0xf5, 0xf6}, Expected: []any{true, nil}}},
+			{"(map)", data{Data: encodeCBOR(cborPairs{{1, 2}, {"a", -7}}), Expected: map[any]any{int64(1): int64(2), "a": int64(-7)}}},
+		},
+	}.Run(t)
+}
+
+func TestUserNewPasskey(t *testing.T) {
+	u, _ := getTestingUser(t)
+	a := newSoftAuthenticator(t)
+	challenge := "Y2hhbGxlbmdl"
+	flags := byte(flagUserPresent | flagUserVerified)
+
+	clientData, object := a.create(testingRP, challenge, flags)
+	_, notVerified := a.create(testingRP, challenge, flagUserPresent)
+	_, wrongRP := a.create(RelyingParty{ID: "example.com", Origin: testingRP.Origin}, challenge, flags)
+	wrongOrigin, _ := a.create(RelyingParty{ID: testingRP.ID, Origin: "https://example.com"}, challenge, flags)
+	wrongType := a.clientData("webauthn.get", challenge, testingRP.Origin)
+
+	type data struct {
+		U          User
+		Name       string
+		Challenge  string
+		ClientData []byte
+		Object     []byte
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			PKID, err := d.U.NewPasskey(d.Name, testingRP, d.Challenge, d.ClientData, d.Object)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if passkeys, _ := d.U.GetPasskeys(); len(passkeys) != 1 || passkeys[0].PKID != PKID || passkeys[0].Name != d.Name {
+					t.Errorf("%s: passkey not saved: got <%+v>", msg, passkeys)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user added passkey", data{U: unknownUser, Name: "Phone", Challenge: challenge, ClientData: clientData, Object: object, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"added passkey without name", data{U: u, Name: "", Challenge: challenge, ClientData: clientData, Object: object, ExpectedErr: ERR_PASSKEY_NAME_INVALID}},
+			{"added passkey without challenge", data{U: u, Name: "Phone", Challenge: "", ClientData: clientData, Object: object, ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"added passkey with wrong challenge", data{U: u, Name: "Phone", Challenge: "b3RoZXI", ClientData: clientData, Object: object, ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"added passkey with wrong origin", data{U: u, Name: "Phone", Challenge: challenge, ClientData: wrongOrigin, Object: object, ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"added passkey with wrong type", data{U: u, Name: "Phone", Challenge: challenge, ClientData: wrongType, Object: object, ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"added passkey for another website", data{U: u, Name: "Phone", Challenge: challenge, ClientData: clientData, Object: wrongRP, ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"added passkey without verifying the user", data{U: u, Name: "Phone", Challenge: challenge, ClientData: clientData, Object: notVerified, ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"added invalid passkey", data{U: u, Name: "Phone", Challenge: challenge, ClientData: clientData, Object: object[:20], ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"", data{U: u, Name: "Phone", Challenge: challenge, ClientData: clientData, Object: object}},
+			{"added passkey twice", data{U: u, Name: "Phone", Challenge: challenge, ClientData: clientData, Object: object, ExpectedErr: ERR_PASSKEY_DUPLICATED}},
+		},
+	}.Run(t)
+}
+
+func TestSignInWithPasskey(t *testing.T) {
+	u, _ := getTestingUser(t)
+	a := newSoftAuthenticator(t)
+	other := newSoftAuthenticator(t)
+	other.credentialID = a.credentialID
+	unknown := newSoftAuthenticator(t)
+	challenge := "Y2hhbGxlbmdl"
+
+	clientData, object := a.create(testingRP, challenge, flagUserPresent|flagUserVerified)
+	u.NewPasskey("Phone", testingRP, challenge, clientData, object)
+
+	type data struct {
+		A          *softAuthenticator
+		Challenge  string
+		UserHandle string
+		Replay     bool
+
+		ExpectedErr error
+	}
+
+	var last [3][]byte
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if !d.Replay {
+				last[0], last[1], last[2] = d.A.get(testingRP, challenge)
+			}
+
+			user, err := SignInWithPasskey(testingRP, d.Challenge, d.A.id(), d.UserHandle, last[0], last[1], last[2])
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil && user.UID != u.UID {
+				t.Errorf("%s: expected user <%d>, got <%d>", msg, u.UID, user.UID)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"signed in with unknown passkey", data{A: unknown, Challenge: challenge, ExpectedErr: ERR_PASSKEY_NOT_FOUND}},
+			{"signed in with wrong challenge", data{A: a, Challenge: "b3RoZXI", ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"signed in with wrong key", data{A: other, Challenge: challenge, ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"signed in with wrong user handle", data{A: a, Challenge: challenge, UserHandle: (User{UID: u.UID + 1}).PasskeyHandle(), ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"", data{A: a, Challenge: challenge, UserHandle: u.PasskeyHandle()}},
+			{"signed in replaying the assertion", data{A: a, Challenge: challenge, Replay: true, ExpectedErr: ERR_PASSKEY_INVALID}},
+			{"(without user handle)", data{A: a, Challenge: challenge}},
+		},
+	}.Run(t)
+
+	if passkeys, _ := u.GetPasskeys(); len(passkeys) != 1 || passkeys[0].LastUsed == nil {
+		t.Errorf("expected passkey used, got <%+v>", passkeys)
+	}
+}
+
+func TestUserGetPasskeys(t *testing.T) {
+	u, _ := getTestingUser(t)
+	empty, _ := getTestingUser(t)
+	challenge := "Y2hhbGxlbmdl"
+
+	var expected []string
+	for _, name := range []string{"Phone", "Laptop"} {
+		clientData, object := newSoftAuthenticator(t).create(testingRP, challenge, flagUserPresent|flagUserVerified)
+		u.NewPasskey(name, testingRP, challenge, clientData, object)
+		expected = append(expected, name)
+	}
+
+	type data struct {
+		U User
+
+		ExpectedNames []string
+		ExpectedErr   error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			passkeys, err := d.U.GetPasskeys()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				var names []string
+				for _, p := range passkeys {
+					names = append(names, p.Name)
+				}
+				if !reflect.DeepEqual(names, d.ExpectedNames) {
+					t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedNames, names)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user got passkeys", data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"(empty)", data{U: empty}},
+			{"", data{U: u, ExpectedNames: expected}},
+		},
+	}.Run(t)
+}
+
+func TestUserDeletePasskey(t *testing.T) {
+	u, _ := getTestingUser(t)
+	otherUser, _ := getTestingUser(t)
+	a := newSoftAuthenticator(t)
+	challenge := "Y2hhbGxlbmdl"
+
+	clientData, object := a.create(testingRP, challenge, flagUserPresent|flagUserVerified)
+	PKID, _ := u.NewPasskey("Phone", testingRP, challenge, clientData, object)
+
+	type data struct {
+		U    User
+		PKID int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.DeletePasskey(d.PKID); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				clientData, authData, signature := a.get(testingRP, challenge)
+				if _, err := SignInWithPasskey(testingRP, challenge, a.id(), "", clientData, authData, signature); err != ERR_PASSKEY_NOT_FOUND {
+					t.Errorf("%s: revoked passkey still valid: got err <%v>", msg, err)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user deleted passkey", data{U: unknownUser, PKID: PKID, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"deleted other user's passkey", data{U: otherUser, PKID: PKID, ExpectedErr: ERR_PASSKEY_NOT_FOUND}},
+			{"deleted unknown passkey", data{U: u, PKID: PKID + 1000, ExpectedErr: ERR_PASSKEY_NOT_FOUND}},
+			{"", data{U: u, PKID: PKID}},
+			{"deleted passkey twice", data{U: u, PKID: PKID, ExpectedErr: ERR_PASSKEY_NOT_FOUND}},
+		},
+	}.Run(t)
+}
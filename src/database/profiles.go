@@ -0,0 +1,122 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Profile is the public page of a user
+type Profile struct {
+	// Username is the user's username
+	Username string
+
+	// Bio is a short presentation written by the user
+	Bio string
+
+	// Collections are the user's published collections
+	// (with only Tag and Code)
+	Collections []Collection
+
+	// Recipes are the user's shared recipes (with only Name and Code)
+	Recipes []Recipe
+}
+
+// DeleteProfile makes the user's profile private
+func (u User) DeleteProfile() error {
+	// Ensures the user exists
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return err
+	}
+
+	// Deletes the profile
+	if _, err := db.Exec(`DELETE FROM profiles WHERE uid=$1;`, u.UID); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// GetProfile returns the user's profile. If the user has not made it
+// public, ERR_PROFILE_NOT_FOUND is returned.
+func (u User) GetProfile() (Profile, error) {
+	return getProfile("u.uid", u.UID)
+}
+
+// SetProfile makes the user's profile public (if it wasn't already)
+// and changes its bio
+func (u User) SetProfile(bio string) error {
+	// Ensures the user exists
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return err
+	}
+
+	// Saves the profile
+	_, err := db.Exec(`INSERT INTO profiles (uid, bio) VALUES ($1, $2)
+					   ON CONFLICT (uid) DO UPDATE SET bio=EXCLUDED.bio;`, u.UID, bio)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// GetPublicProfile returns the profile of a user given its username.
+// If the user has not made it public (or has been disabled),
+// ERR_PROFILE_NOT_FOUND is returned.
+func GetPublicProfile(username string) (Profile, error) {
+	profile, err := getProfile("u.username", username)
+	if err == ERR_USER_UNKNOWN || err == ERR_USER_DISABLED {
+		err = ERR_PROFILE_NOT_FOUND
+	}
+
+	return profile, err
+}
+
+// getProfile is used by GetProfile and GetPublicProfile to get a profile
+// given a field of the user (u.uid or u.username)
+func getProfile(field string, value any) (Profile, error) {
+	var UID int
+	var bio sql.NullString
+	var disabled bool
+	var profile Profile
+
+	// Scans the profile
+	err := db.QueryRow(`SELECT u.uid, u.username, u.disabled, p.bio FROM ca_users u
+						LEFT JOIN profiles p ON p.uid=u.uid WHERE `+field+`=$1;`, value).
+		Scan(&UID, &profile.Username, &disabled, &bio)
+	if errors.Is(err, sql.ErrNoRows) {
+		return profile, ERR_USER_UNKNOWN
+	} else if err != nil {
+		return profile, ERR_UNKNOWN
+	} else if disabled {
+		return profile, ERR_USER_DISABLED
+	}
+
+	// Ensures it is public
+	if !bio.Valid {
+		return profile, ERR_PROFILE_NOT_FOUND
+	}
+	profile.Bio = bio.String
+
+	// Gets the published collections
+	if profile.Collections, err = (Collections{uid: UID}).GetAll(); err != nil {
+		return profile, err
+	}
+
+	// Gets the shared recipes, leaving out the links with limited views
+	// so that visitors don't use them up
+	rows, err := db.Query(`SELECT r.name, r.code FROM recipes r INNER JOIN shares s ON s.code=r.code
+						   WHERE r.uid=$1 AND s.max_views IS NULL AND `+shareActive+` ORDER BY r.name;`, UID)
+	if err != nil {
+		return profile, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var recipe Recipe
+		rows.Scan(&recipe.Name, &recipe.Code)
+		profile.Recipes = append(profile.Recipes, recipe)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestGetPublicProfile(t *testing.T) {
+	u, _ := getTestingUser(t)
+	RID, _ := u.Recipes().New("recipe")
+	u.Recipes().Edit(RID, Recipe{Name: "recipe", Tags: []string{"PASTA"}})
+	code, _ := u.Recipes().Share(RID)
+	limitedRID, _ := u.Recipes().New("limited recipe")
+	one := 1
+	u.Recipes().ShareLimited(limitedRID, nil, &one)
+	u.Collections().Publish("PASTA")
+	u.SetProfile("Hi!")
+
+	privateU, _ := getTestingUser(t)
+
+	disabledU, _ := getTestingUser(t)
+	disabledU.SetProfile("Bye!")
+	disabledU.DeleteProfile()
+
+	type data struct {
+		Username string
+
+		ExpectedErr     error
+		ExpectedBio     string
+		ExpectedRecipes []string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			profile, err := GetPublicProfile(d.Username)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if profile.Bio != d.ExpectedBio {
+					t.Errorf("%s: expected bio <%s>, got <%s>", msg, d.ExpectedBio, profile.Bio)
+				}
+
+				if len(profile.Collections) != 1 || profile.Collections[0].Tag != "PASTA" {
+					t.Errorf("%s: expected collection <PASTA>, got <%v>", msg, profile.Collections)
+				}
+
+				if len(profile.Recipes) != 1 || *profile.Recipes[0].Code != code {
+					t.Errorf("%s: expected recipe <%s>, got <%v>", msg, code, profile.Recipes)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"got unknown profile",
+				data{Username: "unknown", ExpectedErr: ERR_PROFILE_NOT_FOUND},
+			},
+			{
+				"got private profile",
+				data{Username: privateU.Username, ExpectedErr: ERR_PROFILE_NOT_FOUND},
+			},
+			{
+				"got disabled profile",
+				data{Username: disabledU.Username, ExpectedErr: ERR_PROFILE_NOT_FOUND},
+			},
+			{
+				"",
+				data{Username: u.Username, ExpectedBio: "Hi!"},
+			},
+		},
+	}.Run(t)
+}
+
+func TestUserSetProfile(t *testing.T) {
+	u, _ := getTestingUser(t)
+
+	type data struct {
+		U   User
+		Bio string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.SetProfile(d.Bio); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if profile, _ := d.U.GetProfile(); profile.Bio != d.Bio {
+					t.Errorf("%s: expected bio <%s>, got <%s>", msg, d.Bio, profile.Bio)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user set profile",
+				data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"",
+				data{U: u, Bio: "Hello"},
+			},
+			{
+				"(updated)",
+				data{U: u, Bio: "Hello again"},
+			},
+		},
+	}.Run(t)
+}
@@ -1,9 +1,8 @@
 package database
 
 import (
-	"crypto/rand"
 	"database/sql"
-	"fmt"
+	"errors"
 	"reflect"
 	"slices"
 
@@ -19,10 +18,14 @@ type Recipe struct {
 	// Name is the name of the recipe
 	Name string
 
-	// Stars is the number of stars the recipe
-	// has (0 <= Stars <= 5)
+	// Stars is the number of stars the recipe has (0 <= Stars <= 10,
+	// where every unit is half a star). If the recipe has been rated
+	// in its cooking log, it is the average rating.
 	Stars int
 
+	// Servings is the number of servings the recipe makes (at least 1)
+	Servings int
+
 	// Ingredients is a text containing the ingredients
 	Ingredients string
 
@@ -72,33 +75,55 @@ func (r Recipes) Delete(RID int) error {
 	return nil
 }
 
-// Edit replaces all the recipes's data, except for the RID
+// Edit replaces all the recipes's data, except for the RID and the stars
+// (that are derived from the cooking log). The replaced content is saved
+// as a revision.
 func (r Recipes) Edit(RID int, updated Recipe) error {
-	var original Recipe
-	var err error
-
 	// Ensures the recipe (and the user) exist
-	if original, err = r.GetOne(RID); err != nil {
+	original, err := r.GetOne(RID)
+	if err != nil {
+		return err
+	}
+
+	// Updates the recipe and its revisions together, so that
+	// concurrent edits cannot lose any of them
+	tx, err := db.Begin()
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+	defer tx.Rollback()
+
+	if err = edit(tx, original, updated); err != nil {
 		return err
 	}
 
-	// Ensures the stars are correct
-	if updated.Stars < 0 {
-		updated.Stars = 0
-	} else if updated.Stars > 10 {
-		updated.Stars = 10
+	if err = tx.Commit(); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// edit replaces, within the transaction, the data of the original
+// recipe with the updated one, like Edit
+func edit(tx *sql.Tx, original Recipe, updated Recipe) error {
+	// Ensures the servings are correct
+	if updated.Servings < 1 {
+		updated.Servings = 1
 	}
 
 	// Checks if something has actually changed
+	RID := original.RID
 	updated.RID = RID
 	updated.Code = original.Code
+	updated.Stars = original.Stars
 	if reflect.DeepEqual(original, updated) {
 		return nil
 	}
 
 	// Executes the query
-	_, err = db.Exec(`UPDATE recipes SET name=$2, stars=$3, ingredients=$4, directions=$5, notes=$6 WHERE rid=$1;`,
-		RID, updated.Name, updated.Stars, updated.Ingredients, updated.Directions, updated.Notes)
+	_, err := tx.Exec(`UPDATE recipes SET name=$2, servings=$3, ingredients=$4, directions=$5, notes=$6 WHERE rid=$1;`,
+		RID, updated.Name, updated.Servings, updated.Ingredients, updated.Directions, updated.Notes)
 	if err != nil {
 		if pqe, ok := err.(*pq.Error); ok && pqe.Code == "23505" {
 			return ERR_RECIPE_DUPLICATED
@@ -107,10 +132,18 @@ func (r Recipes) Edit(RID int, updated Recipe) error {
 		}
 	}
 
+	// Saves the replaced content as a revision
+	if original.Name != updated.Name || original.Ingredients != updated.Ingredients ||
+		original.Directions != updated.Directions || original.Notes != updated.Notes {
+		if err = addRevision(tx, RID, original); err != nil {
+			return err
+		}
+	}
+
 	// Adds the missing tags
 	for _, tag := range updated.Tags {
 		if tag != "" && !slices.Contains(original.Tags, tag) {
-			_, err = db.Exec(`INSERT INTO tags (name, rid) VALUES ($1, $2);`, tag, RID)
+			_, err = tx.Exec(`INSERT INTO tags (name, rid) VALUES ($1, $2);`, tag, RID)
 			if err != nil {
 				return ERR_UNKNOWN
 			}
@@ -120,7 +153,7 @@ func (r Recipes) Edit(RID int, updated Recipe) error {
 	// Removes the dropped tags
 	for _, tag := range original.Tags {
 		if !slices.Contains(updated.Tags, tag) {
-			_, err = db.Exec(`DELETE FROM tags WHERE name=$1 AND rid=$2;`, tag, RID)
+			_, err = tx.Exec(`DELETE FROM tags WHERE name=$1 AND rid=$2;`, tag, RID)
 			if err != nil {
 				return ERR_UNKNOWN
 			}
@@ -159,13 +192,43 @@ func (r Recipes) GetAll() ([]Recipe, error) {
 	return recipes, nil
 }
 
+// GetAllIngredients returns the ingredients of every recipe,
+// indexed by RID
+func (r Recipes) GetAllIngredients() (map[int]string, error) {
+	ingredients := make(map[int]string)
+
+	// Queries the recipes
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT rid, ingredients FROM recipes WHERE uid=$1;`, r.uid)
+	if err != nil {
+		return ingredients, ERR_UNKNOWN
+	}
+
+	// Adds them to the map
+	defer rows.Close()
+	for rows.Next() {
+		var RID int
+		var i string
+		rows.Scan(&RID, &i)
+		ingredients[RID] = i
+	}
+
+	// If no recipes have been found, makes sure the user exists
+	if len(ingredients) == 0 {
+		_, err := GetUser("UID", r.uid)
+		return ingredients, err
+	}
+
+	return ingredients, nil
+}
+
 // GetOne returns a specific recipe
 func (r Recipes) GetOne(RID int) (Recipe, error) {
 	var recipe Recipe
 
 	// Scans the recipe
-	err := db.QueryRow(`SELECT rid, name, stars, ingredients, directions, notes, code FROM recipes WHERE uid=$1 AND rid=$2;`, r.uid, RID).
-		Scan(&recipe.RID, &recipe.Name, &recipe.Stars, &recipe.Ingredients, &recipe.Directions, &recipe.Notes, &recipe.Code)
+	err := db.QueryRow(`SELECT rid, name, stars, servings, ingredients, directions, notes, code FROM recipes WHERE uid=$1 AND rid=$2;`, r.uid, RID).
+		Scan(&recipe.RID, &recipe.Name, &recipe.Stars, &recipe.Servings, &recipe.Ingredients, &recipe.Directions, &recipe.Notes, &recipe.Code)
 	if err != nil {
 		return recipe, handleNoRowsError(err, r.uid, ERR_RECIPE_NOT_FOUND)
 	}
@@ -186,14 +249,37 @@ func (r Recipes) GetOne(RID int) (Recipe, error) {
 	return recipe, nil
 }
 
-// GetPublicRecipe returns a public recipe
+// GetPublicRecipe returns a public recipe, counting the view.
+// If its link has expired (or its owner has been disabled),
+// ERR_RECIPE_NOT_FOUND is returned.
 func GetPublicRecipe(code string) (Recipe, error) {
+	// Counts the view, only if the link still has some left
+	// (checking and counting together, so that concurrent
+	// views cannot exceed the limit)
+	err := db.QueryRow(`UPDATE shares s SET views=s.views+1 FROM recipes r INNER JOIN ca_users u ON u.uid=r.uid
+						WHERE s.code=$1 AND r.code=s.code AND NOT u.disabled AND `+shareActive+`
+						RETURNING s.rid;`, code).Scan(new(int))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Recipe{}, ERR_RECIPE_NOT_FOUND
+	} else if err != nil {
+		return Recipe{}, ERR_UNKNOWN
+	}
+
+	// Gets the recipe (the view has already been counted)
+	return getPublicRecipe(code, shareValid)
+}
+
+// getPublicRecipe returns a public recipe of an enabled user, without
+// counting the view, if its share satisfies the given SQL condition
+func getPublicRecipe(code string, condition string) (Recipe, error) {
 	var RID int
 	var recipe Recipe
 
 	// Scans the recipe
-	err := db.QueryRow(`SELECT rid, name, stars, ingredients, directions, notes, code FROM recipes WHERE code=$1;`, code).
-		Scan(&RID, &recipe.Name, &recipe.Stars, &recipe.Ingredients, &recipe.Directions, &recipe.Notes, &recipe.Code)
+	err := db.QueryRow(`SELECT r.rid, r.name, r.stars, r.servings, r.ingredients, r.directions, r.notes, r.code
+						FROM recipes r INNER JOIN shares s ON s.code=r.code INNER JOIN ca_users u ON u.uid=r.uid
+						WHERE r.code=$1 AND NOT u.disabled AND `+condition+`;`, code).
+		Scan(&RID, &recipe.Name, &recipe.Stars, &recipe.Servings, &recipe.Ingredients, &recipe.Directions, &recipe.Notes, &recipe.Code)
 	if err != nil {
 		return recipe, ERR_RECIPE_NOT_FOUND
 	}
@@ -273,53 +359,86 @@ func (r Recipes) New(name string) (int, error) {
 	return RID, nil
 }
 
-// Save creates a copy of a public recipe and returns its RID
+// copyRecipe creates, within the transaction, a copy of a recipe that
+// remembers where it comes from (see GetFork), and returns its RID.
+// If the user already has a recipe with the same name,
+// ERR_RECIPE_DUPLICATED is returned and nothing is changed.
+func (r Recipes) copyRecipe(tx *sql.Tx, original Recipe, originRID int, code string, collection bool, author string) (int, error) {
+	var RID int
+
+	// Creates the copy (skipping the duplicates without aborting the
+	// transaction), without stars since its cooking log is empty
+	err := tx.QueryRow(`INSERT INTO recipes (uid, name, servings, ingredients, directions, notes)
+						VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (uid, name) DO NOTHING RETURNING rid;`,
+		r.uid, original.Name, max(original.Servings, 1),
+		original.Ingredients, original.Directions, original.Notes).Scan(&RID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RID, ERR_RECIPE_DUPLICATED
+	} else if err != nil {
+		return RID, ERR_UNKNOWN
+	}
+
+	// Copies the tags
+	for _, tag := range original.Tags {
+		if _, err = tx.Exec(`INSERT INTO tags (name, rid) VALUES ($1, $2);`, tag, RID); err != nil {
+			return RID, ERR_UNKNOWN
+		}
+	}
+
+	// Saves the attribution
+	return RID, addFork(tx, RID, originRID, code, collection, author, original)
+}
+
+// Save creates a copy of a public recipe and returns its RID.
+// The copy remembers where it comes from (see GetFork).
 func (r Recipes) Save(code string) (int, error) {
 	var RID int
 
-	// Gets the original
-	original, err := GetPublicRecipe(code)
+	// Ensures the user exists
+	if _, err := GetUser("UID", r.uid); err != nil {
+		return RID, err
+	}
+
+	// Gets the original: whoever has seen the last allowed view
+	// must still be able to save it
+	original, err := getPublicRecipe(code, shareValid)
 	if err != nil {
 		return RID, err
 	}
 
-	// Creates a new one
-	copiedRID, err := r.New(original.Name)
+	// Gets its RID and author
+	originRID, author, err := getOrigin(code)
 	if err != nil {
-		return copiedRID, err
+		return RID, err
 	}
 
-	// Saves the content
-	return copiedRID, r.Edit(copiedRID, original)
-}
+	// Creates the copy
+	tx, err := db.Begin()
+	if err != nil {
+		return RID, ERR_UNKNOWN
+	}
+	defer tx.Rollback()
 
-// Share creates a code for a recipe
-func (r Recipes) Share(RID int) (string, error) {
-	// Ensures the recipe (and the user) exist
-	if _, err := r.GetOne(RID); err != nil {
-		return "", err
-	}
-
-	for true {
-		// Generates the code
-		buffer := make([]byte, 4)
-		rand.Read(buffer)
-		code := fmt.Sprintf("%x", buffer)
-
-		// Saves it
-		_, err := db.Exec(`UPDATE recipes SET code=$2 WHERE rid=$1;`, RID, code)
-		if err != nil {
-			if pqe, ok := err.(*pq.Error); ok && pqe.Code == "23505" {
-				continue
-			} else {
-				return "", ERR_UNKNOWN
-			}
-		} else {
-			return code, nil
-		}
+	if RID, err = r.copyRecipe(tx, original, originRID, code, false, author); err != nil {
+		return RID, err
 	}
 
-	return "", nil
+	// Counts the save
+	if _, err = tx.Exec(`UPDATE shares SET saves=saves+1 WHERE code=$1;`, code); err != nil {
+		return RID, ERR_UNKNOWN
+	}
+
+	if err = tx.Commit(); err != nil {
+		return RID, ERR_UNKNOWN
+	}
+
+	return RID, nil
+}
+
+// Share creates a code for a recipe, that never expires.
+// The previous code (if any) is revoked.
+func (r Recipes) Share(RID int) (string, error) {
+	return r.ShareLimited(RID, nil, nil)
 }
 
 // Unshare deletes a recipe's code
@@ -329,8 +448,14 @@ func (r Recipes) Unshare(RID int) error {
 		return err
 	}
 
-	// Saves it
-	_, err := db.Exec(`UPDATE recipes SET code=NULL WHERE rid=$1;`, RID)
+	// Revokes it
+	_, err := db.Exec(`UPDATE shares SET revoked=NOW() WHERE rid=$1 AND revoked IS NULL;`, RID)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	// Deletes it
+	_, err = db.Exec(`UPDATE recipes SET code=NULL WHERE rid=$1;`, RID)
 	if err != nil {
 		return ERR_UNKNOWN
 	}
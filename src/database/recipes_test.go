@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"slices"
 	"testing"
+	"time"
 )
 
 func compareRecipes(t *testing.T, msg string, expected, got Recipe) {
@@ -144,7 +145,10 @@ func TestRecipesEdit(t *testing.T) {
 			got, _ := d.R.GetOne(d.RID)
 
 			if d.ExpectedErr == nil {
-				compareRecipes(t, msg, d.NewData, got)
+				// The stars are derived from the cooking log
+				expected := d.NewData
+				expected.Stars = 0
+				compareRecipes(t, msg, expected, got)
 			}
 		},
 
@@ -188,7 +192,7 @@ func TestRecipesGetAll(t *testing.T) {
 	RID1, _ := r.New("r1")
 	recipe1, _ := r.GetOne(RID1)
 	r.Edit(RID1, Recipe{
-		Name: "r1", Stars: 4, Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"vegan"},
+		Name: "r1", Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"vegan"},
 	})
 
 	RID2, _ := r.New("r2")
@@ -241,7 +245,7 @@ func TestRecipesGetOne(t *testing.T) {
 
 	RID, _ := r.New("r")
 	r.Edit(RID, Recipe{
-		Name: "newName", Stars: 4, Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"gluten free"},
+		Name: "newName", Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"gluten free"},
 	})
 	recipe, _ := r.GetOne(RID)
 
@@ -289,7 +293,7 @@ func TestRecipesGetPublic(t *testing.T) {
 
 	RID, _ := r.New("r")
 	r.Edit(RID, Recipe{
-		Name: "newName", Stars: 4, Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"dairy free"},
+		Name: "newName", Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"dairy free"},
 	})
 	code, _ := r.Share(RID)
 	recipe, _ := r.GetOne(RID)
@@ -332,24 +336,24 @@ func TestRecipesGetTags(t *testing.T) {
 	RID2, _ := r.New("r2")
 	recipe2, _ := r.GetOne(RID2)
 	r.Edit(RID2, Recipe{
-		Name: "r2", Stars: 2, Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"gluten free"},
+		Name: "r2", Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"gluten free"},
 	})
 
 	RID3, _ := r.New("r3")
 	recipe3, _ := r.GetOne(RID3)
 	r.Edit(RID3, Recipe{
-		Name: "r3", Stars: 3, Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"vegan", "gluten free"},
+		Name: "r3", Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"vegan", "gluten free"},
 	})
 
 	RID4, _ := r.New("r4")
 	r.Edit(RID4, Recipe{
-		Name: "r4", Stars: 4, Ingredients: "flour", Directions: "Mix", Notes: "-",
+		Name: "r4", Ingredients: "flour", Directions: "Mix", Notes: "-",
 	})
 
 	RID1, _ := r.New("r1")
 	recipe1, _ := r.GetOne(RID1)
 	r.Edit(RID1, Recipe{
-		Name: "r1", Stars: 1, Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"vegan"},
+		Name: "r1", Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"vegan"},
 	})
 
 	tags := []Tag{
@@ -470,8 +474,10 @@ func TestRecipesSave(t *testing.T) {
 
 	RID, _ := ownerR.New("recipe")
 	ownerR.Edit(RID, Recipe{
-		Name: "recipe", Stars: 4, Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"dairy free"},
+		Name: "recipe", Ingredients: "flour", Directions: "Mix", Notes: "-", Tags: []string{"dairy free"},
 	})
+	eight := 8
+	ownerR.AddCooking(RID, Cooking{Date: time.Now(), Stars: &eight})
 	code, _ := ownerR.Share(RID)
 	recipe, _ := ownerR.GetOne(RID)
 
@@ -501,6 +507,7 @@ func TestRecipesSave(t *testing.T) {
 				recipe, _ := d.R.GetOne(RID)
 				d.ExpectedRecipe.RID = RID
 				d.ExpectedRecipe.Code = nil
+				d.ExpectedRecipe.Stars = 0
 				compareRecipes(t, msg, d.ExpectedRecipe, recipe)
 			}
 		},
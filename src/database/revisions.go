@@ -0,0 +1,245 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Revision is a snapshot of a recipe, saved every time the recipe
+// is edited
+type Revision struct {
+	// RID is the Recipe ID
+	RID int
+
+	// Number identifies the revision among the recipe's ones.
+	// The current version of the recipe has Number=0.
+	Number int
+
+	// Created is when the revision has been replaced
+	Created time.Time
+
+	// Name is the name the recipe had
+	Name string
+
+	// Ingredients are the ingredients the recipe had
+	Ingredients string
+
+	// Directions are the directions the recipe had
+	Directions string
+
+	// Notes are the notes the recipe had
+	Notes string
+}
+
+// DiffLine is a line of a diff
+type DiffLine struct {
+	// Op is '+' if the line has been added, '-' if it has been
+	// removed, ' ' if it is unchanged
+	Op byte
+
+	// Text is the line content
+	Text string
+}
+
+// RevisionDiff contains the differences between a revision and
+// the next one
+type RevisionDiff struct {
+	// Old is the older revision
+	Old Revision
+
+	// New is the following revision (or the current recipe, with Number=0)
+	New Revision
+
+	// Ingredients is the diff of the ingredients
+	Ingredients []DiffLine
+
+	// Directions is the diff of the directions
+	Directions []DiffLine
+
+	// Notes is the diff of the notes
+	Notes []DiffLine
+}
+
+// splitLines splits a text in lines. An empty text has no lines.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// lcsTable returns the table of the lengths of the longest common
+// subsequences of the suffixes of a and b
+func lcsTable(a, b []string) [][]int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	return table
+}
+
+// diffLines returns the line-by-line diff between two texts
+func diffLines(old, new string) []DiffLine {
+	var diff []DiffLine
+
+	a, b := splitLines(old), splitLines(new)
+	table := lcsTable(a, b)
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			diff = append(diff, DiffLine{Op: ' ', Text: a[i]})
+			i++
+			j++
+		} else if table[i+1][j] >= table[i][j+1] {
+			diff = append(diff, DiffLine{Op: '-', Text: a[i]})
+			i++
+		} else {
+			diff = append(diff, DiffLine{Op: '+', Text: b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: '-', Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: '+', Text: b[j]})
+	}
+
+	return diff
+}
+
+// addRevision saves the given recipe's data as a new revision,
+// within the transaction that replaces it
+func addRevision(tx *sql.Tx, RID int, original Recipe) error {
+	_, err := tx.Exec(`INSERT INTO revisions (rid, number, name, ingredients, directions, notes)
+					   SELECT $1, COALESCE(MAX(number), 0)+1, $2, $3, $4, $5 FROM revisions WHERE rid=$1;`,
+		RID, original.Name, original.Ingredients, original.Directions, original.Notes)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// CompareRevision returns the differences between a revision
+// and the following one (or the current recipe, if it's the last one)
+func (r Recipes) CompareRevision(RID int, number int) (RevisionDiff, error) {
+	var diff RevisionDiff
+	var err error
+
+	// Gets the old revision
+	if diff.Old, err = r.GetRevision(RID, number); err != nil {
+		return diff, err
+	}
+
+	// Gets the new one
+	if diff.New, err = r.GetRevision(RID, number+1); err == ERR_REVISION_NOT_FOUND {
+		var current Recipe
+		if current, err = r.GetOne(RID); err != nil {
+			return diff, err
+		}
+
+		diff.New = Revision{
+			RID:         RID,
+			Name:        current.Name,
+			Ingredients: current.Ingredients,
+			Directions:  current.Directions,
+			Notes:       current.Notes,
+		}
+	} else if err != nil {
+		return diff, err
+	}
+
+	// Compares them
+	diff.Ingredients = diffLines(diff.Old.Ingredients, diff.New.Ingredients)
+	diff.Directions = diffLines(diff.Old.Directions, diff.New.Directions)
+	diff.Notes = diffLines(diff.Old.Notes, diff.New.Notes)
+	return diff, nil
+}
+
+// GetRevision returns a specific revision of a recipe
+func (r Recipes) GetRevision(RID int, number int) (Revision, error) {
+	var rev Revision
+
+	// Ensures the recipe (and the user) exist
+	if _, err := r.GetOne(RID); err != nil {
+		return rev, err
+	}
+
+	// Scans the revision
+	err := db.QueryRow(`SELECT rid, number, created, name, ingredients, directions, notes
+						FROM revisions WHERE rid=$1 AND number=$2;`, RID, number).
+		Scan(&rev.RID, &rev.Number, &rev.Created, &rev.Name, &rev.Ingredients, &rev.Directions, &rev.Notes)
+	if err != nil {
+		return rev, handleNoRowsError(err, r.uid, ERR_REVISION_NOT_FOUND)
+	}
+
+	return rev, nil
+}
+
+// GetRevisions returns all the revisions of a recipe (only with RID, Number,
+// Created and Name), from the newest to the oldest
+func (r Recipes) GetRevisions(RID int) ([]Revision, error) {
+	var revisions []Revision
+
+	// Ensures the recipe (and the user) exist
+	if _, err := r.GetOne(RID); err != nil {
+		return revisions, err
+	}
+
+	// Queries the revisions
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT rid, number, created, name FROM revisions WHERE rid=$1 ORDER BY number DESC;`, RID)
+	if err != nil {
+		return revisions, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var rev Revision
+		rows.Scan(&rev.RID, &rev.Number, &rev.Created, &rev.Name)
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, nil
+}
+
+// RestoreRevision replaces the recipe's name, ingredients, directions and
+// notes with the ones of a revision. The current data is saved in a new
+// revision, so that it can be restored too.
+func (r Recipes) RestoreRevision(RID int, number int) error {
+	// Gets the revision
+	rev, err := r.GetRevision(RID, number)
+	if err != nil {
+		return err
+	}
+
+	// Gets the current recipe
+	recipe, err := r.GetOne(RID)
+	if err != nil {
+		return err
+	}
+
+	// Replaces its content
+	recipe.Name = rev.Name
+	recipe.Ingredients = rev.Ingredients
+	recipe.Directions = rev.Directions
+	recipe.Notes = rev.Notes
+	return r.Edit(RID, recipe)
+}
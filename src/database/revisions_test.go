@@ -0,0 +1,223 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	type data struct {
+		Old string
+		New string
+
+		Expected []DiffLine
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if got := diffLines(d.Old, d.New); !reflect.DeepEqual(got, d.Expected) {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.Expected, got)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"(empty)",
+				data{},
+			},
+			{
+				"(added)",
+				data{Old: "", New: "a\nb", Expected: []DiffLine{{'+', "a"}, {'+', "b"}}},
+			},
+			{
+				"(removed)",
+				data{Old: "a\nb", New: "a", Expected: []DiffLine{{' ', "a"}, {'-', "b"}}},
+			},
+			{
+				"(replaced)",
+				data{Old: "a\nb\nc", New: "a\nd\nc", Expected: []DiffLine{{' ', "a"}, {'-', "b"}, {'+', "d"}, {' ', "c"}}},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesCompareRevision(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID, _ := r.New("r")
+	r.Edit(RID, Recipe{Name: "r", Ingredients: "flour\nwater", Directions: "Mix"})
+	r.Edit(RID, Recipe{Name: "r", Ingredients: "flour\nmilk", Directions: "Mix"})
+	r.Edit(RID, Recipe{Name: "r2", Ingredients: "flour\nmilk\neggs", Directions: "Mix"})
+
+	otherU, _ := getTestingUser(t)
+	otherR := otherU.Recipes()
+
+	type data struct {
+		R      Recipes
+		RID    int
+		Number int
+
+		ExpectedErr         error
+		ExpectedNewName     string
+		ExpectedIngredients []DiffLine
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			diff, err := d.R.CompareRevision(d.RID, d.Number)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if diff.New.Name != d.ExpectedNewName {
+					t.Errorf("%s: expected new name <%s>, got <%s>", msg, d.ExpectedNewName, diff.New.Name)
+				}
+
+				if !reflect.DeepEqual(diff.Ingredients, d.ExpectedIngredients) {
+					t.Errorf("%s: expected ingredients <%v>, got <%v>", msg, d.ExpectedIngredients, diff.Ingredients)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user compared revision",
+				data{R: otherR, RID: RID, Number: 1, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"compared unknown revision",
+				data{R: r, RID: RID, Number: 4, ExpectedErr: ERR_REVISION_NOT_FOUND},
+			},
+			{
+				"(with next revision)",
+				data{
+					R: r, RID: RID, Number: 2, ExpectedNewName: "r",
+					ExpectedIngredients: []DiffLine{{' ', "flour"}, {'-', "water"}, {'+', "milk"}},
+				},
+			},
+			{
+				"(with current recipe)",
+				data{
+					R: r, RID: RID, Number: 3, ExpectedNewName: "r2",
+					ExpectedIngredients: []DiffLine{{' ', "flour"}, {' ', "milk"}, {'+', "eggs"}},
+				},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesGetRevisions(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID, _ := r.New("r")
+	r.Edit(RID, Recipe{Name: "r", Ingredients: "flour"})
+	r.Edit(RID, Recipe{Name: "r2", Ingredients: "flour"})
+	r.Edit(RID, Recipe{Name: "r3", Ingredients: "flour"})
+
+	newRID, _ := r.New("new")
+
+	otherU, _ := getTestingUser(t)
+	otherR := otherU.Recipes()
+
+	sharedRID, _ := otherR.New("shared")
+	otherR.Edit(sharedRID, Recipe{Name: "shared", Ingredients: "eggs"})
+	code, _ := otherR.Share(sharedRID)
+	savedRID, _ := r.Save(code)
+
+	type data struct {
+		R   Recipes
+		RID int
+
+		ExpectedErr   error
+		ExpectedNames []string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			revisions, err := d.R.GetRevisions(d.RID)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				var names []string
+				for _, rev := range revisions {
+					names = append(names, rev.Name)
+				}
+
+				if !reflect.DeepEqual(names, d.ExpectedNames) {
+					t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedNames, names)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user got revisions",
+				data{R: otherR, RID: RID, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"(never edited)",
+				data{R: r, RID: newRID},
+			},
+			{
+				"(saved copy)",
+				data{R: r, RID: savedRID},
+			},
+			{
+				"",
+				data{R: r, RID: RID, ExpectedNames: []string{"r2", "r", "r"}},
+			},
+		},
+	}.Run(t)
+}
+
+func TestRecipesRestoreRevision(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID, _ := r.New("r")
+	r.Edit(RID, Recipe{Name: "r", Ingredients: "flour", Tags: []string{"BREAD"}})
+	r.Edit(RID, Recipe{Name: "r2", Ingredients: "flour\nwater", Tags: []string{"BREAD"}})
+
+	otherU, _ := getTestingUser(t)
+	otherR := otherU.Recipes()
+
+	type data struct {
+		R      Recipes
+		RID    int
+		Number int
+
+		ExpectedErr    error
+		ExpectedRecipe Recipe
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.R.RestoreRevision(d.RID, d.Number); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				got, _ := d.R.GetOne(d.RID)
+				compareRecipes(t, msg, d.ExpectedRecipe, got)
+
+				if revisions, _ := d.R.GetRevisions(d.RID); len(revisions) != 3 {
+					t.Errorf("%s: current version not saved", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user restored revision",
+				data{R: otherR, RID: RID, Number: 1, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"restored unknown revision",
+				data{R: r, RID: RID, Number: 3, ExpectedErr: ERR_REVISION_NOT_FOUND},
+			},
+			{
+				"",
+				data{R: r, RID: RID, Number: 2, ExpectedRecipe: Recipe{Name: "r", Ingredients: "flour", Tags: []string{"BREAD"}}},
+			},
+		},
+	}.Run(t)
+}
@@ -6,11 +6,15 @@ CREATE TABLE ca_users (
     username VARCHAR(250) NOT NULL,
     password VARCHAR(250) NOT NULL,
     email VARCHAR(250) NOT NULL,
-    token VARCHAR(250),
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    pending_email VARCHAR(250),
 
     email_lang CHAR(2),
 	newsletter CHAR(16),
 
+    admin BOOLEAN NOT NULL DEFAULT FALSE,
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+
     PRIMARY KEY (uid),
     UNIQUE (username),
     UNIQUE (email),
@@ -23,6 +27,8 @@ CREATE TABLE menus (
     mid SERIAL NOT NULL,
 
     name VARCHAR(64) NOT NULL,
+    start DATE,
+    slots VARCHAR(64)[] NOT NULL DEFAULT '{}',
 
     PRIMARY KEY (mid),
     FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE
@@ -36,24 +42,50 @@ CREATE TABLE days (
 
     name VARCHAR(64) NOT NULL,
     meals VARCHAR(512)[],
+    updated TIMESTAMP NOT NULL DEFAULT NOW(),
 
     PRIMARY KEY (mid, position) DEFERRABLE INITIALLY IMMEDIATE,
     FOREIGN KEY (mid) REFERENCES menus (mid) ON DELETE CASCADE
 );
 
+CREATE TABLE menu_templates (
+    uid INT NOT NULL,
+    tid SERIAL NOT NULL,
+
+    name VARCHAR(64) NOT NULL,
+    slots VARCHAR(64)[] NOT NULL DEFAULT '{}',
+
+    PRIMARY KEY (tid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE,
+    UNIQUE (uid, name)
+);
+
+CREATE TABLE template_days (
+    tid INT NOT NULL,
+    position INT NOT NULL,
+
+    meals VARCHAR(512)[] NOT NULL DEFAULT '{}',
+
+    PRIMARY KEY (tid, position),
+    FOREIGN KEY (tid) REFERENCES menu_templates (tid) ON DELETE CASCADE
+);
+
 
 CREATE TABLE sections (
     uid INT NOT NULL,
     sid SERIAL NOT NULL,
+    parent INT,
 
     name VARCHAR(128) NOT NULL,
+    storage VARCHAR(16) NOT NULL DEFAULT '',
 
     PRIMARY KEY (sid),
     FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE,
-    UNIQUE (uid, name)
+    FOREIGN KEY (parent) REFERENCES sections (sid) ON DELETE CASCADE
 );
 
 CREATE INDEX sections_uid ON sections (uid);
+CREATE UNIQUE INDEX sections_uid_parent_name ON sections (uid, COALESCE(parent, 0), name);
 
 CREATE TABLE articles (
     sid INT NOT NULL,
@@ -62,6 +94,10 @@ CREATE TABLE articles (
     name VARCHAR(250) NOT NULL,
     quantity FLOAT,
     expiration DATE NOT NULL,
+    category VARCHAR(32) NOT NULL DEFAULT '',
+    opened DATE,
+    frozen DATE,
+    updated TIMESTAMP NOT NULL DEFAULT NOW(),
 
     PRIMARY KEY (aid),
     FOREIGN KEY (sid) REFERENCES sections (sid) ON DELETE CASCADE,
@@ -70,6 +106,17 @@ CREATE TABLE articles (
 
 CREATE INDEX articles_sid_expiration ON articles (sid, expiration, aid);
 
+CREATE TABLE shelf_lives (
+    uid INT NOT NULL,
+    category VARCHAR(32) NOT NULL,
+    storage VARCHAR(16) NOT NULL,
+
+    days INT NOT NULL,
+
+    PRIMARY KEY (uid, category, storage),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE
+);
+
 
 CREATE TABLE entries (
     uid INT NOT NULL,
@@ -92,12 +139,13 @@ CREATE TABLE recipes (
 
     name VARCHAR(64) NOT NULL,
     stars INT NOT NULL DEFAULT 0,
+    servings INT NOT NULL DEFAULT 1,
 
     ingredients VARCHAR(4096) NOT NULL DEFAULT '',
     directions VARCHAR(4096) NOT NULL DEFAULT '',
     notes VARCHAR(4096) NOT NULL DEFAULT '',
 
-	code CHAR(8),
+	code VARCHAR(32),
 
     PRIMARY KEY (rid),
     FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE,
@@ -117,3 +165,235 @@ CREATE TABLE tags (
 );
 
 CREATE INDEX tags_name ON tags (name);
+
+CREATE TABLE revisions (
+    rid INT NOT NULL,
+    number INT NOT NULL,
+    created TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    name VARCHAR(64) NOT NULL,
+    ingredients VARCHAR(4096) NOT NULL DEFAULT '',
+    directions VARCHAR(4096) NOT NULL DEFAULT '',
+    notes VARCHAR(4096) NOT NULL DEFAULT '',
+
+    PRIMARY KEY (rid, number),
+    FOREIGN KEY (rid) REFERENCES recipes (rid) ON DELETE CASCADE
+);
+
+CREATE TABLE forks (
+    rid INT NOT NULL,
+    origin INT,
+
+    code VARCHAR(32) NOT NULL,
+    collection BOOLEAN NOT NULL DEFAULT FALSE,
+    author VARCHAR(250) NOT NULL,
+
+    ingredients VARCHAR(4096) NOT NULL DEFAULT '',
+    directions VARCHAR(4096) NOT NULL DEFAULT '',
+
+    PRIMARY KEY (rid),
+    FOREIGN KEY (rid) REFERENCES recipes (rid) ON DELETE CASCADE,
+    FOREIGN KEY (origin) REFERENCES recipes (rid) ON DELETE SET NULL
+);
+
+CREATE TABLE collections (
+    uid INT NOT NULL,
+    tag VARCHAR NOT NULL,
+    code VARCHAR(32) NOT NULL,
+
+    PRIMARY KEY (uid, tag),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE,
+    UNIQUE (code)
+);
+
+CREATE TABLE dietary_profiles (
+    uid INT NOT NULL,
+
+    allergens VARCHAR(32)[] NOT NULL DEFAULT '{}',
+    diets VARCHAR(32)[] NOT NULL DEFAULT '{}',
+
+    PRIMARY KEY (uid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE
+);
+
+CREATE TABLE profiles (
+    uid INT NOT NULL,
+    bio VARCHAR(1024) NOT NULL DEFAULT '',
+
+    PRIMARY KEY (uid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE
+);
+
+CREATE TABLE shares (
+    code VARCHAR(32) NOT NULL,
+    rid INT NOT NULL,
+
+    created TIMESTAMP NOT NULL DEFAULT NOW(),
+    expiration DATE,
+    max_views INT,
+    revoked TIMESTAMP,
+
+    views INT NOT NULL DEFAULT 0,
+    saves INT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (code),
+    FOREIGN KEY (rid) REFERENCES recipes (rid) ON DELETE CASCADE
+);
+
+CREATE TABLE cookings (
+    rid INT NOT NULL,
+    cid SERIAL NOT NULL,
+
+    date DATE NOT NULL,
+    cook VARCHAR(64) NOT NULL DEFAULT '',
+    stars INT,
+    comments VARCHAR(1024) NOT NULL DEFAULT '',
+
+    PRIMARY KEY (cid),
+    FOREIGN KEY (rid) REFERENCES recipes (rid) ON DELETE CASCADE
+);
+
+CREATE INDEX cookings_rid_date ON cookings (rid, date);
+
+CREATE TABLE nutrients (
+    name VARCHAR(250) NOT NULL,
+
+    kcal REAL NOT NULL DEFAULT 0,
+    proteins REAL NOT NULL DEFAULT 0,
+    carbohydrates REAL NOT NULL DEFAULT 0,
+    fats REAL NOT NULL DEFAULT 0,
+    piece REAL,
+
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE calendars (
+    uid INT NOT NULL,
+
+    token VARCHAR(32) NOT NULL,
+
+    PRIMARY KEY (uid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE,
+    UNIQUE (token)
+);
+
+CREATE TABLE totp (
+    uid INT NOT NULL,
+
+    secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_step BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (uid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE
+);
+
+CREATE TABLE recovery_codes (
+    rcid SERIAL NOT NULL,
+    uid INT NOT NULL,
+
+    hash VARCHAR(250) NOT NULL,
+
+    PRIMARY KEY (rcid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE
+);
+
+CREATE INDEX recovery_codes_uid ON recovery_codes (uid);
+
+CREATE TABLE passkeys (
+    pkid SERIAL NOT NULL,
+    uid INT NOT NULL,
+
+    credential VARCHAR(1400) NOT NULL,
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    name VARCHAR(64) NOT NULL,
+    created TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_used TIMESTAMP,
+
+    PRIMARY KEY (pkid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE,
+    UNIQUE (credential)
+);
+
+CREATE TABLE user_tokens (
+    tid SERIAL NOT NULL,
+    uid INT NOT NULL,
+
+    purpose VARCHAR(32) NOT NULL,
+    hash VARCHAR(250) NOT NULL,
+    expires TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (tid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE,
+    UNIQUE (uid, purpose)
+);
+
+CREATE TABLE user_sessions (
+    sid SERIAL NOT NULL,
+    uid INT NOT NULL,
+
+    key VARCHAR(64) NOT NULL,
+    user_agent VARCHAR(250) NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    created TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (sid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE,
+    UNIQUE (key)
+);
+
+CREATE INDEX user_sessions_uid ON user_sessions (uid);
+
+CREATE TABLE user_identities (
+    uid INT NOT NULL,
+
+    issuer VARCHAR(250) NOT NULL,
+    subject VARCHAR(250) NOT NULL,
+
+    PRIMARY KEY (issuer, subject),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE
+);
+
+CREATE INDEX user_identities_uid ON user_identities (uid);
+
+CREATE TABLE audit_log (
+    aeid SERIAL NOT NULL,
+
+    admin VARCHAR(250) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    target VARCHAR(250) NOT NULL,
+    time TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (aeid)
+);
+
+CREATE TABLE stats_history (
+    day DATE NOT NULL,
+
+    users INT NOT NULL,
+    menus INT NOT NULL,
+    sections INT NOT NULL,
+    articles INT NOT NULL,
+    entries INT NOT NULL,
+    recipes INT NOT NULL,
+
+    PRIMARY KEY (day)
+);
+
+CREATE TABLE security_events (
+    eid SERIAL NOT NULL,
+    uid INT,
+
+    username VARCHAR(250) NOT NULL,
+    event VARCHAR(32) NOT NULL,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(250) NOT NULL DEFAULT '',
+    time TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (eid),
+    FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE SET NULL
+);
+
+CREATE INDEX security_events_uid ON security_events (uid);
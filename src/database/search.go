@@ -0,0 +1,180 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// SearchResults contains everything that matched a search, grouped by type.
+// Every group is ordered by relevance.
+type SearchResults struct {
+	// Recipes contains the matching recipes (with only RID and Name)
+	Recipes []Recipe
+
+	// Articles contains the matching articles
+	Articles []Article
+
+	// Menus contains the matching menus, each with only the matching days
+	Menus []Menu
+
+	// Entries contains the matching entries of the shopping list
+	Entries []Entry
+}
+
+// IsEmpty returns true if nothing has been found
+func (sr SearchResults) IsEmpty() bool {
+	return len(sr.Recipes) == 0 && len(sr.Articles) == 0 &&
+		len(sr.Menus) == 0 && len(sr.Entries) == 0
+}
+
+// Search looks for query in the user's recipes (name, ingredients, directions,
+// notes and tags), articles, menus (days names and meals) and shopping list,
+// using PostgreSQL's full-text search.
+// Config is the text search configuration (es. "english", "italian") used to
+// stem the words; if it's unknown, the "simple" one is used instead.
+func (u User) Search(query string, config string) (SearchResults, error) {
+	var res SearchResults
+	var err error
+
+	// Ensures the user exists
+	if _, err = GetUser("UID", u.UID); err != nil {
+		return res, err
+	}
+
+	// Ensures the query is not empty
+	if strings.TrimSpace(query) == "" {
+		return res, nil
+	}
+
+	// Ensures the config exists
+	var found int
+	db.QueryRow(`SELECT 1 FROM pg_ts_config WHERE cfgname=$1;`, config).Scan(&found)
+	if found == 0 {
+		config = "simple"
+	}
+
+	if res.Recipes, err = searchRecipes(u.UID, query, config); err != nil {
+		return res, err
+	} else if res.Articles, err = searchArticles(u.UID, query, config); err != nil {
+		return res, err
+	} else if res.Menus, err = searchMenus(u.UID, query, config); err != nil {
+		return res, err
+	} else if res.Entries, err = searchEntries(u.UID, query, config); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// searchRecipes is used by Search to look into the recipes
+func searchRecipes(UID int, query string, config string) ([]Recipe, error) {
+	var recipes []Recipe
+
+	rows, err := db.Query(`SELECT r.rid, r.name FROM recipes r,
+						   websearch_to_tsquery($2::regconfig, $3) q,
+						   setweight(to_tsvector($2::regconfig, r.name), 'A') ||
+						   setweight(to_tsvector($2::regconfig, COALESCE((SELECT string_agg(t.name, ' ') FROM tags t WHERE t.rid=r.rid), '')), 'B') ||
+						   setweight(to_tsvector($2::regconfig, r.ingredients), 'B') ||
+						   setweight(to_tsvector($2::regconfig, r.directions || ' ' || r.notes), 'C') doc
+						   WHERE r.uid=$1 AND doc @@ q
+						   ORDER BY ts_rank(doc, q) DESC, r.name;`, UID, config, query)
+	if err != nil {
+		return recipes, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var r Recipe
+		rows.Scan(&r.RID, &r.Name)
+		recipes = append(recipes, r)
+	}
+
+	return recipes, nil
+}
+
+// searchArticles is used by Search to look into the storage
+func searchArticles(UID int, query string, config string) ([]Article, error) {
+	var articles []Article
+
+	rows, err := db.Query(`SELECT a.sid, a.aid, a.name, a.expiration, a.quantity
+						   FROM articles a INNER JOIN sections s ON s.sid=a.sid,
+						   websearch_to_tsquery($2::regconfig, $3) q,
+						   to_tsvector($2::regconfig, a.name) doc
+						   WHERE s.uid=$1 AND doc @@ q
+						   ORDER BY ts_rank(doc, q) DESC, a.expiration, a.aid;`, UID, config, query)
+	if err != nil {
+		return articles, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var a Article
+		rows.Scan(&a.SID, &a.AID, &a.Name, &a.Expiration, &a.Quantity)
+		a.fixExpiration()
+		articles = append(articles, a)
+	}
+
+	return articles, nil
+}
+
+// searchMenus is used by Search to look into the menus. Only the matching days
+// are returned.
+func searchMenus(UID int, query string, config string) ([]Menu, error) {
+	var menus []Menu
+
+	rows, err := db.Query(`SELECT m.mid, m.name, d.name, d.position, d.meals,
+						   MAX(ts_rank(doc, q)) OVER (PARTITION BY m.mid) AS mrank
+						   FROM menus m INNER JOIN days d ON d.mid=m.mid,
+						   websearch_to_tsquery($2::regconfig, $3) q,
+						   setweight(to_tsvector($2::regconfig, d.name), 'B') ||
+						   setweight(to_tsvector($2::regconfig, array_to_string(d.meals, ' ')), 'A') doc
+						   WHERE m.uid=$1 AND doc @@ q
+						   ORDER BY mrank DESC, m.mid, d.position;`, UID, config, query)
+	if err != nil {
+		return menus, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var menu Menu
+		var day Day
+		var rank float64
+
+		rows.Scan(&menu.MID, &menu.Name, &day.Name, &day.Position, pq.Array(&day.Meals), &rank)
+		day.MID = menu.MID
+
+		if len(menus) == 0 || menus[len(menus)-1].MID != menu.MID {
+			menus = append(menus, menu)
+		}
+
+		menus[len(menus)-1].Days = append(menus[len(menus)-1].Days, day)
+	}
+
+	return menus, nil
+}
+
+// searchEntries is used by Search to look into the shopping list
+func searchEntries(UID int, query string, config string) ([]Entry, error) {
+	var entries []Entry
+
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT e.eid, e.name, e.marked FROM entries e,
+						   websearch_to_tsquery($2::regconfig, $3) q,
+						   to_tsvector($2::regconfig, e.name) doc
+						   WHERE e.uid=$1 AND doc @@ q
+						   ORDER BY ts_rank(doc, q) DESC, e.name;`, UID, config, query)
+	if err != nil {
+		return entries, ERR_UNKNOWN
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var e Entry
+		rows.Scan(&e.EID, &e.Name, &e.Marked)
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
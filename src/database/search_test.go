@@ -0,0 +1,124 @@
+package database
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestUserSearch(t *testing.T) {
+	u, _ := getTestingUser(t)
+
+	RID1, _ := u.Recipes().New("Tomato soup")
+	u.Recipes().Edit(RID1, Recipe{Name: "Tomato soup", Ingredients: "tomatoes\nonions", Directions: "Boil everything"})
+	RID2, _ := u.Recipes().New("Carbonara")
+	u.Recipes().Edit(RID2, Recipe{Name: "Carbonara", Ingredients: "eggs\nbacon", Tags: []string{"PASTA"}})
+
+	SID, _ := u.Storage().NewSection("Fridge")
+	u.Storage().AddArticles(StringArticle{Name: "Tomatoes", Section: strconv.Itoa(SID)})
+	article, _ := u.Storage().GetArticles(SID, "")
+
+	MID, _ := u.Menus().New("Week", []string{"Monday", "Tuesday"}, 0)
+	u.Menus().SetDayMeals(MID, 1, []string{"tomato soup", "pizza"})
+
+	u.ShoppingList().Append("tomatoes", "bread")
+	entries, _ := u.ShoppingList().GetAll()
+
+	otherU, _ := getTestingUser(t)
+	otherU.Recipes().New("Tomato pie")
+
+	type data struct {
+		U      User
+		Query  string
+		Config string
+
+		ExpectedErr      error
+		ExpectedRecipes  []int
+		ExpectedArticles []int
+		ExpectedDays     []int
+		ExpectedEntries  []int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			res, err := d.U.Search(d.Query, d.Config)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+				return
+			}
+
+			var recipes, articles, days, entries []int
+			for _, r := range res.Recipes {
+				recipes = append(recipes, r.RID)
+			}
+			for _, a := range res.Articles {
+				articles = append(articles, a.AID)
+			}
+			for _, m := range res.Menus {
+				if m.MID != MID {
+					t.Errorf("%s: unexpected menu <%d>", msg, m.MID)
+				}
+
+				for _, d := range m.Days {
+					days = append(days, d.Position)
+				}
+			}
+			for _, e := range res.Entries {
+				entries = append(entries, e.EID)
+			}
+
+			compareIDs(t, msg+", recipes", d.ExpectedRecipes, recipes)
+			compareIDs(t, msg+", articles", d.ExpectedArticles, articles)
+			compareIDs(t, msg+", days", d.ExpectedDays, days)
+			compareIDs(t, msg+", entries", d.ExpectedEntries, entries)
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user searched",
+				data{U: unknownUser, Query: "tomato", Config: "english", ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"(empty query)",
+				data{U: u, Query: " ", Config: "english"},
+			},
+			{
+				"(no results)",
+				data{U: u, Query: "banana", Config: "english"},
+			},
+			{
+				"(stemmed)",
+				data{
+					U: u, Query: "tomato", Config: "english",
+					ExpectedRecipes:  []int{RID1},
+					ExpectedArticles: []int{article.Articles[0].AID},
+					ExpectedDays:     []int{1},
+					ExpectedEntries:  []int{entries[1].EID},
+				},
+			},
+			{
+				"(tags)",
+				data{U: u, Query: "pasta", Config: "english", ExpectedRecipes: []int{RID2}},
+			},
+			{
+				"(unknown config)",
+				data{U: u, Query: "bacon", Config: "klingon", ExpectedRecipes: []int{RID2}},
+			},
+		},
+	}.Run(t)
+}
+
+// compareIDs ensures two lists of IDs contain the same elements, in the
+// same order
+func compareIDs(t *testing.T, msg string, expected, got []int) {
+	if len(expected) != len(got) {
+		t.Errorf("%s: expected <%v>, got <%v>", msg, expected, got)
+		return
+	}
+
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Errorf("%s: expected <%v>, got <%v>", msg, expected, got)
+			return
+		}
+	}
+}
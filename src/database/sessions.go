@@ -0,0 +1,164 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// sessionDuration is how long a session is kept after it has
+// been seen the last time
+const sessionDuration = 90 * 24 * time.Hour
+
+// touchInterval is how often the last use of a session is updated
+const touchInterval = time.Minute
+
+// maxUserAgent is the maximum length of a saved user agent
+const maxUserAgent = 250
+
+// Session is a device on which the user has signed in
+type Session struct {
+	// SID is the Session ID
+	SID int
+
+	// UserAgent is the user agent of the device
+	UserAgent string
+
+	// IP is the last IP address of the device
+	IP string
+
+	// Created is when the user has signed in
+	Created time.Time
+
+	// LastSeen is when the session has been used the last time
+	LastSeen time.Time
+
+	// Current is true if it's the session the list has been requested from
+	Current bool
+}
+
+// FormatLastSeen returns the time of the last use as a string
+func (s Session) FormatLastSeen() string {
+	return s.LastSeen.Format(time.DateTime)
+}
+
+// truncateUserAgent ensures a user agent is not too long to be saved
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > maxUserAgent {
+		return userAgent[:maxUserAgent]
+	}
+
+	return userAgent
+}
+
+// NewSession starts tracking a session of the user. The key is the
+// ID of the session in the store.
+func (u User) NewSession(key string, userAgent string, IP string) error {
+	_, err := db.Exec(`INSERT INTO user_sessions (key, uid, user_agent, ip) VALUES ($1, $2, $3, $4)
+					   ON CONFLICT (key) DO UPDATE SET uid=EXCLUDED.uid, user_agent=EXCLUDED.user_agent,
+					   ip=EXCLUDED.ip, created=NOW(), last_seen=NOW();`,
+		key, u.UID, truncateUserAgent(userAgent), IP)
+	if err != nil {
+		return handleNoRowsError(sql.ErrNoRows, u.UID, ERR_UNKNOWN)
+	}
+
+	return nil
+}
+
+// TouchSession ensures a session of the user has not been revoked,
+// then updates its user agent, IP and last use (only if it hasn't
+// been seen for touchInterval, to avoid writing on every request)
+func (u User) TouchSession(key string, userAgent string, IP string) error {
+	var stale bool
+	err := db.QueryRow(`SELECT last_seen < NOW() - MAKE_INTERVAL(secs => $3) FROM user_sessions WHERE key=$1 AND uid=$2;`,
+		key, u.UID, touchInterval.Seconds()).Scan(&stale)
+	if err != nil {
+		return handleNoRowsError(err, u.UID, ERR_SESSION_NOT_FOUND)
+	} else if !stale {
+		return nil
+	}
+
+	_, err = db.Exec(`UPDATE user_sessions SET user_agent=$3, ip=$4, last_seen=NOW() WHERE key=$1 AND uid=$2;`,
+		key, u.UID, truncateUserAgent(userAgent), IP)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// GetSessions returns the sessions of the user, from the last seen.
+// The one with the given key is marked as current.
+func (u User) GetSessions(currentKey string) ([]Session, error) {
+	var sessions []Session
+
+	// Queries the sessions
+	rows, err := db.Query(`SELECT sid, user_agent, ip, created, last_seen, key=$2 FROM user_sessions
+						   WHERE uid=$1 ORDER BY last_seen DESC, sid DESC;`, u.UID, currentKey)
+	if err != nil {
+		return sessions, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var s Session
+		rows.Scan(&s.SID, &s.UserAgent, &s.IP, &s.Created, &s.LastSeen, &s.Current)
+		sessions = append(sessions, s)
+	}
+
+	// If no sessions have been found, makes sure the user exists
+	if len(sessions) == 0 {
+		_, err := GetUser("UID", u.UID)
+		return sessions, err
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs the user out from a session
+func (u User) RevokeSession(SID int) error {
+	res, err := db.Exec(`DELETE FROM user_sessions WHERE uid=$1 AND sid=$2;`, u.UID, SID)
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra < 1 {
+		return handleNoRowsError(sql.ErrNoRows, u.UID, ERR_SESSION_NOT_FOUND)
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions signs the user out from every session
+// but the one with the given key (if empty, from all of them)
+func (u User) RevokeOtherSessions(currentKey string) error {
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`DELETE FROM user_sessions WHERE uid=$1 AND key<>$2;`, u.UID, currentKey)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// DropSession stops tracking a session (when the user signs out)
+func DropSession(key string) error {
+	if _, err := db.Exec(`DELETE FROM user_sessions WHERE key=$1;`, key); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// CleanupSessions deletes the sessions that haven't been
+// seen for too long
+func CleanupSessions() error {
+	_, err := db.Exec(`DELETE FROM user_sessions WHERE last_seen < NOW() - $1 * INTERVAL '1 second';`,
+		int(sessionDuration.Seconds()))
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+)
+
+var testingSessionsN int = 0
+
+// getTestingSession starts a new session of the user, and returns its key
+func getTestingSession(t *testing.T, u User) string {
+	testingSessionsN++
+	key := fmt.Sprintf("key%d", testingSessionsN)
+
+	if err := u.NewSession(key, "agent", "127.0.0.1"); err != nil {
+		t.Fatalf("Cannot create testing session: %s", err.Error())
+	}
+
+	return key
+}
+
+// ageTestingSession makes a session look like it hasn't been
+// seen for longer than touchInterval
+func ageTestingSession(t *testing.T, key string) {
+	_, err := db.Exec(`UPDATE user_sessions SET last_seen=last_seen - MAKE_INTERVAL(secs => $2) WHERE key=$1;`,
+		key, 2*touchInterval.Seconds())
+	if err != nil {
+		t.Fatalf("Cannot age testing session: %s", err.Error())
+	}
+}
+
+func TestUserNewSession(t *testing.T) {
+	user, _ := getTestingUser(t)
+	otherUser, _ := getTestingUser(t)
+	otherKey := getTestingSession(t, otherUser)
+
+	type data struct {
+		U   User
+		Key string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.NewSession(d.Key, "agent", "127.0.0.1"); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if err = d.U.TouchSession(d.Key, "agent", "127.0.0.1"); err != nil {
+					t.Errorf("%s: session not saved", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"started session of unknown user", data{U: unknownUser, Key: "unknown", ExpectedErr: ERR_USER_UNKNOWN}},
+			{"", data{U: user, Key: "new"}},
+			{"(reused key)", data{U: user, Key: otherKey}},
+		},
+	}.Run(t)
+}
+
+func TestUserTouchSession(t *testing.T) {
+	user, _ := getTestingUser(t)
+	key := getTestingSession(t, user)
+	otherUser, _ := getTestingUser(t)
+
+	type data struct {
+		U     User
+		Key   string
+		Stale bool
+
+		ExpectedErr   error
+		ExpectedAgent string
+		ExpectedIP    string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if d.Stale {
+				ageTestingSession(t, d.Key)
+			}
+
+			if err := d.U.TouchSession(d.Key, "new agent", "127.0.0.2"); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				sessions, _ := d.U.GetSessions(d.Key)
+				if len(sessions) != 1 || sessions[0].UserAgent != d.ExpectedAgent || sessions[0].IP != d.ExpectedIP {
+					t.Errorf("%s: expected agent <%s> and IP <%s>, got <%+v>", msg, d.ExpectedAgent, d.ExpectedIP, sessions)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"touched session of unknown user", data{U: unknownUser, Key: key, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"touched session of another user", data{U: otherUser, Key: key, ExpectedErr: ERR_SESSION_NOT_FOUND}},
+			{"touched unknown session", data{U: user, Key: "unknown", ExpectedErr: ERR_SESSION_NOT_FOUND}},
+			{"(recently seen)", data{U: user, Key: key, ExpectedAgent: "agent", ExpectedIP: "127.0.0.1"}},
+			{"", data{U: user, Key: key, Stale: true, ExpectedAgent: "new agent", ExpectedIP: "127.0.0.2"}},
+		},
+	}.Run(t)
+}
+
+func TestUserGetSessions(t *testing.T) {
+	user, _ := getTestingUser(t)
+	key1 := getTestingSession(t, user)
+	key2 := getTestingSession(t, user)
+	ageTestingSession(t, key1)
+	user.TouchSession(key1, "agent", "127.0.0.1")
+	otherUser, _ := getTestingUser(t)
+
+	type data struct {
+		U          User
+		CurrentKey string
+
+		ExpectedCurrent []bool
+		ExpectedErr     error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			sessions, err := d.U.GetSessions(d.CurrentKey)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if len(sessions) != len(d.ExpectedCurrent) {
+				t.Errorf("%s: expected <%d> sessions, got <%d>", msg, len(d.ExpectedCurrent), len(sessions))
+			} else {
+				for i, s := range sessions {
+					if s.Current != d.ExpectedCurrent[i] {
+						t.Errorf("%s: expected current <%v>, got <%+v>", msg, d.ExpectedCurrent, sessions)
+						break
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"got sessions of unknown user", data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"(empty)", data{U: otherUser}},
+			{"", data{U: user, CurrentKey: key2, ExpectedCurrent: []bool{false, true}}},
+		},
+	}.Run(t)
+}
+
+func TestUserRevokeSession(t *testing.T) {
+	user, _ := getTestingUser(t)
+	key := getTestingSession(t, user)
+	sessions, _ := user.GetSessions(key)
+	otherUser, _ := getTestingUser(t)
+
+	type data struct {
+		U   User
+		SID int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.RevokeSession(d.SID); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if err = d.U.TouchSession(key, "agent", "127.0.0.1"); err != ERR_SESSION_NOT_FOUND {
+					t.Errorf("%s: session not revoked", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"revoked session of unknown user", data{U: unknownUser, SID: sessions[0].SID, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"revoked session of another user", data{U: otherUser, SID: sessions[0].SID, ExpectedErr: ERR_SESSION_NOT_FOUND}},
+			{"", data{U: user, SID: sessions[0].SID}},
+			{"revoked session twice", data{U: user, SID: sessions[0].SID, ExpectedErr: ERR_SESSION_NOT_FOUND}},
+		},
+	}.Run(t)
+}
+
+func TestUserRevokeOtherSessions(t *testing.T) {
+	user, _ := getTestingUser(t)
+	key := getTestingSession(t, user)
+	getTestingSession(t, user)
+	getTestingSession(t, user)
+
+	type data struct {
+		U          User
+		CurrentKey string
+
+		ExpectedLeft int
+		ExpectedErr  error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.RevokeOtherSessions(d.CurrentKey); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if sessions, _ := d.U.GetSessions(d.CurrentKey); len(sessions) != d.ExpectedLeft {
+					t.Errorf("%s: expected <%d> sessions left, got <%d>", msg, d.ExpectedLeft, len(sessions))
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"revoked sessions of unknown user", data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"", data{U: user, CurrentKey: key, ExpectedLeft: 1}},
+			{"(all)", data{U: user, ExpectedLeft: 0}},
+		},
+	}.Run(t)
+}
+
+func TestCleanupSessions(t *testing.T) {
+	user, _ := getTestingUser(t)
+	oldKey := getTestingSession(t, user)
+	key := getTestingSession(t, user)
+	db.Exec(`UPDATE user_sessions SET last_seen=NOW() - INTERVAL '100 days' WHERE key=$1;`, oldKey)
+
+	if err := CleanupSessions(); err != nil {
+		t.Errorf("expected no err, got <%v>", err)
+	}
+
+	if sessions, _ := user.GetSessions(key); len(sessions) != 1 || !sessions[0].Current {
+		t.Errorf("expected only the recent session, got <%+v>", sessions)
+	}
+}
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Share is a link to a public recipe
+type Share struct {
+	// Code is the random code of the link
+	Code string
+
+	// RID is the Recipe ID
+	RID int
+
+	// Name is the name of the recipe
+	Name string
+
+	// Created is when the link has been generated
+	Created time.Time
+
+	// Expiration is the last day in which the link works (nil if
+	// it never expires)
+	Expiration *time.Time
+
+	// MaxViews is the number of times the recipe can be seen through
+	// the link (nil if there's no limit)
+	MaxViews *int
+
+	// Views is the number of times the recipe has been seen
+	Views int
+
+	// Saves is the number of times the recipe has been saved
+	Saves int
+
+	// Revoked is when the link has been deleted or regenerated
+	// (nil if it hasn't)
+	Revoked *time.Time
+
+	// Active is true if the link can still be used
+	Active bool
+}
+
+// FormatExpiration returns the expiration as a string
+func (s Share) FormatExpiration() string {
+	return s.Expiration.Format(time.DateOnly)
+}
+
+// shareValid is the SQL condition that is true if the share s
+// has not been revoked and its expiration date has not passed
+const shareValid = `(s.revoked IS NULL AND (s.expiration IS NULL OR s.expiration >= CURRENT_DATE))`
+
+// shareActive is the SQL condition that is true if the share s
+// is valid and has not used up its views
+const shareActive = `(` + shareValid + ` AND (s.max_views IS NULL OR s.views < s.max_views))`
+
+// generateCode returns a new random code for a share link
+func generateCode() string {
+	buffer := make([]byte, 16)
+	rand.Read(buffer)
+	return fmt.Sprintf("%x", buffer)
+}
+
+// scanShare is used to scan a share from a row
+func scanShare(row interface{ Scan(...any) error }) (Share, error) {
+	var s Share
+	err := row.Scan(&s.Code, &s.RID, &s.Name, &s.Created, &s.Expiration,
+		&s.MaxViews, &s.Views, &s.Saves, &s.Revoked, &s.Active)
+	return s, err
+}
+
+// shareFields are the fields read by scanShare
+const shareFields = `s.code, s.rid, r.name, s.created, s.expiration, s.max_views,
+					 s.views, s.saves, s.revoked, ` + shareActive
+
+// GetShare returns the current share of a recipe
+func (r Recipes) GetShare(RID int) (Share, error) {
+	// Ensures the recipe (and the user) exist
+	if _, err := r.GetOne(RID); err != nil {
+		return Share{}, err
+	}
+
+	// Scans the share
+	share, err := scanShare(db.QueryRow(`SELECT `+shareFields+` FROM shares s
+										 INNER JOIN recipes r ON r.code=s.code WHERE r.rid=$1;`, RID))
+	if err != nil {
+		return share, handleNoRowsError(err, r.uid, ERR_SHARE_NOT_FOUND)
+	}
+
+	return share, nil
+}
+
+// GetShares returns all the shares of the user's recipes, from the
+// newest to the oldest, including the revoked and the expired ones
+func (r Recipes) GetShares() ([]Share, error) {
+	var shares []Share
+
+	// Queries the shares
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT `+shareFields+` FROM shares s INNER JOIN recipes r
+						   ON r.rid=s.rid WHERE r.uid=$1 ORDER BY s.created DESC, s.code;`, r.uid)
+	if err != nil {
+		return shares, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		if share, err := scanShare(rows); err == nil {
+			shares = append(shares, share)
+		}
+	}
+
+	// If no shares have been found, makes sure the user exists
+	if len(shares) == 0 {
+		_, err := GetUser("UID", r.uid)
+		return shares, err
+	}
+
+	return shares, nil
+}
+
+// ShareLimited creates a code for a recipe, like Share, that stops working
+// after the expiration day or after it has been seen maxViews times.
+// Both limits are optional. The previous code (if any) is revoked.
+func (r Recipes) ShareLimited(RID int, expiration *time.Time, maxViews *int) (string, error) {
+	// Ensures the recipe (and the user) exist
+	if _, err := r.GetOne(RID); err != nil {
+		return "", err
+	}
+
+	// Ensures the limits are valid
+	if maxViews != nil && *maxViews < 1 {
+		return "", ERR_SHARE_LIMITS_INVALID
+	} else if expiration != nil && expiration.Before(time.Now().Truncate(24*time.Hour)) {
+		return "", ERR_SHARE_LIMITS_INVALID
+	}
+
+	for true {
+		// Generates the code
+		code := generateCode()
+
+		// Saves it
+		_, err := db.Exec(`INSERT INTO shares (code, rid, expiration, max_views) VALUES ($1, $2, $3, $4);`,
+			code, RID, expiration, maxViews)
+		if err != nil {
+			if pqe, ok := err.(*pq.Error); ok && pqe.Code == "23505" {
+				continue
+			} else {
+				return "", ERR_UNKNOWN
+			}
+		}
+
+		// Revokes the old one
+		_, err = db.Exec(`UPDATE shares SET revoked=NOW() WHERE rid=$1 AND code!=$2 AND revoked IS NULL;`, RID, code)
+		if err != nil {
+			return "", ERR_UNKNOWN
+		}
+
+		// Uses the new one
+		_, err = db.Exec(`UPDATE recipes SET code=$2 WHERE rid=$1;`, RID, code)
+		if err != nil {
+			return "", ERR_UNKNOWN
+		}
+
+		return code, nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecipesShareLimited(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID, _ := r.New("recipe")
+
+	otherU, _ := getTestingUser(t)
+	otherR := otherU.Recipes()
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	zero, two := 0, 2
+
+	type data struct {
+		R          Recipes
+		RID        int
+		Expiration *time.Time
+		MaxViews   *int
+
+		ExpectedErr   error
+		ExpectedViews int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			code, err := d.R.ShareLimited(d.RID, d.Expiration, d.MaxViews)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				// Ensures the recipe can be seen only ExpectedViews times
+				for n := 0; n < d.ExpectedViews; n++ {
+					if _, err = GetPublicRecipe(code); err != nil {
+						t.Errorf("%s: view %d: expected err <nil>, got <%v>", msg, n+1, err)
+					}
+				}
+
+				if _, err = GetPublicRecipe(code); err != ERR_RECIPE_NOT_FOUND {
+					t.Errorf("%s: expected link to be expired, got <%v>", msg, err)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user shared recipe",
+				data{R: otherR, RID: RID, ExpectedErr: ERR_RECIPE_NOT_FOUND},
+			},
+			{
+				"shared with zero views",
+				data{R: r, RID: RID, MaxViews: &zero, ExpectedErr: ERR_SHARE_LIMITS_INVALID},
+			},
+			{
+				"shared with past expiration",
+				data{R: r, RID: RID, Expiration: &yesterday, ExpectedErr: ERR_SHARE_LIMITS_INVALID},
+			},
+			{
+				"(max views)",
+				data{R: r, RID: RID, Expiration: &tomorrow, MaxViews: &two, ExpectedViews: 2},
+			},
+		},
+	}.Run(t)
+}
+
+func TestGetPublicRecipeConcurrent(t *testing.T) {
+	u, _ := getTestingUser(t)
+	RID, _ := u.Recipes().New("recipe")
+	three := 3
+	code, _ := u.Recipes().ShareLimited(RID, nil, &three)
+
+	// Views the recipe many times at once
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	served := 0
+	for n := 0; n < 20; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := GetPublicRecipe(code); err == nil {
+				mu.Lock()
+				served++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if served != three {
+		t.Errorf("expected <%d> views served, got <%d>", three, served)
+	}
+
+	if share, _ := u.Recipes().GetShare(RID); share.Views != three || share.Active {
+		t.Errorf("expected <%d> views and inactive link, got <%v>", three, share)
+	}
+}
+
+func TestRecipesSaveLastView(t *testing.T) {
+	u, _ := getTestingUser(t)
+	RID, _ := u.Recipes().New("recipe")
+	one := 1
+	code, _ := u.Recipes().ShareLimited(RID, nil, &one)
+	GetPublicRecipe(code)
+
+	saver, _ := getTestingUser(t)
+	if _, err := saver.Recipes().Save(code); err != nil {
+		t.Errorf("expected err <nil>, got <%v>", err)
+	}
+
+	u.Recipes().Unshare(RID)
+	if _, err := saver.Recipes().Save(code); err != ERR_RECIPE_NOT_FOUND {
+		t.Errorf("revoked link: expected err <%v>, got <%v>", ERR_RECIPE_NOT_FOUND, err)
+	}
+}
+
+func TestRecipesGetShares(t *testing.T) {
+	u, _ := getTestingUser(t)
+	r := u.Recipes()
+
+	RID, _ := r.New("recipe")
+	oldCode, _ := r.Share(RID)
+	code, _ := r.Share(RID)
+	GetPublicRecipe(code)
+	GetPublicRecipe(code)
+
+	saver, _ := getTestingUser(t)
+	saver.Recipes().Save(code)
+
+	type data struct {
+		R Recipes
+
+		ExpectedErr    error
+		ExpectedShares []Share
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			shares, err := d.R.GetShares()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if len(shares) != len(d.ExpectedShares) {
+				t.Errorf("%s: expected <%d> shares, got <%d>", msg, len(d.ExpectedShares), len(shares))
+			} else {
+				for i, expected := range d.ExpectedShares {
+					got := shares[i]
+					if got.Code != expected.Code || got.Views != expected.Views ||
+						got.Saves != expected.Saves || got.Active != expected.Active ||
+						(got.Revoked == nil) == (expected.Revoked != nil) {
+						t.Errorf("%s: expected <%v>, got <%v>", msg, expected, got)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user got shares",
+				data{R: unknownUser.Recipes(), ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"",
+				data{
+					R: r,
+					ExpectedShares: []Share{
+						{Code: code, Views: 2, Saves: 1, Active: true},
+						{Code: oldCode, Revoked: &time.Time{}},
+					},
+				},
+			},
+		},
+	}.Run(t)
+}
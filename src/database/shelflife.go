@@ -0,0 +1,182 @@
+package database
+
+import (
+	"slices"
+	"time"
+)
+
+// Category is a group of articles that last the same time once
+// opened or frozen
+type Category string
+
+const (
+	CATEGORY_BREAD      Category = "bread"
+	CATEGORY_CHEESE     Category = "cheese"
+	CATEGORY_DAIRY      Category = "dairy"
+	CATEGORY_FISH       Category = "fish"
+	CATEGORY_FRUIT      Category = "fruit"
+	CATEGORY_LEFTOVERS  Category = "leftovers"
+	CATEGORY_MEAT       Category = "meat"
+	CATEGORY_SAUCES     Category = "sauces"
+	CATEGORY_VEGETABLES Category = "vegetables"
+)
+
+// Categories contains the categories a user can choose
+var Categories = []Category{
+	CATEGORY_BREAD, CATEGORY_CHEESE, CATEGORY_DAIRY, CATEGORY_FISH,
+	CATEGORY_FRUIT, CATEGORY_LEFTOVERS, CATEGORY_MEAT, CATEGORY_SAUCES,
+	CATEGORY_VEGETABLES,
+}
+
+// StorageType is the kind of place in which an article is stored
+type StorageType string
+
+const (
+	STORAGE_FRIDGE  StorageType = "fridge"
+	STORAGE_FREEZER StorageType = "freezer"
+	STORAGE_PANTRY  StorageType = "pantry"
+)
+
+// StorageTypes contains the storage types a section can have
+var StorageTypes = []StorageType{STORAGE_FRIDGE, STORAGE_FREEZER, STORAGE_PANTRY}
+
+// ShelfLives contains, for every category and storage type, for how many
+// days an article lasts after being opened (or frozen, in the freezer).
+// A missing value means that the article should not be kept there.
+type ShelfLives map[Category]map[StorageType]int
+
+// DefaultShelfLives contains the shelf lives used when a user has not
+// set them
+var DefaultShelfLives = ShelfLives{
+	CATEGORY_BREAD:      {STORAGE_FRIDGE: 7, STORAGE_FREEZER: 90, STORAGE_PANTRY: 3},
+	CATEGORY_CHEESE:     {STORAGE_FRIDGE: 21, STORAGE_FREEZER: 180},
+	CATEGORY_DAIRY:      {STORAGE_FRIDGE: 4, STORAGE_FREEZER: 90},
+	CATEGORY_FISH:       {STORAGE_FRIDGE: 2, STORAGE_FREEZER: 90},
+	CATEGORY_FRUIT:      {STORAGE_FRIDGE: 5, STORAGE_FREEZER: 240, STORAGE_PANTRY: 3},
+	CATEGORY_LEFTOVERS:  {STORAGE_FRIDGE: 3, STORAGE_FREEZER: 90},
+	CATEGORY_MEAT:       {STORAGE_FRIDGE: 3, STORAGE_FREEZER: 180},
+	CATEGORY_SAUCES:     {STORAGE_FRIDGE: 14, STORAGE_FREEZER: 120, STORAGE_PANTRY: 30},
+	CATEGORY_VEGETABLES: {STORAGE_FRIDGE: 5, STORAGE_FREEZER: 240, STORAGE_PANTRY: 5},
+}
+
+// Get returns the shelf life of a category in a storage type, and false
+// if the article should not be kept there
+func (sl ShelfLives) Get(category Category, storage StorageType) (int, bool) {
+	days, found := sl[category][storage]
+	return days, found
+}
+
+// EffectiveExpiration returns the date in which an article expires, given
+// the storage type of its section: if it has been frozen, it lasts as much as
+// its category lasts in the freezer (and the printed expiration is ignored);
+// if it has been opened, it expires when its category's shelf life ends, or
+// on the printed expiration if it comes first.
+// It returns nil if the article has not been opened or frozen, or if there
+// is no rule for it.
+func (sl ShelfLives) EffectiveExpiration(a Article, storage StorageType) *time.Time {
+	var from *time.Time
+	if a.Frozen != nil {
+		from, storage = a.Frozen, STORAGE_FREEZER
+	} else if a.Opened != nil {
+		from = a.Opened
+	} else {
+		return nil
+	}
+
+	days, found := sl.Get(a.Category, storage)
+	if !found {
+		return nil
+	}
+
+	effective := from.AddDate(0, 0, days)
+	if a.Frozen == nil && a.Expiration != nil && a.Expiration.Before(effective) {
+		effective = *a.Expiration
+	}
+
+	return &effective
+}
+
+// GetShelfLives returns the user's shelf lives (the default ones, replaced
+// by the ones set by the user)
+func (u User) GetShelfLives() (ShelfLives, error) {
+	shelfLives := make(ShelfLives)
+	for category, values := range DefaultShelfLives {
+		shelfLives[category] = make(map[StorageType]int)
+		for storage, days := range values {
+			shelfLives[category][storage] = days
+		}
+	}
+
+	// Ensures the user exists
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return shelfLives, err
+	}
+
+	// Queries the user's rules
+	rows, err := db.Query(`SELECT category, storage, days FROM shelf_lives WHERE uid=$1;`, u.UID)
+	if err != nil {
+		return shelfLives, ERR_UNKNOWN
+	}
+
+	// Replaces the defaults (a negative value removes them)
+	defer rows.Close()
+	for rows.Next() {
+		var category Category
+		var storage StorageType
+		var days int
+		rows.Scan(&category, &storage, &days)
+
+		if _, found := shelfLives[category]; !found {
+			shelfLives[category] = make(map[StorageType]int)
+		}
+		if days < 0 {
+			delete(shelfLives[category], storage)
+		} else {
+			shelfLives[category][storage] = days
+		}
+	}
+
+	return shelfLives, nil
+}
+
+// SetShelfLife sets the shelf life of a category in a storage type.
+// If days is nil, the articles of that category should not be kept there.
+func (u User) SetShelfLife(category Category, storage StorageType, days *int) error {
+	// Ensures the values are valid
+	if !slices.Contains(Categories, category) || !slices.Contains(StorageTypes, storage) || (days != nil && *days < 0) {
+		return ERR_SHELF_LIFE_INVALID
+	}
+
+	// Ensures the user exists
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return err
+	}
+
+	// Saves the value (-1 means that there is no shelf life)
+	value := -1
+	if days != nil {
+		value = *days
+	}
+	_, err := db.Exec(`INSERT INTO shelf_lives (uid, category, storage, days) VALUES ($1, $2, $3, $4)
+					   ON CONFLICT (uid, category, storage) DO UPDATE SET days=excluded.days;`,
+		u.UID, category, storage, value)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// ResetShelfLives restores the default shelf lives
+func (u User) ResetShelfLives() error {
+	// Ensures the user exists
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(`DELETE FROM shelf_lives WHERE uid=$1;`, u.UID); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
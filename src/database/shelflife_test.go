@@ -0,0 +1,265 @@
+package database
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestShelfLivesEffectiveExpiration(t *testing.T) {
+	date := func(month time.Month, day int) *time.Time {
+		d := time.Date(2025, month, day, 0, 0, 0, 0, dateLocale)
+		return &d
+	}
+
+	type data struct {
+		Article Article
+		Storage StorageType
+
+		Expected *time.Time
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if got := DefaultShelfLives.EffectiveExpiration(d.Article, d.Storage); !reflect.DeepEqual(got, d.Expected) {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.Expected, got)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"not opened",
+				data{Article: Article{Category: CATEGORY_MEAT, Expiration: date(3, 20)}, Storage: STORAGE_FRIDGE},
+			},
+			{
+				"no category",
+				data{Article: Article{Opened: date(3, 1)}, Storage: STORAGE_FRIDGE},
+			},
+			{
+				"no storage type",
+				data{Article: Article{Category: CATEGORY_MEAT, Opened: date(3, 1)}},
+			},
+			{
+				"no rule",
+				data{Article: Article{Category: CATEGORY_MEAT, Opened: date(3, 1)}, Storage: STORAGE_PANTRY},
+			},
+			{
+				"opened",
+				data{Article: Article{Category: CATEGORY_MEAT, Opened: date(3, 1), Expiration: date(3, 20)}, Storage: STORAGE_FRIDGE, Expected: date(3, 4)},
+			},
+			{
+				"opened after the printed expiration",
+				data{Article: Article{Category: CATEGORY_MEAT, Opened: date(3, 1), Expiration: date(3, 2)}, Storage: STORAGE_FRIDGE, Expected: date(3, 2)},
+			},
+			{
+				"frozen in the fridge",
+				data{Article: Article{Category: CATEGORY_FISH, Opened: date(3, 1), Frozen: date(3, 2), Expiration: date(3, 3)}, Storage: STORAGE_FRIDGE, Expected: date(5, 31)},
+			},
+		},
+	}.Run(t)
+}
+
+func TestUserSetShelfLife(t *testing.T) {
+	u, _ := getTestingUser(t)
+	days := 10
+	negative := -1
+
+	type data struct {
+		U        User
+		Category Category
+		Storage  StorageType
+		Days     *int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.SetShelfLife(d.Category, d.Storage, d.Days); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				shelfLives, _ := d.U.GetShelfLives()
+				if got, found := shelfLives.Get(d.Category, d.Storage); found != (d.Days != nil) || (found && got != *d.Days) {
+					t.Errorf("%s: expected <%v>, got <%d> <%v>", msg, d.Days, got, found)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user set shelf life",
+				data{U: unknownUser, Category: CATEGORY_MEAT, Storage: STORAGE_FRIDGE, Days: &days, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"set shelf life of unknown category",
+				data{U: u, Category: "sweets", Storage: STORAGE_FRIDGE, Days: &days, ExpectedErr: ERR_SHELF_LIFE_INVALID},
+			},
+			{
+				"set shelf life of unknown storage type",
+				data{U: u, Category: CATEGORY_MEAT, Storage: "cellar", Days: &days, ExpectedErr: ERR_SHELF_LIFE_INVALID},
+			},
+			{
+				"set negative shelf life",
+				data{U: u, Category: CATEGORY_MEAT, Storage: STORAGE_FRIDGE, Days: &negative, ExpectedErr: ERR_SHELF_LIFE_INVALID},
+			},
+			{
+				"",
+				data{U: u, Category: CATEGORY_MEAT, Storage: STORAGE_FRIDGE, Days: &days},
+			},
+			{
+				"removed shelf life",
+				data{U: u, Category: CATEGORY_BREAD, Storage: STORAGE_PANTRY},
+			},
+			{
+				"added shelf life",
+				data{U: u, Category: CATEGORY_MEAT, Storage: STORAGE_PANTRY, Days: &days},
+			},
+		},
+	}.Run(t)
+}
+
+func TestUserResetShelfLives(t *testing.T) {
+	u, _ := getTestingUser(t)
+	days := 10
+	u.SetShelfLife(CATEGORY_MEAT, STORAGE_FRIDGE, &days)
+
+	type data struct {
+		U User
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.ResetShelfLives(); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if shelfLives, _ := d.U.GetShelfLives(); !reflect.DeepEqual(shelfLives, DefaultShelfLives) {
+					t.Errorf("%s: expected the default shelf lives, got <%v>", msg, shelfLives)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"unknown user reset shelf lives",
+				data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"",
+				data{U: u},
+			},
+		},
+	}.Run(t)
+}
+
+func TestStorageSetSectionType(t *testing.T) {
+	u, _ := getTestingUser(t)
+	s := u.Storage()
+
+	SID, _ := s.NewSection("Freezer")
+
+	otherU, _ := getTestingUser(t)
+
+	type data struct {
+		S       Storage
+		SID     int
+		Storage StorageType
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.S.SetSectionType(d.SID, d.Storage); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if section, _ := d.S.GetSection(d.SID); section.Type != d.Storage {
+					t.Errorf("%s: expected type <%s>, got <%s>", msg, d.Storage, section.Type)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user set section type",
+				data{S: otherU.Storage(), SID: SID, Storage: STORAGE_FREEZER, ExpectedErr: ERR_SECTION_NOT_FOUND},
+			},
+			{
+				"set invalid section type",
+				data{S: s, SID: SID, Storage: "cellar", ExpectedErr: ERR_STORAGE_TYPE_INVALID},
+			},
+			{
+				"",
+				data{S: s, SID: SID, Storage: STORAGE_FREEZER},
+			},
+			{
+				"",
+				data{S: s, SID: SID, Storage: ""},
+			},
+		},
+	}.Run(t)
+}
+
+func TestStorageEffectiveExpiration(t *testing.T) {
+	u, _ := getTestingUser(t)
+	s := u.Storage()
+
+	SID1, _ := s.NewSection("Fridge")
+	s.SetSectionType(SID1, STORAGE_FRIDGE)
+	SID2, _ := s.NewSubsection(SID1, "Drawer")
+	s.AddArticles(StringArticle{
+		Name: "Ham", Section: strconv.Itoa(SID2), Expiration: "2025-03-20",
+		Category: string(CATEGORY_MEAT), Opened: "2025-03-01",
+	})
+	section, _ := s.GetArticles(SID2, "")
+	AID := section.Articles[0].AID
+
+	type data struct {
+		Data StringArticle
+
+		ExpectedErr       error
+		ExpectedEffective string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if d.Data.Name != "" {
+				if err := s.EditArticle(AID, d.Data); err != d.ExpectedErr {
+					t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+					return
+				} else if err != nil {
+					return
+				}
+			}
+
+			article, _ := s.GetArticle(AID)
+			if article.Effective == nil || article.FormatEffective() != d.ExpectedEffective {
+				t.Errorf("%s: expected effective expiration <%s>, got <%v>", msg, d.ExpectedEffective, article.Effective)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"inherited storage type",
+				data{ExpectedEffective: "2025-03-04"},
+			},
+			{
+				"edited with invalid category",
+				data{Data: StringArticle{Name: "Ham", Section: strconv.Itoa(SID2), Category: "sweets"}, ExpectedErr: ERR_ARTICLE_CATEGORY_INVALID},
+			},
+			{
+				"edited with invalid date",
+				data{Data: StringArticle{Name: "Ham", Section: strconv.Itoa(SID2), Frozen: "yesterday"}, ExpectedErr: ERR_ARTICLE_DATE_INVALID},
+			},
+			{
+				"frozen",
+				data{Data: StringArticle{
+					Name: "Ham", Section: strconv.Itoa(SID2), Expiration: "2025-03-20",
+					Category: string(CATEGORY_MEAT), Opened: "2025-03-01", Frozen: "2025-03-02",
+				}, ExpectedEffective: "2025-08-29"},
+			},
+		},
+	}.Run(t)
+}
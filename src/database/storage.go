@@ -4,7 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -39,6 +41,22 @@ type Article struct {
 	// Quantity is the quantity of the article.
 	// It may be nil
 	Quantity *float32
+
+	// Category is used to find how long the article lasts once
+	// opened or frozen (it may be empty)
+	Category Category
+
+	// Opened is the date in which the article has been opened.
+	// It may be nil
+	Opened *time.Time
+
+	// Frozen is the date in which the article has been frozen.
+	// It may be nil
+	Frozen *time.Time
+
+	// Effective is the expiration computed from the opening or freezing
+	// date (see ShelfLives.EffectiveExpiration). It may be nil
+	Effective *time.Time
 }
 
 // fixExpiration sets a nil expiration if it's the default
@@ -48,18 +66,45 @@ func (a *Article) fixExpiration() {
 	}
 }
 
+// Expires returns the effective expiration if there is one,
+// otherwise the printed one (which may be nil)
+func (a Article) Expires() *time.Time {
+	if a.Effective != nil {
+		return a.Effective
+	}
+
+	return a.Expiration
+}
+
 // FormatExpiration returns the expiration as a string
 func (a Article) FormatExpiration() string {
 	return a.Expiration.Format(time.DateOnly)
 }
 
+// FormatEffective returns the effective expiration as a string
+func (a Article) FormatEffective() string {
+	return a.Effective.Format(time.DateOnly)
+}
+
+// FormatOpened returns the opening date as a string
+func (a Article) FormatOpened() string {
+	return a.Opened.Format(time.DateOnly)
+}
+
+// FormatFrozen returns the freezing date as a string
+func (a Article) FormatFrozen() string {
+	return a.Frozen.Format(time.DateOnly)
+}
+
 func (a Article) FormatQuantity() string {
 	return strconv.FormatFloat(float64(*a.Quantity), 'f', -1, 32)
 }
 
-// IsExpired returns true if the article is expired
+// IsExpired returns true if the article is expired (considering the
+// effective expiration, if there is one)
 func (a Article) IsExpired() bool {
-	return a.Expiration != nil && a.Expiration.Before(time.Now())
+	expires := a.Expires()
+	return expires != nil && expires.Before(time.Now())
 }
 
 // Section is a named collection of articles
@@ -67,20 +112,64 @@ type Section struct {
 	// Section is the Section ID
 	SID int
 
+	// Parent is the SID of the section that contains this one
+	// (0 if it is a top-level section)
+	Parent int
+
 	// Name is the name of the section
 	Name string
 
+	// Type is the storage type of the section. If it is empty, the
+	// section has the same type of its parent
+	Type StorageType
+
 	// Articles contains all the articles in this section
 	Articles []Article
 }
 
-// StringArticle is a container for name, quantity,
-// expiration and section as strings, used for inputs
+// SectionSummary is a section (without the articles) with some
+// information about its position in the tree of sections
+type SectionSummary struct {
+	Section
+
+	// FullName contains the names of the section and of all its
+	// ancestors, from the top-level one
+	FullName string
+
+	// Depth is the number of ancestors of the section
+	Depth int
+
+	// Count is the number of articles in the section and in all
+	// its subsections
+	Count int
+}
+
+// StringArticle is a container for name, quantity, expiration,
+// section, category and opening and freezing dates as strings,
+// used for inputs
 type StringArticle struct {
 	Section    string
 	Name       string
 	Quantity   string
 	Expiration string
+	Category   string
+	Opened     string
+	Frozen     string
+}
+
+// parseDate converts a date formatted like 2004-02-05 to a time,
+// or nil if empty
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, value, dateLocale)
+	if err != nil {
+		return nil, ERR_ARTICLE_DATE_INVALID
+	}
+
+	return &date, nil
 }
 
 // Parse converts a StringArticle into an Article.
@@ -121,6 +210,20 @@ func (sa StringArticle) Parse() (Article, error) {
 		}
 	}
 
+	// Converts the category
+	a.Category = Category(sa.Category)
+	if a.Category != "" && !slices.Contains(Categories, a.Category) {
+		return a, ERR_ARTICLE_CATEGORY_INVALID
+	}
+
+	// Converts the opening and freezing dates
+	if a.Opened, err = parseDate(sa.Opened); err != nil {
+		return a, err
+	}
+	if a.Frozen, err = parseDate(sa.Frozen); err != nil {
+		return a, err
+	}
+
 	a.Name = sa.Name
 	return a, nil
 }
@@ -158,7 +261,7 @@ func (s Storage) AddArticles(stringArticles ...StringArticle) error {
 	}
 
 	// Prepares the statement
-	stmt, err := db.Prepare(`INSERT INTO articles (sid, name, quantity, expiration) VALUES ($1, $2, $3, $4)
+	stmt, err := db.Prepare(`INSERT INTO articles (sid, name, quantity, expiration, category, opened, frozen) VALUES ($1, $2, $3, $4, $5, $6, $7)
                              ON CONFLICT (sid, name, expiration) DO UPDATE set quantity = articles.quantity+excluded.quantity;`)
 	defer stmt.Close()
 	if err != nil {
@@ -167,7 +270,7 @@ func (s Storage) AddArticles(stringArticles ...StringArticle) error {
 
 	// Inserts the entries
 	for _, a := range articles {
-		if _, err = stmt.Exec(a.SID, a.Name, a.Quantity, a.Expiration); err != nil {
+		if _, err = stmt.Exec(a.SID, a.Name, a.Quantity, a.Expiration, a.Category, a.Opened, a.Frozen); err != nil {
 			return ERR_UNKNOWN
 		}
 	}
@@ -222,13 +325,19 @@ func (s Storage) EditArticle(AID int, newData StringArticle) error {
 		if article.Name == parsed.Name &&
 			article.SID == parsed.SID &&
 			reflect.DeepEqual(article.Expiration, parsed.Expiration) &&
-			reflect.DeepEqual(article.Quantity, parsed.Quantity) {
+			reflect.DeepEqual(article.Quantity, parsed.Quantity) &&
+			article.Category == parsed.Category &&
+			reflect.DeepEqual(article.Opened, parsed.Opened) &&
+			reflect.DeepEqual(article.Frozen, parsed.Frozen) {
 			return nil
 		} else {
 			article.SID = parsed.SID
 			article.Name = parsed.Name
 			article.Expiration = parsed.Expiration
 			article.Quantity = parsed.Quantity
+			article.Category = parsed.Category
+			article.Opened = parsed.Opened
+			article.Frozen = parsed.Frozen
 		}
 	}
 
@@ -253,8 +362,10 @@ func (s Storage) EditArticle(AID int, newData StringArticle) error {
 	}
 
 	// Updates the article
-	_, err = db.Exec(`UPDATE articles SET name=$3, expiration=$4, quantity=$5, sid=$2 WHERE aid=$1;`,
-		AID, article.SID, article.Name, article.Expiration, article.Quantity)
+	_, err = db.Exec(`UPDATE articles SET name=$3, expiration=$4, quantity=$5, sid=$2,
+					  category=$6, opened=$7, frozen=$8, updated=NOW() WHERE aid=$1;`,
+		AID, article.SID, article.Name, article.Expiration, article.Quantity,
+		article.Category, article.Opened, article.Frozen)
 	if err != nil {
 		return ERR_UNKNOWN
 	}
@@ -275,10 +386,9 @@ func (s Storage) EditSection(SID int, newName string) error {
 		return nil
 	}
 
-	// Makes sure the new name is not used
-	var found int
-	db.QueryRow(`SELECT 1 FROM sections WHERE uid=$1 AND name=$2;`, s.uid, newName).Scan(&found)
-	if found > 0 {
+	// Makes sure the new name is not used by another section with
+	// the same parent
+	if s.isNameUsed(section.Parent, newName) {
 		return ERR_SECTION_DUPLICATED
 	}
 
@@ -295,8 +405,9 @@ func (s Storage) EditSection(SID int, newName string) error {
 func (s Storage) GetArticle(AID int) (Article, error) {
 	// Fetches the article
 	var article Article
-	err := db.QueryRow(`SELECT sid, aid, name, expiration, quantity FROM articles WHERE aid=$1;`, AID).
-		Scan(&article.SID, &article.AID, &article.Name, &article.Expiration, &article.Quantity)
+	err := db.QueryRow(`SELECT sid, aid, name, expiration, quantity, category, opened, frozen FROM articles WHERE aid=$1;`, AID).
+		Scan(&article.SID, &article.AID, &article.Name, &article.Expiration, &article.Quantity,
+			&article.Category, &article.Opened, &article.Frozen)
 
 	if err != nil {
 		return Article{}, handleNoRowsError(err, s.uid, ERR_ARTICLE_NOT_FOUND)
@@ -309,7 +420,13 @@ func (s Storage) GetArticle(AID int) (Article, error) {
 		return Article{}, ERR_ARTICLE_NOT_FOUND
 	}
 
-	return article, nil
+	// Computes the effective expiration
+	articles := []Article{article}
+	if err := s.setEffectiveExpirations(articles); err != nil {
+		return Article{}, err
+	}
+
+	return articles[0], nil
 }
 
 // GetArticles returns a section filled with its articles.
@@ -339,7 +456,7 @@ func (s Storage) GetArticles(SID int, nfilter string) (Section, error) {
 	}
 
 	// Runs the query
-	rows, err := db.Query(`SELECT sid, aid, name, expiration, quantity
+	rows, err := db.Query(`SELECT sid, aid, name, expiration, quantity, category, opened, frozen
 						   FROM articles WHERE sid = ANY($1) AND
 						   name ILIKE CONCAT('%', $2::VARCHAR, '%')
 						   ORDER BY expiration, aid;`, pq.Array(sids), nfilter)
@@ -351,12 +468,17 @@ func (s Storage) GetArticles(SID int, nfilter string) (Section, error) {
 	} else {
 		for rows.Next() {
 			var a Article
-			rows.Scan(&a.SID, &a.AID, &a.Name, &a.Expiration, &a.Quantity)
+			rows.Scan(&a.SID, &a.AID, &a.Name, &a.Expiration, &a.Quantity, &a.Category, &a.Opened, &a.Frozen)
 			a.fixExpiration()
 			section.Articles = append(section.Articles, a)
 		}
 	}
 
+	// Computes the effective expirations
+	if err = s.setEffectiveExpirations(section.Articles); err != nil {
+		return section, err
+	}
+
 	return section, nil
 }
 
@@ -392,12 +514,47 @@ func (s Storage) GetNeighbours(SID int, AID int) (int, int) {
 	return prev, next
 }
 
+// GetPath returns the ancestors of a section, from the top-level one,
+// followed by the section itself (without the articles)
+func (s Storage) GetPath(SID int) ([]Section, error) {
+	var path []Section
+
+	// Ensures the section (and the user) exist
+	section, err := s.GetSection(SID)
+	if err != nil {
+		return path, err
+	}
+
+	// Queries the ancestors
+	rows, err := db.Query(`WITH RECURSIVE ancestors AS (
+							   SELECT sid, parent, name, storage, 0 AS depth FROM sections WHERE sid=$1
+							   UNION ALL
+							   SELECT s.sid, s.parent, s.name, s.storage, a.depth+1 FROM sections s
+							   INNER JOIN ancestors a ON s.sid=a.parent
+						   ) SELECT sid, COALESCE(parent, 0), name, storage FROM ancestors
+						   WHERE depth > 0 ORDER BY depth DESC;`, SID)
+	if err != nil {
+		return path, ERR_UNKNOWN
+	}
+
+	// Appends them to the path
+	defer rows.Close()
+	for rows.Next() {
+		var s Section
+		rows.Scan(&s.SID, &s.Parent, &s.Name, &s.Type)
+		path = append(path, s)
+	}
+
+	return append(path, section), nil
+}
+
 // GetSection returns a specific section, without the articles
 func (s Storage) GetSection(SID int) (Section, error) {
 	var section Section
 
 	// Scans the section
-	err := db.QueryRow(`SELECT sid, name FROM sections WHERE uid=$1 AND sid=$2;`, s.uid, SID).Scan(&section.SID, &section.Name)
+	err := db.QueryRow(`SELECT sid, COALESCE(parent, 0), name, storage FROM sections WHERE uid=$1 AND sid=$2;`, s.uid, SID).
+		Scan(&section.SID, &section.Parent, &section.Name, &section.Type)
 	if err != nil {
 		return section, handleNoRowsError(err, s.uid, ERR_SECTION_NOT_FOUND)
 	}
@@ -411,7 +568,7 @@ func (s Storage) GetSections() ([]Section, error) {
 	var sections []Section
 
 	// Queries the sections
-	rows, err := db.Query(`SELECT sid, name FROM sections WHERE uid=$1;`, s.uid)
+	rows, err := db.Query(`SELECT sid, COALESCE(parent, 0), name, storage FROM sections WHERE uid=$1 ORDER BY sid;`, s.uid)
 	defer rows.Close()
 	if err != nil {
 		return sections, ERR_UNKNOWN
@@ -420,7 +577,7 @@ func (s Storage) GetSections() ([]Section, error) {
 	// Appends them to the list
 	for rows.Next() {
 		var s Section
-		rows.Scan(&s.SID, &s.Name)
+		rows.Scan(&s.SID, &s.Parent, &s.Name, &s.Type)
 		sections = append(sections, s)
 	}
 
@@ -433,27 +590,205 @@ func (s Storage) GetSections() ([]Section, error) {
 	return sections, nil
 }
 
-// NewSection tries to create a new section and returns its SID
+// GetSummaries returns all the sections created by an user, ordered as a
+// tree (every section is followed by its subsections), with the number
+// of articles they contain
+func (s Storage) GetSummaries() ([]SectionSummary, error) {
+	var summaries []SectionSummary
+
+	// Gets the sections
+	sections, err := s.GetSections()
+	if err != nil {
+		return summaries, err
+	}
+
+	// Counts the articles in every section
+	counts := make(map[int]int)
+	rows, err := db.Query(`SELECT a.sid, COUNT(*) FROM articles a INNER JOIN sections s ON s.sid=a.sid
+						   WHERE s.uid=$1 GROUP BY a.sid;`, s.uid)
+	if err != nil {
+		return summaries, ERR_UNKNOWN
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var SID, count int
+		rows.Scan(&SID, &count)
+		counts[SID] = count
+	}
+
+	// Groups the sections by parent, ordered by name
+	children := make(map[int][]Section)
+	for _, section := range sections {
+		children[section.Parent] = append(children[section.Parent], section)
+	}
+	for _, list := range children {
+		slices.SortFunc(list, func(a, b Section) int { return strings.Compare(a.Name, b.Name) })
+	}
+
+	// Visits the tree, and returns the number of articles in the subtree
+	var visit func(parent int, prefix string, depth int) int
+	visit = func(parent int, prefix string, depth int) int {
+		var total int
+		for _, section := range children[parent] {
+			i := len(summaries)
+			summaries = append(summaries, SectionSummary{
+				Section:  section,
+				FullName: prefix + section.Name,
+				Depth:    depth,
+			})
+
+			summaries[i].Count = counts[section.SID] + visit(section.SID, summaries[i].FullName+" / ", depth+1)
+			total += summaries[i].Count
+		}
+
+		return total
+	}
+	visit(0, "", 0)
+
+	return summaries, nil
+}
+
+// isNameUsed returns true if a child of the parent section
+// (or a top-level section, if parent is 0) has the name
+func (s Storage) isNameUsed(parent int, name string) bool {
+	var found bool
+	db.QueryRow(`SELECT 1 FROM sections WHERE uid=$1 AND COALESCE(parent, 0)=$2 AND name=$3;`, s.uid, parent, name).Scan(&found)
+	return found
+}
+
+// MoveSection moves a section (with its articles and its subsections)
+// inside another one, or to the top level if parent is 0
+func (s Storage) MoveSection(SID int, parent int) error {
+	// Gets the section
+	section, err := s.GetSection(SID)
+	if err != nil {
+		return err
+	}
+
+	// Makes sure the parent actually changes
+	if section.Parent == parent {
+		return nil
+	}
+
+	// Makes sure the new parent is not the section itself or one of its
+	// descendants
+	if parent != 0 {
+		path, err := s.GetPath(parent)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range path {
+			if ancestor.SID == SID {
+				return ERR_SECTION_PARENT_INVALID
+			}
+		}
+	}
+
+	// Makes sure the name is not used in the new parent
+	if s.isNameUsed(parent, section.Name) {
+		return ERR_SECTION_DUPLICATED
+	}
+
+	// Moves the section
+	_, err = db.Exec(`UPDATE sections SET parent=NULLIF($3, 0) WHERE uid=$1 AND sid=$2;`, s.uid, SID, parent)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
+
+// NewSection tries to create a new top-level section and returns its SID
 func (s Storage) NewSection(name string) (int, error) {
+	return s.NewSubsection(0, name)
+}
+
+// NewSubsection tries to create a new section inside another one (or a
+// top-level section, if parent is 0) and returns its SID
+func (s Storage) NewSubsection(parent int, name string) (int, error) {
 	var SID int
 
-	// Ensures the user exists
-	if _, err := GetUser("UID", s.uid); err != nil {
+	// Ensures the user (and the parent) exist
+	if parent == 0 {
+		if _, err := GetUser("UID", s.uid); err != nil {
+			return SID, err
+		}
+	} else if _, err := s.GetSection(parent); err != nil {
 		return SID, err
 	}
 
 	// Checks if the name is used
-	var found bool
-	db.QueryRow(`SELECT 1 FROM sections WHERE uid=$1 AND name=$2;`, s.uid, name).Scan(&found)
-	if found {
+	if s.isNameUsed(parent, name) {
 		return SID, ERR_SECTION_DUPLICATED
 	}
 
 	// Tries to save it in the database
-	err := db.QueryRow(`INSERT INTO sections (uid, name) VALUES ($1, $2) RETURNING sid;`, s.uid, name).Scan(&SID)
+	err := db.QueryRow(`INSERT INTO sections (uid, parent, name) VALUES ($1, NULLIF($2, 0), $3) RETURNING sid;`, s.uid, parent, name).Scan(&SID)
 	if err != nil {
 		return SID, ERR_UNKNOWN
 	}
 
 	return SID, nil
 }
+
+// setEffectiveExpirations computes the effective expirations of the
+// articles, using the user's shelf lives and the types of their sections
+func (s Storage) setEffectiveExpirations(articles []Article) error {
+	// Does nothing if no article has been opened or frozen
+	if !slices.ContainsFunc(articles, func(a Article) bool { return a.Opened != nil || a.Frozen != nil }) {
+		return nil
+	}
+
+	// Gets the shelf lives and the sections
+	shelfLives, err := User{UID: s.uid}.GetShelfLives()
+	if err != nil {
+		return err
+	}
+	sections, err := s.GetSections()
+	if err != nil {
+		return err
+	}
+
+	// Finds the type of every section (the untyped ones inherit it)
+	bySID := make(map[int]Section)
+	for _, section := range sections {
+		bySID[section.SID] = section
+	}
+	types := make(map[int]StorageType)
+	for _, section := range sections {
+		for current := section; ; current = bySID[current.Parent] {
+			if current.Type != "" || current.Parent == 0 {
+				types[section.SID] = current.Type
+				break
+			}
+		}
+	}
+
+	// Computes the expirations
+	for i := range articles {
+		articles[i].Effective = shelfLives.EffectiveExpiration(articles[i], types[articles[i].SID])
+	}
+
+	return nil
+}
+
+// SetSectionType changes the storage type of a section (it can be
+// empty, to use the one of the parent section)
+func (s Storage) SetSectionType(SID int, storage StorageType) error {
+	// Ensures the type is valid
+	if storage != "" && !slices.Contains(StorageTypes, storage) {
+		return ERR_STORAGE_TYPE_INVALID
+	}
+
+	// Ensures the section (and the user) exist
+	if _, err := s.GetSection(SID); err != nil {
+		return err
+	}
+
+	// Changes the type
+	if _, err := db.Exec(`UPDATE sections SET storage=$3 WHERE uid=$1 AND sid=$2;`, s.uid, SID, storage); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
@@ -826,3 +826,225 @@ func TestStorageNewSection(t *testing.T) {
 		},
 	}.Run(t)
 }
+
+func TestStorageNewSubsection(t *testing.T) {
+	u, _ := getTestingUser(t)
+	s := u.Storage()
+
+	SID, _ := s.NewSection("Freezer")
+	s.NewSection("Drawer")
+
+	otherU, _ := getTestingUser(t)
+	otherS := otherU.Storage()
+
+	type data struct {
+		S      Storage
+		Parent int
+		Name   string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			newSID, err := d.S.NewSubsection(d.Parent, d.Name)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				expected := Section{SID: newSID, Parent: d.Parent, Name: d.Name}
+				if section, _ := d.S.GetSection(newSID); !reflect.DeepEqual(section, expected) {
+					t.Errorf("%s: expected section <%v>, got <%v>", msg, expected, section)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"created subsection of other user's section",
+				data{S: otherS, Parent: SID, Name: "Drawer", ExpectedErr: ERR_SECTION_NOT_FOUND},
+			},
+			{
+				"created subsection of unknown section",
+				data{S: s, Parent: -1, Name: "Drawer", ExpectedErr: ERR_SECTION_NOT_FOUND},
+			},
+			{
+				"created subsection with the name of a top-level section",
+				data{S: s, Parent: SID, Name: "Drawer"},
+			},
+			{
+				"created duplicated subsection",
+				data{S: s, Parent: SID, Name: "Drawer", ExpectedErr: ERR_SECTION_DUPLICATED},
+			},
+		},
+	}.Run(t)
+}
+
+func TestStorageMoveSection(t *testing.T) {
+	u, _ := getTestingUser(t)
+	s := u.Storage()
+
+	SID1, _ := s.NewSection("Freezer")
+	SID2, _ := s.NewSubsection(SID1, "Drawer")
+	SID3, _ := s.NewSubsection(SID2, "Basket")
+	SID4, _ := s.NewSection("Pantry")
+	s.NewSubsection(SID4, "Basket")
+
+	otherU, _ := getTestingUser(t)
+	otherS := otherU.Storage()
+	otherSID, _ := otherS.NewSection("Fridge")
+
+	type data struct {
+		S      Storage
+		SID    int
+		Parent int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.S.MoveSection(d.SID, d.Parent); err != d.ExpectedErr {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if section, _ := d.S.GetSection(d.SID); section.Parent != d.Parent {
+					t.Errorf("%s: expected parent <%d>, got <%d>", msg, d.Parent, section.Parent)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user moved section",
+				data{S: otherS, SID: SID2, Parent: 0, ExpectedErr: ERR_SECTION_NOT_FOUND},
+			},
+			{
+				"moved section in other user's section",
+				data{S: s, SID: SID2, Parent: otherSID, ExpectedErr: ERR_SECTION_NOT_FOUND},
+			},
+			{
+				"moved section in itself",
+				data{S: s, SID: SID2, Parent: SID2, ExpectedErr: ERR_SECTION_PARENT_INVALID},
+			},
+			{
+				"moved section in its subsection",
+				data{S: s, SID: SID1, Parent: SID3, ExpectedErr: ERR_SECTION_PARENT_INVALID},
+			},
+			{
+				"moved section next to a section with the same name",
+				data{S: s, SID: SID3, Parent: SID4, ExpectedErr: ERR_SECTION_DUPLICATED},
+			},
+			{
+				"",
+				data{S: s, SID: SID3, Parent: SID1},
+			},
+			{
+				"",
+				data{S: s, SID: SID2, Parent: 0},
+			},
+		},
+	}.Run(t)
+}
+
+func TestStorageGetPath(t *testing.T) {
+	u, _ := getTestingUser(t)
+	s := u.Storage()
+
+	SID1, _ := s.NewSection("Freezer")
+	SID2, _ := s.NewSubsection(SID1, "Drawer")
+	SID3, _ := s.NewSubsection(SID2, "Basket")
+
+	otherU, _ := getTestingUser(t)
+
+	type data struct {
+		S   Storage
+		SID int
+
+		ExpectedErr  error
+		ExpectedPath []Section
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if path, err := d.S.GetPath(d.SID); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil && !reflect.DeepEqual(path, d.ExpectedPath) {
+				t.Errorf("%s: expected path <%v>, got <%v>", msg, d.ExpectedPath, path)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"got path of other user's section",
+				data{S: otherU.Storage(), SID: SID3, ExpectedErr: ERR_SECTION_NOT_FOUND},
+			},
+			{
+				"",
+				data{S: s, SID: SID1, ExpectedPath: []Section{{SID: SID1, Name: "Freezer"}}},
+			},
+			{
+				"",
+				data{S: s, SID: SID3, ExpectedPath: []Section{
+					{SID: SID1, Name: "Freezer"},
+					{SID: SID2, Parent: SID1, Name: "Drawer"},
+					{SID: SID3, Parent: SID2, Name: "Basket"},
+				}},
+			},
+		},
+	}.Run(t)
+}
+
+func TestStorageGetSummaries(t *testing.T) {
+	u, _ := getTestingUser(t)
+	s := u.Storage()
+
+	SID1, _ := s.NewSection("Pantry")
+	SID2, _ := s.NewSection("Freezer")
+	SID3, _ := s.NewSubsection(SID2, "Drawer")
+	SID4, _ := s.NewSubsection(SID3, "Basket")
+	s.AddArticles(
+		StringArticle{Name: "Pasta", Section: strconv.Itoa(SID1)},
+		StringArticle{Name: "Peas", Section: strconv.Itoa(SID2)},
+		StringArticle{Name: "Fish", Section: strconv.Itoa(SID3)},
+		StringArticle{Name: "Ice", Section: strconv.Itoa(SID4)},
+		StringArticle{Name: "Berries", Section: strconv.Itoa(SID4)},
+	)
+
+	otherU, _ := getTestingUser(t)
+
+	type data struct {
+		S Storage
+
+		ExpectedErr       error
+		ExpectedSummaries []SectionSummary
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if summaries, err := d.S.GetSummaries(); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil && !reflect.DeepEqual(summaries, d.ExpectedSummaries) {
+				t.Errorf("%s: expected summaries <%v>, got <%v>", msg, d.ExpectedSummaries, summaries)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"got summaries of unknown user",
+				data{S: unknownUser.Storage(), ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"(empty)",
+				data{S: otherU.Storage()},
+			},
+			{
+				"",
+				data{S: s, ExpectedSummaries: []SectionSummary{
+					{Section: Section{SID: SID2, Name: "Freezer"}, FullName: "Freezer", Count: 4},
+					{Section: Section{SID: SID3, Parent: SID2, Name: "Drawer"}, FullName: "Freezer / Drawer", Depth: 1, Count: 3},
+					{Section: Section{SID: SID4, Parent: SID3, Name: "Basket"}, FullName: "Freezer / Drawer / Basket", Depth: 2, Count: 2},
+					{Section: Section{SID: SID1, Name: "Pantry"}, FullName: "Pantry", Count: 1},
+				}},
+			},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,354 @@
+package database
+
+import (
+	"database/sql"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lib/pq"
+)
+
+// TagConstraint limits how many meals with a tag can be in a week of a
+// menu (a week is made of seven consecutive days, from the first one)
+type TagConstraint struct {
+	// Tag is the tag of the recipes
+	Tag string
+
+	// Min is the minimum number of meals with the tag
+	Min int
+
+	// Max is the maximum number of meals with the tag (0 means
+	// that there is no limit)
+	Max int
+}
+
+// SuggestOptions configures the suggestions of SuggestMeals
+type SuggestOptions struct {
+	// Seed is used to choose the recipes: the same seed (with the
+	// same recipes, menu and storage) produces the same suggestions
+	Seed int64
+
+	// Constraints are the tag constraints that must be respected
+	Constraints []TagConstraint
+
+	// NoRepeatDays is the number of days in which a recipe cannot
+	// appear twice (0 means that the recipes can be repeated freely)
+	NoRepeatDays int
+
+	// ExpiringDays is the number of days in which an article of the
+	// storage is considered soon-to-expire
+	ExpiringDays int
+
+	// Today is used to find the soon-to-expire articles (if zero,
+	// the current date is used)
+	Today time.Time
+}
+
+// suggestable is a recipe that can be suggested
+type suggestable struct {
+	Recipe
+
+	// weight is the base weight of the recipe
+	weight float64
+}
+
+// Weights used by SuggestMeals
+const (
+	// expiringWeight multiplies the weight of the recipes that use
+	// soon-to-expire articles
+	expiringWeight = 3
+
+	// constraintWeight multiplies the weight of the recipes that
+	// help to reach the minimum of a tag constraint
+	constraintWeight = 4
+)
+
+// normalizeWords returns the lowercase words of a text, separated
+// and surrounded by single spaces, so that they can be searched
+// without matching parts of other words
+func normalizeWords(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		} else {
+			return ' '
+		}
+	}, text)
+
+	return " " + strings.Join(strings.Fields(text), " ") + " "
+}
+
+// ingredientUses returns true if the ingredient line is the article
+// (the article must appear in it as whole words)
+func ingredientUses(line string, article string) bool {
+	article = normalizeWords(article)
+	if len(strings.TrimSpace(article)) < 3 {
+		return false
+	}
+
+	if i, ok := ParseIngredient(line); ok {
+		line = i.Name
+	}
+
+	return strings.Contains(normalizeWords(line), article)
+}
+
+// usesArticle returns true if one of the ingredients (one per line)
+// is the article
+func usesArticle(ingredients string, article string) bool {
+	for _, line := range strings.Split(ingredients, "\n") {
+		if ingredientUses(line, article) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSuggestable returns all the user's recipes, with their tags
+func (r Recipes) getSuggestable() ([]suggestable, error) {
+	var recipes []suggestable
+
+	// Queries the recipes
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT r.rid, r.name, r.stars, r.ingredients,
+						   COALESCE(ARRAY_AGG(t.name) FILTER (WHERE t.name IS NOT NULL), '{}')
+						   FROM recipes r LEFT JOIN tags t ON t.rid=r.rid WHERE r.uid=$1
+						   GROUP BY r.rid ORDER BY r.rid;`, r.uid)
+	if err != nil {
+		return recipes, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var s suggestable
+		rows.Scan(&s.RID, &s.Name, &s.Stars, &s.Ingredients, pq.Array(&s.Tags))
+		recipes = append(recipes, s)
+	}
+
+	return recipes, nil
+}
+
+// hasTag returns true if the recipe has the tag (ignoring the case)
+func (s suggestable) hasTag(tag string) bool {
+	return slices.ContainsFunc(s.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// SuggestMeals fills the empty meals of a menu (the ones of its slots,
+// or all of them if it has no slots) with the user's recipes, and returns
+// how many meals have been filled.
+//
+// The recipes are chosen randomly, but the ones with more stars and the
+// ones that use soon-to-expire articles of the storage are more likely.
+// The recipes already in the menu are taken into account for the
+// constraints and the repetitions.
+func (m Menus) SuggestMeals(MID int, options SuggestOptions) (int, error) {
+	var filled int
+
+	// Ensures the options are valid
+	for _, c := range options.Constraints {
+		if strings.TrimSpace(c.Tag) == "" || c.Min < 0 || c.Max < 0 || (c.Max > 0 && c.Max < c.Min) {
+			return filled, ERR_SUGGEST_INVALID
+		}
+	}
+	if options.NoRepeatDays < 0 || options.ExpiringDays < 0 {
+		return filled, ERR_SUGGEST_INVALID
+	}
+
+	// Gets the menu
+	menu, err := m.GetOne(MID)
+	if err != nil {
+		return filled, err
+	}
+
+	// Gets the recipes
+	recipes, err := Recipes{uid: m.uid}.getSuggestable()
+	if err != nil {
+		return filled, err
+	}
+
+	// Gets the soon-to-expire articles
+	var expiring []string
+	if options.ExpiringDays > 0 {
+		today := options.Today
+		if today.IsZero() {
+			today = time.Now()
+		}
+		today = toDate(today)
+		limit := today.AddDate(0, 0, options.ExpiringDays)
+
+		section, err := Storage{uid: m.uid}.GetArticles(0, "")
+		if err != nil {
+			return filled, err
+		}
+		for _, a := range section.Articles {
+			if expires := a.Expires(); expires != nil && !expires.Before(today) && !expires.After(limit) {
+				expiring = append(expiring, a.Name)
+			}
+		}
+	}
+
+	// Computes the base weights
+	byKey := make(map[string]int)
+	for i := range recipes {
+		recipes[i].weight = float64(1 + recipes[i].Stars)
+		for _, article := range expiring {
+			if usesArticle(recipes[i].Ingredients, article) {
+				recipes[i].weight *= expiringWeight
+				break
+			}
+		}
+
+		byKey[MealKey(recipes[i].Name)] = i
+	}
+
+	// Finds the recipes already in the menu (as recipe index -> days)
+	// and the empty meals
+	used := make(map[int][]int)
+	empty := make([]int, len(menu.Days))
+	for d, day := range menu.Days {
+		for i, meal := range day.Meals {
+			if strings.TrimSpace(meal) == "" {
+				if menu.Slots == nil || i < len(menu.Slots) {
+					empty[d]++
+				}
+				continue
+			}
+
+			for _, line := range strings.Split(meal, "\n") {
+				if r, found := byKey[MealKey(line)]; found {
+					used[r] = append(used[r], d)
+				}
+			}
+		}
+	}
+
+	// countTag counts the meals with a tag in the week of a day
+	countTag := func(tag string, day int) int {
+		var count int
+		for r, days := range used {
+			if recipes[r].hasTag(tag) {
+				for _, d := range days {
+					if d/7 == day/7 {
+						count++
+					}
+				}
+			}
+		}
+
+		return count
+	}
+
+	// Fills the empty meals
+	random := rand.New(rand.NewSource(options.Seed))
+	var changed []Day
+	for d, day := range menu.Days {
+		dayChanged := false
+
+		for i, meal := range day.Meals {
+			if strings.TrimSpace(meal) != "" || (menu.Slots != nil && i >= len(menu.Slots)) {
+				continue
+			}
+
+			// Counts the empty meals left in the week (this one included)
+			var left int
+			for w := d; w < len(menu.Days) && w/7 == d/7; w++ {
+				left += empty[w]
+			}
+
+			// Finds the constraints that still need meals
+			var needed []TagConstraint
+			var missing int
+			for _, c := range options.Constraints {
+				if n := countTag(c.Tag, d); n < c.Min {
+					needed = append(needed, c)
+					missing += c.Min - n
+				}
+			}
+
+			// Finds the candidates and their weights
+			var candidates []int
+			var weights []float64
+			var total float64
+			for r, recipe := range recipes {
+				// Skips the repetitions
+				repeated := slices.ContainsFunc(used[r], func(u int) bool {
+					return options.NoRepeatDays > 0 && u > d-options.NoRepeatDays && u < d+options.NoRepeatDays
+				})
+				if repeated {
+					continue
+				}
+
+				// Skips the recipes that would exceed a maximum
+				exceeds := slices.ContainsFunc(options.Constraints, func(c TagConstraint) bool {
+					return c.Max > 0 && recipe.hasTag(c.Tag) && countTag(c.Tag, d) >= c.Max
+				})
+				if exceeds {
+					continue
+				}
+
+				// Prefers the recipes that help to reach the minimums, and
+				// requires them if there are no more meals to spare
+				weight := recipe.weight
+				helps := slices.ContainsFunc(needed, func(c TagConstraint) bool { return recipe.hasTag(c.Tag) })
+				if helps {
+					weight *= constraintWeight
+				} else if len(needed) > 0 && left <= missing {
+					continue
+				}
+
+				candidates = append(candidates, r)
+				weights = append(weights, weight)
+				total += weight
+			}
+
+			empty[d]--
+			if len(candidates) == 0 {
+				continue
+			}
+
+			// Chooses one of them
+			choice := random.Float64() * total
+			chosen := candidates[len(candidates)-1]
+			for c, w := range weights {
+				if choice < w {
+					chosen = candidates[c]
+					break
+				}
+				choice -= w
+			}
+
+			day.Meals[i] = recipes[chosen].Name
+			used[chosen] = append(used[chosen], d)
+			dayChanged = true
+			filled++
+		}
+
+		if dayChanged {
+			changed = append(changed, day)
+		}
+	}
+
+	// Saves the changed days all together
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, ERR_UNKNOWN
+	}
+	defer tx.Rollback()
+
+	for _, day := range changed {
+		if err = setDayMeals(tx, MID, day.Position, day.Meals); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, ERR_UNKNOWN
+	}
+
+	return filled, nil
+}
@@ -0,0 +1,235 @@
+package database
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMenusSuggestMeals(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	days := []string{"d0", "d1", "d2", "d3", "d4", "d5", "d6"}
+	recipes := map[string][]string{
+		"Salmon":    {"FISH"},
+		"Tuna":      {"FISH"},
+		"Pasta":     {},
+		"Pizza":     {},
+		"Risotto":   {},
+		"Steak":     {"MEAT"},
+		"Meatballs": {"MEAT"},
+	}
+	for name, tags := range recipes {
+		RID, _ := u.Recipes().New(name)
+		u.Recipes().Edit(RID, Recipe{Name: name, Tags: tags})
+	}
+
+	// newMenu creates a menu with a slot, with the first meal already set
+	newMenu := func() int {
+		MID, _ := m.NewWithSlots("test", days, []string{SLOT_DINNER})
+		m.SetDayMeals(MID, 0, []string{"Pizza"})
+		return MID
+	}
+
+	MID := newMenu()
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M       Menus
+		MID     int
+		Options SuggestOptions
+
+		ExpectedErr    error
+		ExpectedFilled int
+		Check          func(meals []string) bool
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if filled, err := d.M.SuggestMeals(d.MID, d.Options); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if filled != d.ExpectedFilled {
+					t.Errorf("%s: expected %d filled meals, got %d", msg, d.ExpectedFilled, filled)
+				}
+
+				menu, _ := d.M.GetOne(d.MID)
+				var meals []string
+				for _, day := range menu.Days {
+					meals = append(meals, day.Meals...)
+				}
+
+				if meals[0] != "Pizza" {
+					t.Errorf("%s: the first meal has been changed to <%s>", msg, meals[0])
+				} else if d.Check != nil && !d.Check(meals) {
+					t.Errorf("%s: unexpected meals <%v>", msg, meals)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user suggested meals",
+				data{M: otherM, MID: MID, ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"unknown user suggested meals",
+				data{M: unknownUser.Menus(), MID: MID, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"suggested meals with empty tag",
+				data{M: m, MID: MID, Options: SuggestOptions{Constraints: []TagConstraint{{Tag: " ", Min: 1}}}, ExpectedErr: ERR_SUGGEST_INVALID},
+			},
+			{
+				"suggested meals with maximum lower than minimum",
+				data{M: m, MID: MID, Options: SuggestOptions{Constraints: []TagConstraint{{Tag: "FISH", Min: 2, Max: 1}}}, ExpectedErr: ERR_SUGGEST_INVALID},
+			},
+			{
+				"suggested meals with negative days",
+				data{M: m, MID: MID, Options: SuggestOptions{NoRepeatDays: -1}, ExpectedErr: ERR_SUGGEST_INVALID},
+			},
+			{
+				"suggested meals without repetitions",
+				data{M: m, MID: newMenu(), Options: SuggestOptions{Seed: 1, NoRepeatDays: 7}, ExpectedFilled: 6, Check: func(meals []string) bool {
+					slices.Sort(meals)
+					return len(slices.Compact(meals)) == len(recipes)
+				}},
+			},
+			{
+				"suggested meals with constraints",
+				data{M: m, MID: newMenu(), Options: SuggestOptions{Seed: 2, Constraints: []TagConstraint{{Tag: "fish", Min: 2, Max: 2}, {Tag: "MEAT", Max: 1}}}, ExpectedFilled: 6, Check: func(meals []string) bool {
+					var fish, meat int
+					for _, meal := range meals {
+						if slices.Contains(recipes[meal], "FISH") {
+							fish++
+						} else if slices.Contains(recipes[meal], "MEAT") {
+							meat++
+						}
+					}
+					return fish == 2 && meat <= 1
+				}},
+			},
+			{
+				"suggested meals in a full menu",
+				data{M: m, MID: MID, Options: SuggestOptions{Seed: 3}, ExpectedFilled: 6},
+			},
+			{
+				"",
+				data{M: m, MID: MID, Options: SuggestOptions{Seed: 3}, ExpectedFilled: 0},
+			},
+		},
+	}.Run(t)
+}
+
+func TestMenusSuggestMealsSeed(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	for i := range 10 {
+		u.Recipes().New("recipe " + strconv.Itoa(i))
+	}
+
+	// suggest fills a new menu and returns its meals
+	suggest := func(seed int64) [][]string {
+		MID, _ := m.New("test", []string{"d0", "d1", "d2", "d3"}, 2)
+		m.SuggestMeals(MID, SuggestOptions{Seed: seed, NoRepeatDays: 2})
+
+		var meals [][]string
+		menu, _ := m.GetOne(MID)
+		for _, day := range menu.Days {
+			meals = append(meals, day.Meals)
+		}
+
+		return meals
+	}
+
+	if first, second := suggest(42), suggest(42); !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different meals: <%v> and <%v>", first, second)
+	}
+}
+
+func TestMenusSuggestMealsExpiring(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	RID, _ := u.Recipes().New("Spinach pie")
+	u.Recipes().Edit(RID, Recipe{Name: "Spinach pie", Ingredients: "200 g of spinach\n2 eggs"})
+	RID, _ = u.Recipes().New("Pizza")
+	u.Recipes().Edit(RID, Recipe{Name: "Pizza", Ingredients: "flour\ntomatoes"})
+
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, dateLocale)
+	SID, _ := u.Storage().NewSection("Fridge")
+	u.Storage().AddArticles(StringArticle{Name: "Spinach", Section: strconv.Itoa(SID), Expiration: "2025-03-12"})
+
+	// Counts how many times each recipe is chosen, with many seeds
+	var spinach, pizza int
+	for seed := range int64(20) {
+		MID, _ := m.New("test", []string{"d0"}, 1)
+		m.SuggestMeals(MID, SuggestOptions{Seed: seed, ExpiringDays: 7, Today: today})
+
+		menu, _ := m.GetOne(MID)
+		switch menu.Days[0].Meals[0] {
+		case "Spinach pie":
+			spinach++
+		case "Pizza":
+			pizza++
+		}
+	}
+
+	if spinach+pizza != 20 || spinach <= pizza {
+		t.Errorf("expected the spinach pie to be preferred, got %d spinach pies and %d pizzas", spinach, pizza)
+	}
+}
+
+func TestUsesArticle(t *testing.T) {
+	type data struct {
+		Ingredients string
+		Article     string
+
+		Expected bool
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if got := usesArticle(d.Ingredients, d.Article); got != d.Expected {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.Expected, got)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"",
+				data{Ingredients: "200 g of spinach\n2 eggs", Article: "Spinach", Expected: true},
+			},
+			{
+				"",
+				data{Ingredients: "- 2 eggs", Article: " eggs ", Expected: true},
+			},
+			{
+				"",
+				data{Ingredients: "flour\ntomatoes", Article: "Spinach"},
+			},
+			{
+				"short article",
+				data{Ingredients: "2 eggs", Article: "eg"},
+			},
+			{
+				"article in another word",
+				data{Ingredients: "1 eggplant", Article: "Egg"},
+			},
+			{
+				"article in another word",
+				data{Ingredients: "50 g of unsalted butter", Article: "Salt"},
+			},
+			{
+				"",
+				data{Ingredients: "flour\na pinch of salt", Article: "Salt"},
+			},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// MenuTemplate is the structure of a menu (its slots and the meals of
+// its days), that can be reused to create new menus
+type MenuTemplate struct {
+	// TID is the Template ID
+	TID int
+
+	// Name is the name of the template
+	Name string
+
+	// Slots are the slots of the menu (see Menu.Slots)
+	Slots []string
+
+	// Days contains the meals of every day of the menu
+	Days [][]string
+}
+
+// DeleteTemplate deletes a menu template
+func (m Menus) DeleteTemplate(TID int) error {
+	// Deletes the template
+	res, err := db.Exec(`DELETE FROM menu_templates WHERE uid=$1 AND tid=$2;`, m.uid, TID)
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra < 1 {
+		// If the query has failed, makes sure that the template (and the user) exist
+		_, err := m.GetTemplate(TID)
+		return err
+	}
+
+	return nil
+}
+
+// GetTemplate returns a specific menu template, with its days
+func (m Menus) GetTemplate(TID int) (MenuTemplate, error) {
+	var t MenuTemplate
+
+	// Scans the template
+	err := db.QueryRow(`SELECT tid, name, slots FROM menu_templates WHERE uid=$1 AND tid=$2;`, m.uid, TID).
+		Scan(&t.TID, &t.Name, pq.Array(&t.Slots))
+	if err != nil {
+		return t, handleNoRowsError(err, m.uid, ERR_TEMPLATE_NOT_FOUND)
+	}
+
+	// Queries the days
+	var rows *sql.Rows
+	rows, err = db.Query(`SELECT meals FROM template_days WHERE tid=$1 ORDER BY position;`, TID)
+	if err != nil {
+		return t, ERR_UNKNOWN
+	}
+
+	// Appends them to the template
+	defer rows.Close()
+	for rows.Next() {
+		var meals []string
+		rows.Scan(pq.Array(&meals))
+		t.Days = append(t.Days, meals)
+	}
+
+	return t, nil
+}
+
+// GetTemplates returns the user's menu templates (days not included),
+// ordered by name
+func (m Menus) GetTemplates() ([]MenuTemplate, error) {
+	var templates []MenuTemplate
+
+	// Queries the templates
+	var rows *sql.Rows
+	rows, err := db.Query(`SELECT tid, name, slots FROM menu_templates WHERE uid=$1 ORDER BY name;`, m.uid)
+	if err != nil {
+		return templates, ERR_UNKNOWN
+	}
+
+	// Appends them to the list
+	defer rows.Close()
+	for rows.Next() {
+		var t MenuTemplate
+		rows.Scan(&t.TID, &t.Name, pq.Array(&t.Slots))
+		templates = append(templates, t)
+	}
+
+	// If no templates have been found, makes sure the user exists
+	if len(templates) == 0 {
+		_, err := GetUser("UID", m.uid)
+		return templates, err
+	}
+
+	return templates, nil
+}
+
+// NewFromTemplate creates a new menu with the slots of a template. The
+// meals of the template's days are repeated over the new days. It returns
+// the MID of the new menu.
+func (m Menus) NewFromTemplate(TID int, name string, daysNames []string) (int, error) {
+	// Gets the template
+	t, err := m.GetTemplate(TID)
+	if err != nil {
+		return 0, err
+	}
+
+	return m.newMenu(name, daysNames, t.Slots, func(dpos int) []string {
+		if len(t.Days) == 0 {
+			return make([]string, len(t.Slots))
+		}
+
+		return t.Days[dpos%len(t.Days)]
+	})
+}
+
+// SaveTemplate saves the structure of a menu as a template
+// and returns its TID
+func (m Menus) SaveTemplate(MID int, name string) (int, error) {
+	var TID int
+
+	// Gets the menu
+	menu, err := m.GetOne(MID)
+	if err != nil {
+		return TID, err
+	}
+
+	// Adds the template (the slots cannot be null)
+	if menu.Slots == nil {
+		menu.Slots = []string{}
+	}
+	err = db.QueryRow(`INSERT INTO menu_templates (uid, name, slots) VALUES ($1, $2, $3) RETURNING tid;`,
+		m.uid, name, pq.Array(menu.Slots)).Scan(&TID)
+	if pqe, ok := err.(*pq.Error); ok && pqe.Code == "23505" {
+		return TID, ERR_TEMPLATE_DUPLICATED
+	} else if err != nil {
+		return TID, ERR_UNKNOWN
+	}
+
+	// Adds the days
+	for _, day := range menu.Days {
+		_, err := db.Exec(`INSERT INTO template_days (tid, position, meals) VALUES ($1, $2, $3);`,
+			TID, day.Position, pq.Array(day.Meals))
+		if err != nil {
+			return TID, ERR_UNKNOWN
+		}
+	}
+
+	return TID, nil
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMenusSaveTemplate(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	MID, _ := m.NewWithSlots("test", []string{"d0", "d1"}, []string{SLOT_LUNCH, SLOT_DINNER})
+	m.SetDayMeals(MID, 1, []string{"pasta", "soup"})
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M    Menus
+		MID  int
+		Name string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if TID, err := d.M.SaveTemplate(d.MID, d.Name); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				expected := MenuTemplate{
+					TID:   TID,
+					Name:  d.Name,
+					Slots: []string{SLOT_LUNCH, SLOT_DINNER},
+					Days:  [][]string{{"", ""}, {"pasta", "soup"}},
+				}
+
+				if got, err := d.M.GetTemplate(TID); err != nil || !reflect.DeepEqual(got, expected) {
+					t.Errorf("%s: expected template <%v>, got <%v> <%v>", msg, expected, got, err)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"other user saved template",
+				data{M: otherM, MID: MID, Name: "week", ExpectedErr: ERR_MENU_NOT_FOUND},
+			},
+			{
+				"",
+				data{M: m, MID: MID, Name: "week"},
+			},
+			{
+				"saved duplicated template",
+				data{M: m, MID: MID, Name: "week", ExpectedErr: ERR_TEMPLATE_DUPLICATED},
+			},
+		},
+	}.Run(t)
+}
+
+func TestMenusGetTemplates(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	MID, _ := m.NewWithSlots("test", []string{"d0"}, []string{SLOT_LUNCH})
+	TID1, _ := m.SaveTemplate(MID, "b")
+	TID2, _ := m.SaveTemplate(MID, "a")
+
+	emptyU, _ := getTestingUser(t)
+
+	type data struct {
+		M Menus
+
+		ExpectedErr  error
+		ExpectedTIDs []int
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			templates, err := d.M.GetTemplates()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if len(templates) != len(d.ExpectedTIDs) {
+				t.Errorf("%s: expected <%d> templates, got <%v>", msg, len(d.ExpectedTIDs), templates)
+			} else {
+				for i, tmpl := range templates {
+					if tmpl.TID != d.ExpectedTIDs[i] {
+						t.Errorf("%s: expected <%v> templates, got <%v>", msg, d.ExpectedTIDs, templates)
+						break
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user got templates", data{M: unknownUser.Menus(), ExpectedErr: ERR_USER_UNKNOWN}},
+			{"(empty)", data{M: emptyU.Menus()}},
+			{"", data{M: m, ExpectedTIDs: []int{TID2, TID1}}},
+		},
+	}.Run(t)
+}
+
+func TestMenusDeleteTemplate(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	MID, _ := m.New("test", []string{"d0"}, 1)
+	TID, _ := m.SaveTemplate(MID, "week")
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M   Menus
+		TID int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.M.DeleteTemplate(d.TID); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if _, err := d.M.GetTemplate(d.TID); err != ERR_TEMPLATE_NOT_FOUND {
+					t.Errorf("%s: template not deleted: got err <%v>", msg, err)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"other user deleted template", data{M: otherM, TID: TID, ExpectedErr: ERR_TEMPLATE_NOT_FOUND}},
+			{"", data{M: m, TID: TID}},
+			{"deleted template twice", data{M: m, TID: TID, ExpectedErr: ERR_TEMPLATE_NOT_FOUND}},
+		},
+	}.Run(t)
+}
+
+func TestMenusNewFromTemplate(t *testing.T) {
+	u, _ := getTestingUser(t)
+	m := u.Menus()
+
+	MID, _ := m.NewWithSlots("test", []string{"d0", "d1"}, []string{SLOT_LUNCH})
+	m.SetDayMeals(MID, 0, []string{"pasta"})
+	m.SetDayMeals(MID, 1, []string{"soup"})
+	TID, _ := m.SaveTemplate(MID, "week")
+
+	otherU, _ := getTestingUser(t)
+	otherM := otherU.Menus()
+
+	type data struct {
+		M   Menus
+		TID int
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			newMID, err := d.M.NewFromTemplate(d.TID, "new", []string{"a", "b", "c"})
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				got, _ := d.M.GetOne(newMID)
+				expected := [][]string{{"pasta"}, {"soup"}, {"pasta"}}
+
+				if !reflect.DeepEqual(got.Slots, []string{SLOT_LUNCH}) || len(got.Days) != len(expected) {
+					t.Errorf("%s: unexpected menu <%v>", msg, got)
+					return
+				}
+				for i, day := range got.Days {
+					if !reflect.DeepEqual(day.Meals, expected[i]) {
+						t.Errorf("%s: day %d: expected meals <%v>, got <%v>", msg, i, expected[i], day.Meals)
+					}
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"other user used template", data{M: otherM, TID: TID, ExpectedErr: ERR_TEMPLATE_NOT_FOUND}},
+			{"", data{M: m, TID: TID}},
+		},
+	}.Run(t)
+}
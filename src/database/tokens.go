@@ -0,0 +1,93 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TokenPurpose is the action a user token can be used for
+type TokenPurpose string
+
+const (
+	TOKEN_DELETE         TokenPurpose = "delete"
+	TOKEN_RESET_PASSWORD TokenPurpose = "reset_password"
+)
+
+// tokenDurations contains, for every purpose, how long a token is valid
+var tokenDurations = map[TokenPurpose]time.Duration{
+	TOKEN_DELETE:         time.Hour,
+	TOKEN_RESET_PASSWORD: time.Hour,
+}
+
+// GenerateToken generates a new token for the user, that can be used
+// only once and only for the given purpose, then returns it. Only its
+// hash is saved, replacing the previous token with the same purpose.
+func (u User) GenerateToken(purpose TokenPurpose) (string, error) {
+	// Ensures the user exists and the purpose is valid
+	duration, ok := tokenDurations[purpose]
+	if _, err := GetUser("UID", u.UID); err != nil {
+		return "", err
+	} else if !ok {
+		return "", ERR_UNKNOWN
+	}
+
+	// Generates the token
+	token, hash, err := generateUserToken()
+	if err != nil {
+		return "", err
+	}
+
+	// Saves it in the database
+	_, err = db.Exec(`INSERT INTO user_tokens (uid, purpose, hash, expires)
+					  VALUES ($1, $2, $3, NOW() + $4 * INTERVAL '1 second')
+					  ON CONFLICT (uid, purpose) DO UPDATE SET hash=EXCLUDED.hash, expires=EXCLUDED.expires;`,
+		u.UID, purpose, hash, int(duration.Seconds()))
+	if err != nil {
+		return "", ERR_UNKNOWN
+	}
+
+	return token, nil
+}
+
+// useToken ensures the token has been generated for the given purpose
+// and has not expired, then drops it so that it can't be used again
+func (u User) useToken(purpose TokenPurpose, token string) error {
+	var TID int
+	var hash string
+	var expired bool
+
+	// Fetches the token
+	err := db.QueryRow(`SELECT tid, hash, expires < NOW() FROM user_tokens WHERE uid=$1 AND purpose=$2;`, u.UID, purpose).
+		Scan(&TID, &hash, &expired)
+	if err == sql.ErrNoRows {
+		return handleNoRowsError(err, u.UID, ERR_USER_WRONG_TOKEN)
+	} else if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	// Compares the tokens
+	if err = compareHash(token, hash, ERR_USER_WRONG_TOKEN); err != nil {
+		return err
+	} else if expired {
+		return ERR_USER_TOKEN_EXPIRED
+	}
+
+	// Drops it, making sure nobody else has used it in the meanwhile
+	res, err := db.Exec(`DELETE FROM user_tokens WHERE tid=$1;`, TID)
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra != 1 {
+		return ERR_USER_WRONG_TOKEN
+	}
+
+	return nil
+}
+
+// CleanupTokens deletes the expired tokens
+func CleanupTokens() error {
+	if _, err := db.Exec(`DELETE FROM user_tokens WHERE expires < NOW();`); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestUserGenerateToken(t *testing.T) {
+	user, _ := getTestingUser(t)
+
+	type data struct {
+		User    User
+		Purpose TokenPurpose
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			token, err := d.User.GenerateToken(d.Purpose)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedErr, err)
+			}
+
+			if d.ExpectedErr == nil {
+				var hash string
+				db.QueryRow(`SELECT hash FROM user_tokens WHERE uid=$1 AND purpose=$2;`, d.User.UID, d.Purpose).Scan(&hash)
+				if err := compareHash(token, hash, ERR_UNKNOWN); err != nil {
+					t.Errorf("%s, saved token does not match returned one", msg)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"generated token of unknown user",
+				data{User: unknownUser, Purpose: TOKEN_DELETE, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"generated token with invalid purpose",
+				data{User: user, Purpose: "purpose", ExpectedErr: ERR_UNKNOWN},
+			},
+			{
+				"",
+				data{User: user, Purpose: TOKEN_DELETE},
+			},
+			{
+				"(replaced)",
+				data{User: user, Purpose: TOKEN_DELETE},
+			},
+		},
+	}.Run(t)
+}
+
+func TestUserUseToken(t *testing.T) {
+	user, _ := getTestingUser(t)
+	token, _ := user.GenerateToken(TOKEN_RESET_PASSWORD)
+	expiredUser, _ := getTestingUser(t)
+	expiredToken, _ := expiredUser.GenerateToken(TOKEN_RESET_PASSWORD)
+	db.Exec(`UPDATE user_tokens SET expires=NOW() - INTERVAL '1 minute' WHERE uid=$1;`, expiredUser.UID)
+
+	type data struct {
+		User    User
+		Purpose TokenPurpose
+		Token   string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.User.useToken(d.Purpose, d.Token); err != d.ExpectedErr {
+				t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedErr, err)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{
+				"used token of unknown user",
+				data{User: unknownUser, Purpose: TOKEN_RESET_PASSWORD, ExpectedErr: ERR_USER_UNKNOWN},
+			},
+			{
+				"used token with another purpose",
+				data{User: user, Purpose: TOKEN_DELETE, Token: token, ExpectedErr: ERR_USER_WRONG_TOKEN},
+			},
+			{
+				"used expired token",
+				data{User: expiredUser, Purpose: TOKEN_RESET_PASSWORD, Token: expiredToken, ExpectedErr: ERR_USER_TOKEN_EXPIRED},
+			},
+			{
+				"",
+				data{User: user, Purpose: TOKEN_RESET_PASSWORD, Token: token},
+			},
+			{
+				"used token twice",
+				data{User: user, Purpose: TOKEN_RESET_PASSWORD, Token: token, ExpectedErr: ERR_USER_WRONG_TOKEN},
+			},
+		},
+	}.Run(t)
+}
+
+func TestCleanupTokens(t *testing.T) {
+	user, _ := getTestingUser(t)
+	user.GenerateToken(TOKEN_DELETE)
+	user.GenerateToken(TOKEN_RESET_PASSWORD)
+	db.Exec(`UPDATE user_tokens SET expires=NOW() - INTERVAL '1 minute' WHERE uid=$1 AND purpose=$2;`, user.UID, TOKEN_DELETE)
+
+	if err := CleanupTokens(); err != nil {
+		t.Errorf("expected no err, got <%v>", err)
+	}
+
+	var purposes []TokenPurpose
+	rows, _ := db.Query(`SELECT purpose FROM user_tokens WHERE uid=$1;`, user.UID)
+	defer rows.Close()
+	for rows.Next() {
+		var purpose TokenPurpose
+		rows.Scan(&purpose)
+		purposes = append(purposes, purpose)
+	}
+
+	if len(purposes) != 1 || purposes[0] != TOKEN_RESET_PASSWORD {
+		t.Errorf("expected only <%s> token, got <%v>", TOKEN_RESET_PASSWORD, purposes)
+	}
+}
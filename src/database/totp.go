@@ -0,0 +1,351 @@
+package database
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// totpPeriod is the duration of every TOTP step (RFC 6238)
+const totpPeriod = 30
+
+// totpSkew is the number of steps before and after the current one
+// whose codes are still accepted, to tolerate unsynchronized clocks
+const totpSkew = 1
+
+// recoveryCodesNumber is the number of recovery codes generated
+// when two-factor authentication is enabled
+const recoveryCodesNumber = 8
+
+// totpEncoding is the base32 encoding used for the secrets
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP contains the two-factor authentication status of an user
+type TOTP struct {
+	// Enabled is true if a code is required to sign in
+	Enabled bool
+
+	// Secret is the shared secret, encoded in base32.
+	// It is set only until the enrolment has been completed.
+	Secret string
+
+	// RecoveryCodes is the number of unused recovery codes
+	RecoveryCodes int
+}
+
+// generateTOTPSecret returns a new random secret of 160 bits,
+// encoded in base32
+func generateTOTPSecret() string {
+	buffer := make([]byte, 20)
+	rand.Read(buffer)
+	return totpEncoding.EncodeToString(buffer)
+}
+
+// generateRecoveryCode returns a new random recovery code,
+// formatted as two groups of 5 characters
+func generateRecoveryCode() string {
+	buffer := make([]byte, 5)
+	rand.Read(buffer)
+	code := fmt.Sprintf("%x", buffer)
+	return code[:5] + "-" + code[5:]
+}
+
+// normalizeCode removes the spaces and the dashes from a code
+// entered by the user, and lowercases it
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// totpCode returns the 6-digits code of a secret at the given
+// step (RFC 4226)
+func totpCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		return "", ERR_UNKNOWN
+	}
+
+	// Computes the HMAC of the step
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	// Truncates it
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000), nil
+}
+
+// totpStep returns the TOTP step of a time
+func totpStep(now time.Time) int64 {
+	return now.Unix() / totpPeriod
+}
+
+// matchTOTP looks for the step, after lastStep, whose code is the given
+// one. If none is found, it returns 0.
+func matchTOTP(secret string, code string, lastStep int64, now time.Time) int64 {
+	current := totpStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+
+		if expected, err := totpCode(secret, step); err == nil &&
+			subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step
+		}
+	}
+
+	return 0
+}
+
+// TOTPURI returns the provisioning URI of a secret, that can
+// be opened (or scanned as a QR code) by an authenticator app
+func TOTPURI(username string, secret string) string {
+	params := url.Values{
+		"secret": {secret},
+		"issuer": {"CucinAssistant"},
+	}
+
+	return "otpauth://totp/" + url.PathEscape("CucinAssistant:"+username) + "?" + params.Encode()
+}
+
+// getTOTP returns the secret, the status and the last step used
+// by the user
+func (u User) getTOTP() (secret string, enabled bool, lastStep int64, err error) {
+	err = db.QueryRow(`SELECT secret, enabled, last_step FROM totp WHERE uid=$1;`, u.UID).
+		Scan(&secret, &enabled, &lastStep)
+	if err != nil {
+		err = handleNoRowsError(err, u.UID, ERR_TOTP_DISABLED)
+	}
+
+	return
+}
+
+// GetTOTP returns the two-factor authentication status of the user
+func (u User) GetTOTP() (TOTP, error) {
+	var totp TOTP
+
+	secret, enabled, _, err := u.getTOTP()
+	if err == ERR_TOTP_DISABLED {
+		return totp, nil
+	} else if err != nil {
+		return totp, err
+	}
+
+	totp.Enabled = enabled
+	if !enabled {
+		totp.Secret = secret
+	}
+
+	// Counts the recovery codes
+	err = db.QueryRow(`SELECT COUNT(*) FROM recovery_codes WHERE uid=$1;`, u.UID).Scan(&totp.RecoveryCodes)
+	if err != nil {
+		return totp, ERR_UNKNOWN
+	}
+
+	return totp, nil
+}
+
+// HasTOTP returns true if the user has enabled two-factor authentication
+func (u User) HasTOTP() (bool, error) {
+	totp, err := u.GetTOTP()
+	return totp.Enabled, err
+}
+
+// StartTOTP generates a new secret for the user, that will be used once
+// the enrolment is completed (see EnableTOTP), then returns it
+func (u User) StartTOTP() (string, error) {
+	// Ensures it isn't already enabled
+	if enabled, err := u.HasTOTP(); err != nil {
+		return "", err
+	} else if enabled {
+		return "", ERR_TOTP_ENABLED
+	}
+
+	// Saves the new secret
+	secret := generateTOTPSecret()
+	_, err := db.Exec(`INSERT INTO totp (uid, secret) VALUES ($1, $2)
+					   ON CONFLICT (uid) DO UPDATE SET secret=EXCLUDED.secret, enabled=FALSE, last_step=0;`, u.UID, secret)
+	if err != nil {
+		return "", ERR_UNKNOWN
+	}
+
+	return secret, nil
+}
+
+// EnableTOTP completes the enrolment started with StartTOTP, if the code
+// is correct. It returns the recovery codes, that are stored hashed and
+// can be used only once instead of a code.
+func (u User) EnableTOTP(code string, now time.Time) ([]string, error) {
+	// Gets the pending secret
+	secret, enabled, lastStep, err := u.getTOTP()
+	if err != nil {
+		return nil, err
+	} else if enabled {
+		return nil, ERR_TOTP_ENABLED
+	}
+
+	// Checks the code
+	step := matchTOTP(secret, normalizeCode(code), lastStep, now)
+	if step == 0 {
+		return nil, ERR_TOTP_WRONG_CODE
+	}
+
+	// Generates the recovery codes and enables it together, so that
+	// a failure cannot leave the user with a partial set of codes
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, ERR_UNKNOWN
+	}
+	defer tx.Rollback()
+
+	codes, err := u.generateRecoveryCodes(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enables it
+	_, err = tx.Exec(`UPDATE totp SET enabled=TRUE, last_step=$2 WHERE uid=$1;`, u.UID, step)
+	if err != nil {
+		return nil, ERR_UNKNOWN
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, ERR_UNKNOWN
+	}
+
+	return codes, nil
+}
+
+// generateRecoveryCodes replaces, within the transaction, the recovery
+// codes of the user with new ones, then returns them
+func (u User) generateRecoveryCodes(tx *sql.Tx) ([]string, error) {
+	var codes []string
+
+	// Deletes the old ones
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE uid=$1;`, u.UID); err != nil {
+		return nil, ERR_UNKNOWN
+	}
+
+	// Saves the new ones
+	for range recoveryCodesNumber {
+		code := generateRecoveryCode()
+		hash, err := createHash(normalizeCode(code))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = tx.Exec(`INSERT INTO recovery_codes (uid, hash) VALUES ($1, $2);`, u.UID, hash); err != nil {
+			return nil, ERR_UNKNOWN
+		}
+
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// useRecoveryCode looks for an unused recovery code of the user,
+// and deletes it
+func (u User) useRecoveryCode(code string) error {
+	var found int
+
+	// Queries the hashes
+	rows, err := db.Query(`SELECT rcid, hash FROM recovery_codes WHERE uid=$1;`, u.UID)
+	if err != nil {
+		return ERR_UNKNOWN
+	}
+
+	// Compares them
+	defer rows.Close()
+	for rows.Next() {
+		var RCID int
+		var hash string
+		rows.Scan(&RCID, &hash)
+
+		if compareHash(code, hash, ERR_TOTP_WRONG_CODE) == nil {
+			found = RCID
+			break
+		}
+	}
+
+	if found == 0 {
+		return ERR_TOTP_WRONG_CODE
+	}
+
+	// Deletes it, so that the code can't be used again
+	// (not even by a concurrent sign in)
+	res, err := db.Exec(`DELETE FROM recovery_codes WHERE rcid=$1 AND uid=$2;`, found, u.UID)
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra < 1 {
+		return ERR_TOTP_WRONG_CODE
+	}
+
+	return nil
+}
+
+// CheckTOTP checks the code given by the user during the sign in.
+// Every code can be used only once: it can be either the one shown
+// by the authenticator app or a recovery code.
+func (u User) CheckTOTP(code string, now time.Time) error {
+	// Gets the secret
+	secret, enabled, lastStep, err := u.getTOTP()
+	if err != nil {
+		return err
+	} else if !enabled {
+		return ERR_TOTP_DISABLED
+	}
+
+	// Recovery codes are longer than the TOTP ones
+	code = normalizeCode(code)
+	if len(code) != 6 {
+		return u.useRecoveryCode(code)
+	}
+
+	// Checks the code
+	step := matchTOTP(secret, code, lastStep, now)
+	if step == 0 {
+		return ERR_TOTP_WRONG_CODE
+	}
+
+	// Saves the step, so that the code can't be used again
+	var res sql.Result
+	res, err = db.Exec(`UPDATE totp SET last_step=$2 WHERE uid=$1 AND last_step<$2;`, u.UID, step)
+	if err != nil {
+		return ERR_UNKNOWN
+	} else if ra, _ := res.RowsAffected(); ra < 1 {
+		return ERR_TOTP_WRONG_CODE
+	}
+
+	return nil
+}
+
+// DisableTOTP disables the two-factor authentication, if the code is
+// correct, and deletes the recovery codes
+func (u User) DisableTOTP(code string, now time.Time) error {
+	// Checks the code
+	if err := u.CheckTOTP(code, now); err != nil {
+		return err
+	}
+
+	// Deletes the secret and the recovery codes
+	if _, err := db.Exec(`DELETE FROM totp WHERE uid=$1;`, u.UID); err != nil {
+		return ERR_UNKNOWN
+	}
+	if _, err := db.Exec(`DELETE FROM recovery_codes WHERE uid=$1;`, u.UID); err != nil {
+		return ERR_UNKNOWN
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// currentCode returns the TOTP code of a secret at the given time
+func currentCode(t *testing.T, secret string, now time.Time) string {
+	code, err := totpCode(secret, totpStep(now))
+	if err != nil {
+		t.Fatalf("Cannot compute code: %s", err.Error())
+	}
+
+	return code
+}
+
+func TestTOTPCode(t *testing.T) {
+	// Test vectors of RFC 6238 (SHA1), truncated to 6 digits
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	type data struct {
+		Time int64
+
+		Expected string
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if got, err := totpCode(secret, totpStep(time.Unix(d.Time, 0))); err != nil {
+				t.Errorf("%s: expected no err, got <%v>", msg, err)
+			} else if got != d.Expected {
+				t.Errorf("%s: expected <%s>, got <%s>", msg, d.Expected, got)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"(59)", data{Time: 59, Expected: "287082"}},
+			{"(1111111109)", data{Time: 1111111109, Expected: "081804"}},
+			{"(1111111111)", data{Time: 1111111111, Expected: "050471"}},
+			{"(1234567890)", data{Time: 1234567890, Expected: "005924"}},
+			{"(2000000000)", data{Time: 2000000000, Expected: "279037"}},
+			{"(20000000000)", data{Time: 20000000000, Expected: "353130"}},
+		},
+	}.Run(t)
+}
+
+func TestUserStartTOTP(t *testing.T) {
+	u, _ := getTestingUser(t)
+	enabled, _ := getTestingUser(t)
+	now := time.Now()
+	secret, _ := enabled.StartTOTP()
+	enabled.EnableTOTP(currentCode(t, secret, now), now)
+
+	type data struct {
+		U User
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			secret, err := d.U.StartTOTP()
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if totp, _ := d.U.GetTOTP(); totp.Enabled || totp.Secret != secret {
+					t.Errorf("%s: expected pending secret <%s>, got <%+v>", msg, secret, totp)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user started totp", data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"started totp twice", data{U: enabled, ExpectedErr: ERR_TOTP_ENABLED}},
+			{"", data{U: u}},
+			{"(restarted)", data{U: u}},
+		},
+	}.Run(t)
+}
+
+func TestUserEnableTOTP(t *testing.T) {
+	u, _ := getTestingUser(t)
+	notStarted, _ := getTestingUser(t)
+	now := time.Now()
+	secret, _ := u.StartTOTP()
+	code := currentCode(t, secret, now)
+
+	type data struct {
+		U    User
+		Code string
+		Now  time.Time
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			codes, err := d.U.EnableTOTP(d.Code, d.Now)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if len(codes) != recoveryCodesNumber {
+					t.Errorf("%s: expected %d recovery codes, got <%v>", msg, recoveryCodesNumber, codes)
+				} else if totp, _ := d.U.GetTOTP(); !totp.Enabled || totp.Secret != "" || totp.RecoveryCodes != recoveryCodesNumber {
+					t.Errorf("%s: totp not enabled: got <%+v>", msg, totp)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user enabled totp", data{U: unknownUser, Code: code, Now: now, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"enabled totp without starting", data{U: notStarted, Code: code, Now: now, ExpectedErr: ERR_TOTP_DISABLED}},
+			{"enabled totp with wrong code", data{U: u, Code: "abcdef", Now: now, ExpectedErr: ERR_TOTP_WRONG_CODE}},
+			{"enabled totp with expired code", data{U: u, Code: code, Now: now.Add(5 * time.Minute), ExpectedErr: ERR_TOTP_WRONG_CODE}},
+			{"", data{U: u, Code: code[:3] + " " + code[3:], Now: now}},
+			{"enabled totp twice", data{U: u, Code: code, Now: now, ExpectedErr: ERR_TOTP_ENABLED}},
+		},
+	}.Run(t)
+}
+
+func TestUserCheckTOTP(t *testing.T) {
+	u, _ := getTestingUser(t)
+	notEnabled, _ := getTestingUser(t)
+	notEnabled.StartTOTP()
+
+	now := time.Now()
+	secret, _ := u.StartTOTP()
+	codes, _ := u.EnableTOTP(currentCode(t, secret, now), now)
+	later := now.Add(2 * time.Minute)
+	nextCode := currentCode(t, secret, later)
+
+	type data struct {
+		U    User
+		Code string
+		Now  time.Time
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.CheckTOTP(d.Code, d.Now); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user checked totp", data{U: unknownUser, Code: nextCode, Now: later, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"checked totp not enabled", data{U: notEnabled, Code: nextCode, Now: later, ExpectedErr: ERR_TOTP_DISABLED}},
+			{"checked already used code", data{U: u, Code: currentCode(t, secret, now), Now: now, ExpectedErr: ERR_TOTP_WRONG_CODE}},
+			{"checked wrong code", data{U: u, Code: "000000", Now: later.Add(time.Hour), ExpectedErr: ERR_TOTP_WRONG_CODE}},
+			{"", data{U: u, Code: nextCode, Now: later}},
+			{"checked code twice", data{U: u, Code: nextCode, Now: later, ExpectedErr: ERR_TOTP_WRONG_CODE}},
+			{"(recovery code)", data{U: u, Code: codes[0], Now: later}},
+			{"(uppercase recovery code)", data{U: u, Code: " " + strings.ToUpper(codes[1]), Now: later}},
+			{"checked recovery code twice", data{U: u, Code: codes[0], Now: later, ExpectedErr: ERR_TOTP_WRONG_CODE}},
+			{"checked wrong recovery code", data{U: u, Code: "00000-00000", Now: later, ExpectedErr: ERR_TOTP_WRONG_CODE}},
+		},
+	}.Run(t)
+
+	if totp, _ := u.GetTOTP(); totp.RecoveryCodes != recoveryCodesNumber-2 {
+		t.Errorf("expected %d recovery codes left, got %d", recoveryCodesNumber-2, totp.RecoveryCodes)
+	}
+}
+
+func TestUserDisableTOTP(t *testing.T) {
+	u, _ := getTestingUser(t)
+	notEnabled, _ := getTestingUser(t)
+
+	now := time.Now()
+	secret, _ := u.StartTOTP()
+	u.EnableTOTP(currentCode(t, secret, now), now)
+	later := now.Add(time.Minute)
+
+	type data struct {
+		U    User
+		Code string
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if err := d.U.DisableTOTP(d.Code, later); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if totp, _ := d.U.GetTOTP(); totp != (TOTP{}) {
+					t.Errorf("%s: expected totp disabled, got <%+v>", msg, totp)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"unknown user disabled totp", data{U: unknownUser, Code: "000000", ExpectedErr: ERR_USER_UNKNOWN}},
+			{"disabled totp not enabled", data{U: notEnabled, Code: "000000", ExpectedErr: ERR_TOTP_DISABLED}},
+			{"disabled totp with wrong code", data{U: u, Code: "00000-00000", ExpectedErr: ERR_TOTP_WRONG_CODE}},
+			{"", data{U: u, Code: currentCode(t, secret, later)}},
+		},
+	}.Run(t)
+}
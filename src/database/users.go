@@ -2,7 +2,6 @@ package database
 
 import (
 	"crypto/rand"
-	"database/sql"
 	"fmt"
 	"net/mail"
 	"strings"
@@ -118,6 +117,13 @@ type User struct {
 	// Email is the user's email
 	Email string
 
+	// EmailVerified is true if the user has confirmed the email
+	EmailVerified bool
+
+	// PendingEmail is the new email of the user, that will replace
+	// the current one once verified. Can be null
+	PendingEmail *string
+
 	// EmailLang is the language in which the users wishes to receive emails
 	EmailLang *string
 
@@ -125,9 +131,12 @@ type User struct {
 	// Can be null
 	Newsletter *string
 
-	// Token is an optional string, that can be generated to delete an user or
-	// to reset its password
-	Token string
+	// Admin is true if the user can access the admin console
+	Admin bool
+
+	// Disabled is true if the user has been disabled by an admin,
+	// and cannot sign in
+	Disabled bool
 
 	// fetched is true if the user if fetched from the database, and not
 	// builded by hand
@@ -163,12 +172,12 @@ func GetUser(field string, value any) (User, error) {
 
 	// Prepares the user
 	user := User{fetched: true}
-	var token *string
 
 	// Queries the data
-	err := db.QueryRow(`SELECT uid, username, email, password, token, email_lang,
-		newsletter FROM ca_users WHERE `+field+`=$1;`, value).
-		Scan(&user.UID, &user.Username, &user.Email, &user.Password, &token, &user.EmailLang, &user.Newsletter)
+	err := db.QueryRow(`SELECT uid, username, email, email_verified, pending_email, password,
+		email_lang, newsletter, admin, disabled FROM ca_users WHERE `+field+`=$1;`, value).
+		Scan(&user.UID, &user.Username, &user.Email, &user.EmailVerified, &user.PendingEmail, &user.Password,
+			&user.EmailLang, &user.Newsletter, &user.Admin, &user.Disabled)
 	if err != nil {
 		// Checks the error
 		if !strings.HasSuffix(err.Error(), "no rows in result set") {
@@ -178,13 +187,6 @@ func GetUser(field string, value any) (User, error) {
 		}
 	}
 
-	// Dereferences token (can be null)
-	if token == nil {
-		user.Token = ""
-	} else {
-		user.Token = *token
-	}
-
 	return user, nil
 }
 
@@ -210,7 +212,9 @@ func GetUsersForBroadcast(newsletter bool) (users []User) {
 	return
 }
 
-// ChangeEmail changes the user's email with a new one.
+// ChangeEmail sets a new email for the user, that will replace the
+// current one only once verified (see VerifyEmail). If the new email is
+// the current one, the pending one is dropped.
 func (u *User) ChangeEmail(newEmail string) error {
 	// Ensures all data is present
 	if err := u.fetch(); err != nil {
@@ -219,19 +223,23 @@ func (u *User) ChangeEmail(newEmail string) error {
 
 	// Ensures the email is actually new, and that it's valid
 	if u.Email == newEmail {
-		return nil
+		newEmail = ""
 	} else if err := checkEmail(newEmail); err != nil {
 		return err
 	}
 
-	// Saves the new one
-	_, err := db.Exec(`UPDATE ca_users SET email=$2 WHERE uid=$1;`, u.UID, newEmail)
+	// Saves the new one as pending
+	_, err := db.Exec(`UPDATE ca_users SET pending_email=NULLIF($2, '') WHERE uid=$1;`, u.UID, newEmail)
 	if err != nil {
 		return ERR_UNKNOWN
 	}
 
 	// Updates struct
-	u.Email = newEmail
+	if newEmail == "" {
+		u.PendingEmail = nil
+	} else {
+		u.PendingEmail = &newEmail
+	}
 	return nil
 }
 
@@ -335,18 +343,11 @@ func (u *User) ChangeUsername(newUsername string) error {
 	return nil
 }
 
-// Delete deletes the user and all of its content
+// Delete deletes the user and all of its content,
+// if the token has been generated with TOKEN_DELETE
 func (u *User) Delete(token string) error {
-	// Ensures all data is present and the token
-	// has been generated
-	if err := u.fetch(); err != nil {
-		return err
-	} else if u.Token == "" {
-		return ERR_USER_WRONG_TOKEN
-	}
-
-	// Compares the tokens
-	if err := compareHash(token, u.Token, ERR_USER_WRONG_TOKEN); err != nil {
+	// Ensures the token is valid
+	if err := u.useToken(TOKEN_DELETE, token); err != nil {
 		return err
 	}
 
@@ -359,29 +360,9 @@ func (u *User) Delete(token string) error {
 	return nil
 }
 
-// GenerateToken generates a new token for the user, then returns it.
-// The Token field will be overwritten with its hash. The plain text
-// is returned.
-func (u *User) GenerateToken() (string, error) {
-	// Generates the token
-	token, hash, err := generateUserToken()
-
-	// Saves it in the database
-	var res sql.Result
-	res, err = db.Exec(`UPDATE ca_users SET token=$2 WHERE uid=$1;`, u.UID, hash)
-	if err != nil {
-		return "", ERR_UNKNOWN
-	} else if ra, _ := res.RowsAffected(); ra != 1 {
-		return "", ERR_USER_UNKNOWN
-	}
-
-	// Updates the struct
-	u.Token = hash
-	return token, nil
-}
-
 // ResetPassword tries to reset the password of the user whose
-// is picked from the struct.
+// is picked from the struct, if the token has been generated
+// with TOKEN_RESET_PASSWORD. The user is signed out from every session.
 // Note: the required field of the struct is Email, not UID.
 func (u *User) ResetPassword(token string, newPassword string) error {
 	// Checks if password is valid
@@ -394,12 +375,12 @@ func (u *User) ResetPassword(token string, newPassword string) error {
 	*u, err = GetUser("email", u.Email)
 	if err != nil {
 		return err
-	} else if u.Token == "" {
-		return ERR_USER_WRONG_TOKEN
+	} else if u.Disabled {
+		return ERR_USER_DISABLED
 	}
 
-	// Compares the tokens
-	if err = compareHash(token, u.Token, ERR_USER_WRONG_TOKEN); err != nil {
+	// Ensures the token is valid
+	if err = u.useToken(TOKEN_RESET_PASSWORD, token); err != nil {
 		return err
 	}
 
@@ -409,14 +390,18 @@ func (u *User) ResetPassword(token string, newPassword string) error {
 		return err
 	}
 
-	// Saves the new password (and resets the token)
-	_, err = db.Exec(`UPDATE ca_users SET password=$2, token=NULL WHERE uid=$1;`, u.UID, hashedPassword)
+	// Saves the new password
+	_, err = db.Exec(`UPDATE ca_users SET password=$2 WHERE uid=$1;`, u.UID, hashedPassword)
 	if err != nil {
 		return ERR_UNKNOWN
 	}
 
+	// Signs the user out from everywhere
+	if err = u.RevokeOtherSessions(""); err != nil {
+		return err
+	}
+
 	// Updates the struct
-	u.Token = ""
 	u.Password = hashedPassword
 	return nil
 }
@@ -433,6 +418,8 @@ func SignIn(username string, password string) (User, error) {
 	err = compareHash(password, user.Password, ERR_USER_WRONG_CREDENTIALS)
 	if err != nil {
 		return User{}, err
+	} else if user.Disabled {
+		return User{}, ERR_USER_DISABLED
 	}
 
 	return user, nil
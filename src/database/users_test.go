@@ -56,7 +56,11 @@ func TestUserChangeEmail(t *testing.T) {
 
 			if d.ExpectedErr == nil {
 				user, _ := GetUser("UID", user.UID)
-				if user.Email != d.NewEmail {
+				if d.NewEmail == user.Email {
+					if user.PendingEmail != nil {
+						t.Errorf("%s, pending email not dropped", msg)
+					}
+				} else if user.PendingEmail == nil || *user.PendingEmail != d.NewEmail {
 					t.Errorf("%s, changes not saved", msg)
 				}
 			}
@@ -271,7 +275,8 @@ func TestUserChangeUsername(t *testing.T) {
 
 func TestUserDeleteUser(t *testing.T) {
 	user, _ := getTestingUser(t)
-	token, _ := user.GenerateToken()
+	token, _ := user.GenerateToken(TOKEN_DELETE)
+	resetToken, _ := user.GenerateToken(TOKEN_RESET_PASSWORD)
 
 	user.ShoppingList().Append("e")
 
@@ -321,44 +326,12 @@ func TestUserDeleteUser(t *testing.T) {
 				data{User: user, Token: token + "t", ExpectedErr: ERR_USER_WRONG_TOKEN},
 			},
 			{
-				"",
-				data{User: user, Token: token},
-			},
-		},
-	}.Run(t)
-}
-
-func TestUserGenerateToken(t *testing.T) {
-	user, _ := getTestingUser(t)
-
-	type data struct {
-		User        User
-		ExpectedErr error
-	}
-
-	testSuite[data]{
-		Target: func(t *testing.T, msg string, d data) {
-			token, err := d.User.GenerateToken()
-			if err != d.ExpectedErr {
-				t.Errorf("%s: expected <%v>, got <%v>", msg, d.ExpectedErr, err)
-			}
-
-			if d.ExpectedErr == nil {
-				user, _ := GetUser("UID", d.User.UID)
-				if err := compareHash(token, user.Token, ERR_UNKNOWN); err != nil {
-					t.Errorf("%s, saved token does not match returned one", msg)
-				}
-			}
-		},
-
-		Cases: []testCase[data]{
-			{
-				"generated token of unknown user",
-				data{User: unknownUser, ExpectedErr: ERR_USER_UNKNOWN},
+				"deleted user with a token for another purpose",
+				data{User: user, Token: resetToken, ExpectedErr: ERR_USER_WRONG_TOKEN},
 			},
 			{
 				"",
-				data{User: user},
+				data{User: user, Token: token},
 			},
 		},
 	}.Run(t)
@@ -408,7 +381,9 @@ func TestUserGetUser(t *testing.T) {
 
 func TestUserResetPassword(t *testing.T) {
 	user, password := getTestingUser(t)
-	token, _ := user.GenerateToken()
+	token, _ := user.GenerateToken(TOKEN_RESET_PASSWORD)
+	deleteToken, _ := user.GenerateToken(TOKEN_DELETE)
+	getTestingSession(t, user)
 	otherUser, _ := getTestingUser(t)
 
 	type data struct {
@@ -428,10 +403,10 @@ func TestUserResetPassword(t *testing.T) {
 
 			if d.ExpectedErr == nil {
 				user, _ := GetUser("email", d.User.Email)
-				if user.Token != "" {
-					t.Errorf("%s, token wasn't dropped", msg)
-				} else if err := compareHash(d.NewPassword, user.Password, ERR_UNKNOWN); err != nil {
+				if err := compareHash(d.NewPassword, user.Password, ERR_UNKNOWN); err != nil {
 					t.Errorf("%s, new password not saved", msg)
+				} else if sessions, _ := user.GetSessions(""); len(sessions) > 0 {
+					t.Errorf("%s, sessions not revoked", msg)
 				}
 			}
 		},
@@ -449,6 +424,10 @@ func TestUserResetPassword(t *testing.T) {
 				"reset password with wrong token",
 				data{User: user, Token: token + "t", NewPassword: password, ExpectedErr: ERR_USER_WRONG_TOKEN},
 			},
+			{
+				"reset password with a token for another purpose",
+				data{User: user, Token: deleteToken, NewPassword: password, ExpectedErr: ERR_USER_WRONG_TOKEN},
+			},
 			{
 				"reset password with an invalid one",
 				data{User: user, Token: token, NewPassword: "p", ExpectedErr: ERR_USER_PASS_TOO_SHORT},
@@ -457,6 +436,10 @@ func TestUserResetPassword(t *testing.T) {
 				"",
 				data{User: user, Token: token, NewPassword: password},
 			},
+			{
+				"reset password twice with the same token",
+				data{User: user, Token: token, NewPassword: password, ExpectedErr: ERR_USER_WRONG_TOKEN},
+			},
 		},
 	}.Run(t)
 }
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"cucinassistant/configs"
+)
+
+// verificationDuration is how long an email verification link is valid
+const verificationDuration = 24 * time.Hour
+
+// unverifiedEmail returns the email of the user that has to be verified:
+// the pending one or, if there isn't, the current one (if it has never
+// been verified). If there is nothing to verify, it returns an empty string.
+func (u User) unverifiedEmail() string {
+	if u.PendingEmail != nil {
+		return *u.PendingEmail
+	} else if !u.EmailVerified {
+		return u.Email
+	}
+
+	return ""
+}
+
+// signVerification returns the signature of an email verification.
+// Since it contains the email, a link is no longer valid once the email
+// has been verified or another one has been requested.
+func signVerification(UID int, email string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(configs.SessionSecret))
+	fmt.Fprintf(mac, "verify-email\n%d\n%s\n%d", UID, email, expires)
+	return encodeBase64URL(mac.Sum(nil))
+}
+
+// EmailVerificationToken returns a signed token, valid for one day from now,
+// that can be used to verify the unverified email of the user (the pending
+// one, if there is one)
+func (u *User) EmailVerificationToken(now time.Time) (string, error) {
+	// Ensures all data is present
+	if err := u.fetch(); err != nil {
+		return "", err
+	}
+
+	// Ensures there is something to verify
+	email := u.unverifiedEmail()
+	if email == "" {
+		return "", ERR_USER_MAIL_VERIFIED
+	}
+
+	expires := now.Add(verificationDuration).Unix()
+	return fmt.Sprintf("%d.%d.%s", u.UID, expires, signVerification(u.UID, email, expires)), nil
+}
+
+// VerifyEmail verifies the email whose token has been generated with
+// EmailVerificationToken. If it was a pending email, it replaces the
+// current one, which is returned.
+func VerifyEmail(token string, now time.Time) (user User, oldEmail string, err error) {
+	// Parses the token
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return user, "", ERR_USER_WRONG_TOKEN
+	}
+	UID, errU := strconv.Atoi(parts[0])
+	expires, errE := strconv.ParseInt(parts[1], 10, 64)
+	if errU != nil || errE != nil {
+		return user, "", ERR_USER_WRONG_TOKEN
+	}
+
+	// Fetches the user
+	if user, err = GetUser("UID", UID); err == ERR_USER_UNKNOWN {
+		return user, "", ERR_USER_WRONG_TOKEN
+	} else if err != nil {
+		return user, "", err
+	}
+
+	// Checks the signature and the expiration
+	email := user.unverifiedEmail()
+	if email == "" || !hmac.Equal([]byte(parts[2]), []byte(signVerification(UID, email, expires))) {
+		return user, "", ERR_USER_WRONG_TOKEN
+	} else if now.Unix() > expires {
+		return user, "", ERR_USER_TOKEN_EXPIRED
+	}
+
+	// Replaces the email, if it was a pending one
+	if user.PendingEmail != nil {
+		_, err = db.Exec(`UPDATE ca_users SET email=pending_email, pending_email=NULL, email_verified=TRUE WHERE uid=$1;`, UID)
+		if err != nil {
+			if pqe, ok := err.(*pq.Error); ok && pqe.Code == "23505" {
+				return user, "", ERR_USER_MAIL_UNAVAIL
+			} else {
+				return user, "", ERR_UNKNOWN
+			}
+		}
+
+		oldEmail = user.Email
+		user.Email = email
+		user.PendingEmail = nil
+	} else {
+		_, err = db.Exec(`UPDATE ca_users SET email_verified=TRUE WHERE uid=$1;`, UID)
+		if err != nil {
+			return user, "", ERR_UNKNOWN
+		}
+	}
+
+	user.EmailVerified = true
+	return user, oldEmail, nil
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+// getVerificationToken returns a token to verify the email of the user
+func getVerificationToken(t *testing.T, u User, now time.Time) string {
+	token, err := u.EmailVerificationToken(now)
+	if err != nil {
+		t.Fatalf("Cannot generate verification token: %s", err.Error())
+	}
+
+	return token
+}
+
+func TestUserEmailVerificationToken(t *testing.T) {
+	user, _ := getTestingUser(t)
+	verified, _ := getTestingUser(t)
+	VerifyEmail(getVerificationToken(t, verified, time.Now()), time.Now())
+
+	type data struct {
+		U User
+
+		ExpectedErr error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			if _, err := d.U.EmailVerificationToken(time.Now()); err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"generated token of unknown user", data{U: unknownUser, ExpectedErr: ERR_USER_UNKNOWN}},
+			{"generated token of verified email", data{U: User{UID: verified.UID}, ExpectedErr: ERR_USER_MAIL_VERIFIED}},
+			{"", data{U: user}},
+		},
+	}.Run(t)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	now := time.Now()
+
+	user, _ := getTestingUser(t)
+	userToken := getVerificationToken(t, user, now)
+
+	changed, _ := getTestingUser(t)
+	VerifyEmail(getVerificationToken(t, changed, now), now)
+	newEmail := generateTestingUser().Email
+	changed.ChangeEmail(newEmail)
+	changedToken := getVerificationToken(t, User{UID: changed.UID}, now)
+
+	expired, _ := getTestingUser(t)
+	expiredToken := getVerificationToken(t, expired, now.Add(-25*time.Hour))
+
+	type data struct {
+		Token string
+
+		ExpectedEmail    string
+		ExpectedOldEmail string
+		ExpectedErr      error
+	}
+
+	testSuite[data]{
+		Target: func(t *testing.T, msg string, d data) {
+			got, oldEmail, err := VerifyEmail(d.Token, now)
+			if err != d.ExpectedErr {
+				t.Errorf("%s: expected err <%v>, got <%v>", msg, d.ExpectedErr, err)
+			} else if err == nil {
+				if oldEmail != d.ExpectedOldEmail {
+					t.Errorf("%s: expected old email <%s>, got <%s>", msg, d.ExpectedOldEmail, oldEmail)
+				}
+
+				saved, _ := GetUser("UID", got.UID)
+				if !saved.EmailVerified || saved.PendingEmail != nil || saved.Email != d.ExpectedEmail {
+					t.Errorf("%s: expected verified email <%s>, got <%+v>", msg, d.ExpectedEmail, saved)
+				}
+			}
+		},
+
+		Cases: []testCase[data]{
+			{"verified with malformed token", data{Token: "token", ExpectedErr: ERR_USER_WRONG_TOKEN}},
+			{"verified with token of unknown user", data{Token: "0.0.signature", ExpectedErr: ERR_USER_WRONG_TOKEN}},
+			{"verified with tampered token", data{Token: userToken + "x", ExpectedErr: ERR_USER_WRONG_TOKEN}},
+			{"verified with expired token", data{Token: expiredToken, ExpectedErr: ERR_USER_TOKEN_EXPIRED}},
+			{"", data{Token: userToken, ExpectedEmail: user.Email}},
+			{"verified twice", data{Token: userToken, ExpectedErr: ERR_USER_WRONG_TOKEN}},
+			{"(pending email)", data{Token: changedToken, ExpectedEmail: newEmail, ExpectedOldEmail: changed.Email}},
+		},
+	}.Run(t)
+}
@@ -0,0 +1,256 @@
+package database
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+)
+
+// RelyingParty identifies the website the passkeys are created for
+type RelyingParty struct {
+	// ID is the domain of the website (like cucinassistant.com)
+	ID string
+
+	// Origin is the origin of the website (like https://cucinassistant.com)
+	Origin string
+}
+
+// The flags of the authenticator data
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+// The COSE algorithms supported
+const (
+	coseES256 = -7
+	coseEdDSA = -8
+	coseRS256 = -257
+)
+
+// clientData contains the fields of the client data JSON
+// that are checked
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// authenticatorData contains the parsed authenticator data
+type authenticatorData struct {
+	// rpIDHash is the SHA-256 of the relying party's ID
+	rpIDHash []byte
+
+	// flags are the flags (see flagUserPresent and the others)
+	flags byte
+
+	// signCount is the signature counter
+	signCount uint32
+
+	// credentialID is the ID of the credential, and it's set
+	// only when a credential is created
+	credentialID []byte
+
+	// publicKey is the COSE public key of the credential, and it's set
+	// only when a credential is created
+	publicKey []byte
+}
+
+// encodeBase64URL encodes some bytes as base64url, without padding
+func encodeBase64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// checkClientData ensures the client data JSON has the expected type,
+// challenge and origin
+func checkClientData(raw []byte, expectedType string, challenge string, rp RelyingParty) error {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return ERR_PASSKEY_INVALID
+	}
+
+	if cd.Type != expectedType || cd.Origin != rp.Origin || challenge == "" ||
+		subtle.ConstantTimeCompare([]byte(cd.Challenge), []byte(challenge)) != 1 {
+		return ERR_PASSKEY_INVALID
+	}
+
+	return nil
+}
+
+// parseAuthenticatorData parses the authenticator data, and ensures it
+// was created for the relying party with the user present and verified
+// (since passkeys replace the password)
+func parseAuthenticatorData(data []byte, rp RelyingParty) (authenticatorData, error) {
+	var ad authenticatorData
+
+	if len(data) < 37 {
+		return ad, ERR_PASSKEY_INVALID
+	}
+
+	ad.rpIDHash = data[:32]
+	ad.flags = data[32]
+	ad.signCount = binary.BigEndian.Uint32(data[33:37])
+	data = data[37:]
+
+	// Checks the relying party and the flags
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(ad.rpIDHash, rpIDHash[:]) || ad.flags&flagUserPresent == 0 ||
+		ad.flags&flagUserVerified == 0 {
+		return ad, ERR_PASSKEY_INVALID
+	}
+
+	// Reads the attested credential data (AAGUID, ID length, ID and key)
+	if ad.flags&flagAttestedData != 0 {
+		if len(data) < 18 {
+			return ad, ERR_PASSKEY_INVALID
+		}
+
+		length := int(binary.BigEndian.Uint16(data[16:18]))
+		data = data[18:]
+		if length == 0 || length > 1023 || len(data) < length {
+			return ad, ERR_PASSKEY_INVALID
+		}
+		ad.credentialID = data[:length]
+		data = data[length:]
+
+		// The key is followed by the extensions, if any
+		_, rest, err := decodeCBOR(data)
+		if err != nil {
+			return ad, ERR_PASSKEY_INVALID
+		}
+		ad.publicKey = data[:len(data)-len(rest)]
+	}
+
+	return ad, nil
+}
+
+// parseAttestationObject parses the attestation object returned when
+// a credential is created. Since no attestation is requested, its
+// statement is not verified.
+func parseAttestationObject(data []byte, rp RelyingParty) (authenticatorData, error) {
+	object, _, err := decodeCBOR(data)
+	if err != nil {
+		return authenticatorData{}, ERR_PASSKEY_INVALID
+	}
+
+	m, ok := object.(map[any]any)
+	if !ok {
+		return authenticatorData{}, ERR_PASSKEY_INVALID
+	}
+
+	raw, ok := m["authData"].([]byte)
+	if !ok {
+		return authenticatorData{}, ERR_PASSKEY_INVALID
+	}
+
+	ad, err := parseAuthenticatorData(raw, rp)
+	if err != nil {
+		return ad, err
+	} else if ad.credentialID == nil {
+		return ad, ERR_PASSKEY_INVALID
+	}
+
+	// Makes sure the key is supported
+	if _, err = parseCOSEKey(ad.publicKey); err != nil {
+		return ad, err
+	}
+
+	return ad, nil
+}
+
+// parseCOSEKey returns the public key contained in a COSE key
+// (ES256, EdDSA or RS256)
+func parseCOSEKey(data []byte) (crypto.PublicKey, error) {
+	raw, _, err := decodeCBOR(data)
+	if err != nil {
+		return nil, ERR_PASSKEY_INVALID
+	}
+
+	key, ok := raw.(map[any]any)
+	if !ok {
+		return nil, ERR_PASSKEY_INVALID
+	}
+
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+	switch {
+	case kty == 2 && alg == coseES256:
+		// EC2 key on P-256
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			return nil, ERR_PASSKEY_INVALID
+		}
+
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ERR_PASSKEY_INVALID
+		}
+		return pub, nil
+
+	case kty == 1 && alg == coseEdDSA:
+		// OKP key on Ed25519
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize {
+			return nil, ERR_PASSKEY_INVALID
+		}
+		return ed25519.PublicKey(x), nil
+
+	case kty == 3 && alg == coseRS256:
+		// RSA key
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, ERR_PASSKEY_INVALID
+		}
+
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, nil
+	}
+
+	return nil, ERR_PASSKEY_INVALID
+}
+
+// verifyAssertion verifies the signature of an assertion, made with a
+// COSE public key over the authenticator data and the client data
+func verifyAssertion(publicKey []byte, authData []byte, clientDataJSON []byte, signature []byte) error {
+	key, err := parseCOSEKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte{}, authData...), clientDataHash[:]...)
+	hash := sha256.Sum256(signed)
+
+	var valid bool
+	switch pub := key.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(pub, hash[:], signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(pub, signed, signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], signature) == nil
+	}
+
+	if !valid {
+		return ERR_PASSKEY_INVALID
+	}
+
+	return nil
+}
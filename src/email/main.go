@@ -34,6 +34,18 @@ var (
 
 	// Goodbye is sent after an account has been deleted
 	Goodbye = Email{Subject: langs.STR_GOODBYE, Content: langs.STR_GOODBYE_EMAIL}
+
+	// VerifyEmail is used to send the user the link to verify an email
+	VerifyEmail = Email{Subject: langs.STR_VERIFY_EMAIL, Content: langs.STR_VERIFY_EMAIL_EMAIL}
+
+	// EmailChanged is sent to the old email after it has been replaced
+	EmailChanged = Email{Subject: langs.STR_CHANGE_EMAIL, Content: langs.STR_EMAIL_CHANGED_EMAIL}
+
+	// TOTPEnabled is sent when the two-factor authentication is enabled
+	TOTPEnabled = Email{Subject: langs.STR_TOTP, Content: langs.STR_TOTP_ENABLED_EMAIL}
+
+	// TOTPDisabled is sent when the two-factor authentication is disabled
+	TOTPDisabled = Email{Subject: langs.STR_TOTP, Content: langs.STR_TOTP_DISABLED_EMAIL}
 )
 
 // Write creates an EmailBody.
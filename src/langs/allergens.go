@@ -0,0 +1,93 @@
+package langs
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"cucinassistant/database"
+)
+
+// allergenNames contains the String of every allergen
+var allergenNames = map[database.Allergen]String{
+	database.ALLERGEN_CELERY:      STR_ALLERGEN_CELERY,
+	database.ALLERGEN_CRUSTACEANS: STR_ALLERGEN_CRUSTACEANS,
+	database.ALLERGEN_EGGS:        STR_ALLERGEN_EGGS,
+	database.ALLERGEN_FISH:        STR_ALLERGEN_FISH,
+	database.ALLERGEN_GLUTEN:      STR_ALLERGEN_GLUTEN,
+	database.ALLERGEN_HONEY:       STR_ALLERGEN_HONEY,
+	database.ALLERGEN_LUPIN:       STR_ALLERGEN_LUPIN,
+	database.ALLERGEN_MEAT:        STR_ALLERGEN_MEAT,
+	database.ALLERGEN_MILK:        STR_ALLERGEN_MILK,
+	database.ALLERGEN_MOLLUSCS:    STR_ALLERGEN_MOLLUSCS,
+	database.ALLERGEN_MUSTARD:     STR_ALLERGEN_MUSTARD,
+	database.ALLERGEN_NUTS:        STR_ALLERGEN_NUTS,
+	database.ALLERGEN_PEANUTS:     STR_ALLERGEN_PEANUTS,
+	database.ALLERGEN_SESAME:      STR_ALLERGEN_SESAME,
+	database.ALLERGEN_SOY:         STR_ALLERGEN_SOY,
+	database.ALLERGEN_SULPHITES:   STR_ALLERGEN_SULPHITES,
+}
+
+// dietNames contains the String of every diet
+var dietNames = map[database.Diet]String{
+	database.DIET_VEGAN:      STR_DIET_VEGAN,
+	database.DIET_VEGETARIAN: STR_DIET_VEGETARIAN,
+}
+
+// AllergenName returns the String with the name of an allergen
+func AllergenName(a database.Allergen) String {
+	return allergenNames[a]
+}
+
+// DietName returns the String with the name of a diet
+func DietName(d database.Diet) String {
+	return dietNames[d]
+}
+
+// normalize lowercases a text and replaces everything that is
+// not a letter with a space, so that it can be searched by words
+func normalize(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		} else {
+			return ' '
+		}
+	}, text)
+
+	return " " + strings.Join(strings.Fields(text), " ") + " "
+}
+
+// DetectAllergens looks for the keywords of the given language
+// in a text (like the ingredients of a recipe), and returns the
+// allergens found, sorted
+func DetectAllergens(lang string, text string) []database.Allergen {
+	var found []database.Allergen
+
+	text = normalize(text)
+	for allergen, keywords := range Get(&lang).Allergens {
+		for _, keyword := range keywords {
+			if strings.Contains(text, normalize(keyword)) {
+				found = append(found, allergen)
+				break
+			}
+		}
+	}
+
+	slices.Sort(found)
+	return found
+}
+
+// FilterAllergens returns the allergens of a text (see DetectAllergens)
+// that are also in avoided
+func FilterAllergens(lang string, text string, avoided []database.Allergen) []database.Allergen {
+	var found []database.Allergen
+
+	for _, a := range DetectAllergens(lang, text) {
+		if slices.Contains(avoided, a) {
+			found = append(found, a)
+		}
+	}
+
+	return found
+}
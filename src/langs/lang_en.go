@@ -5,169 +5,457 @@ import (
 )
 
 var english *Lang = &Lang{
-	Tag:  "en",
-	Name: "English",
+	Tag:          "en",
+	Name:         "English",
+	SearchConfig: "english",
 
 	Strings: map[String]string{
-		STR_ADD:                                 "Add",
-		STR_ADD_ARTICLES:                        "Add articles",
-		STR_ADD_DAY:                             "Add day",
-		STR_ADD_MEAL:                            "Add meal",
-		STR_ALL_ARTICLES:                        "All articles",
-		STR_APPEND_ENTRIES:                      "Add entries",
-		STR_ARTICLES:                            "Articles",
-		STR_CANCEL:                              "Cancel",
-		STR_CHANGE_EMAIL:                        "Email change",
-		STR_CHANGE_PASSWORD:                     "Password change",
-		STR_CHANGE_USERNAME:                     "Username change",
-		STR_CLICK_HERE:                          "click here",
-		STR_CLONE:                               "Clone",
-		STR_CODE:                                "Source code",
-		STR_CONFIRM:                             "Confirm",
-		STR_CURRENT_SEARCH:                      "Current search",
-		STR_DAYS:                                "Days",
-		STR_DELETE:                              "Delete",
-		STR_DELETE_CONFIRM_EMAIL:                "to permanently delete your account,",
-		STR_DELETE_LINK:                         "Delete link",
-		STR_DELETE_MENU:                         "Delete menu",
-		STR_DELETE_MENU_TEXT:                    "Are you sure you want to delete this menu?",
-		STR_DELETE_RECIPE:                       "Delete recipe",
-		STR_DELETE_RECIPE_TEXT:                  "Are you sure you want to delete this recipe?",
-		STR_DELETE_SECTION_TEXT:                 "Are you sure? All the articles inside this section will be deleted.",
-		STR_DELETE_SELECTED:                     "Delete selected",
-		STR_DELETE_USER:                         "Delete account",
-		STR_DELETE_USER_TEXT1:                   "Are you sure to delete your account?",
-		STR_DELETE_USER_TEXT2:                   "Are you REALLY sure to delete your account? This action is irreversible.",
-		STR_DIRECTIONS:                          "Directions",
-		STR_EDIT:                                "Edit",
-		STR_EDIT_ARTICLE:                        "Edit article",
-		STR_EDIT_DAY:                            "Edit day",
-		STR_EDIT_MEALS:                          "Edit meals",
-		STR_EDIT_ENTRY:                          "Edit entry",
-		STR_EDIT_MENU:                           "Edit menu",
-		STR_EDIT_RECIPE:                         "Edit recipe",
-		STR_EDIT_SECTION:                        "Edit section",
-		STR_EMAIL:                               "Email",
-		STR_EMAIL_CHANGED:                       "Email changed succesfully",
-		STR_EMAIL_LANG:                          "Email language",
-		STR_EMAIL_SENT:                          "We've sent you an email: please, check your inbox",
-		STR_EMAIL_SETTINGS:                      "Email settings",
-		STR_EXPIRATION:                          "Expiration date",
-		STR_FORGOT_PASSWORD:                     "Forgot password",
-		STR_FROM:                                "From",
-		STR_GENERATE_LINK:                       "Generate link",
-		STR_GOOD_MORNING:                        "Good morning " + placeholder + ",",
-		STR_GOODBYE:                             "Goodbye",
-		STR_GOODBYE_EMAIL:                       "your account has been permanently deleted.",
-		STR_HISTORY:                             "History",
-		STR_INFO:                                "Further informations",
-		STR_INFO_CODE:                           "CucinAssistant is completely open source; you can see the source on <a href='" + placeholder + "'>Github</a>.",
-		STR_INFO_HISTORY:                        "CucinAssistant is a project by Gianluca Parri, ideated in 2023 after its new life as <i>fuorisede</i>.<br>Since then, it's continuosly evolving, even if slowly.",
-		STR_INFO_INTRO:                          "<b>CucinAssistant</b> is a simple website, with which you (and your roommates) can easily manage <b>menues</b>, <b>recipes</b>, <b>articles</b> in storage (with quantities and expirations) and a <b>shopping list</b>.",
-		STR_INFO_STATS:                          "<p>See the <a href='" + placeholder + "'>this page</a>.",
-		STR_INFO_SUPPORT:                        "For doubts, questions or suggestions, you can write an email at <a href='mailto:" + placeholder + "'>" + placeholder + "</a>.",
-		STR_INFO_TUTORIAL:                       "There is a <a href='" + placeholder + "'>tutorial</a>, updated to the last version.",
-		STR_INFO_VERSION:                        "The current version is the " + placeholder + ".",
-		STR_INGREDIENTS:                         "Ingredients",
-		STR_INVALID_DATES:                       "Invalid dates",
-		STR_LANGUAGE:                            "Language",
-		STR_LOGOUT:                              "Logout",
-		STR_MEALS:                               "Meals",
-		STR_MEALS_NUMBER:                        "Number of meals per day",
-		STR_MENUS:                               "Menus",
-		STR_NAME:                                "Name",
-		STR_NETWORK_ERROR:                       "Network error",
-		STR_NEW_DAY:                             "New day",
-		STR_NEW_EMAIL:                           "New email",
-		STR_NEW_MENU:                            "New menu",
-		STR_NEW_PASSWORD:                        "New password",
-		STR_NEW_RECIPE:                          "New recipe",
-		STR_NEW_SECTION:                         "New section",
-		STR_NEW_USERNAME:                        "New username",
-		STR_NOREPLY:                             "This email is automatically generated. Please do not reply.",
-		STR_NOTES:                               "Notes",
-		STR_OK:                                  "Ok",
-		STR_OLD_PASSWORD:                        "Old password",
-		STR_ORDER_CHANGED:                       "The order of the articles has changed",
-		STR_PAGE_NOT_FOUND:                      "Page not found",
-		STR_PASSWORD:                            "Password",
-		STR_PASSWORD_CHANGED:                    "Password changed successfully",
-		STR_PASSWORD_CHANGED_EMAIL:              "recently your password has been changed.",
-		STR_PRINT:                               "Print",
-		STR_QUANTITY:                            "Quantity",
-		STR_RECIPE_IS_SHARED:                    "This recipe is currently shared at this link:",
-		STR_RECIPE_IS_UNSHARED:                  "This recipe is not currently shared",
-		STR_RECIPES:                             "Recipes",
-		STR_RECIPES_EMPTY:                       "No recipes found.",
-		STR_REGARDS:                             "Regards",
-		STR_REGENERATE_LINK:                     "Regenerate link",
-		STR_REPEAT_PASSWORD:                     "Repeat password",
-		STR_RESET_PASSWORD:                      "Reset password",
-		STR_RESET_PASSWORD_EMAIL:                "to reset your password,",
-		STR_SAVE:                                "Save",
-		STR_SEARCH_ARTICLES:                     "Search articles",
-		STR_SEARCH_EMPTY:                        "No articles found.",
-		STR_SECTION:                             "Section",
-		STR_SECTION_EMPTY:                       "This section is empty.",
-		STR_SECTIONS:                            "Storage sections",
-		STR_SEE_TAGS:                            "See tags",
-		STR_SETTINGS:                            "Settings",
-		STR_SETTINGS_SAVED:                      "Settings saved",
-		STR_SHARE:                               "Share",
-		STR_SHOPPINGLIST:                        "Shopping list",
-		STR_SHOPPINGLIST_EMPTY:                  "The list is empty.",
-		STR_SIGNIN:                              "Sign in",
-		STR_SIGNUP:                              "Sign up",
-		STR_SIGNUP_DONE:                         "Succesfully signed up",
-		STR_STARS:                               "Stars",
-		STR_STATS:                               "Statistics",
-		STR_STATS_ARTICLES:                      placeholder + " articles",
-		STR_STATS_ENTRIES:                       placeholder + " entries",
-		STR_STATS_MENUS:                         placeholder + " menus",
-		STR_STATS_RECIPES:                       placeholder + " recipes",
-		STR_STATS_SECTIONS:                      placeholder + " sections",
-		STR_STATS_USERS:                         placeholder + " users",
-		STR_STORAGE:                             "Storage",
-		STR_STORAGE_EMPTY:                       "The storage is empty",
-		STR_SUPPORT:                             "Support",
-		STR_TAGS:                                "Tags",
-		STR_TO:                                  "To",
-		STR_TUTORIAL:                            "Tutorial",
-		STR_UNKNOWN_LANG:                        "Unknown language",
-		STR_UNKNOWN_REQUEST:                     "Unknown request",
-		STR_UNMATCHING_PASSWORDS:                "The two passwords do not match",
-		STR_UNSUBSCRIBE:                         "To unsubscribe, ",
-		STR_USER_CREATED:                        "Account created succesfully",
-		STR_USER_DELETED:                        "Account deleted succesfully",
-		STR_USERNAME:                            "Username",
-		STR_USERNAME_CHANGED:                    "Username changed succesfully",
-		STR_VERSION:                             "Version",
-		STR_WANT_NEWSLETTER:                     "I want to receive the newsletter",
-		STR_WELCOME_EMAIL:                       "Welcome to CucinAssistant!",
-		STR_WELCOMEBACK:                         "Welcome back, " + placeholder + "!",
-		String(database.ERR_ARTICLE_DUPLICATED): "An article with this name and expiration already exists",
+		STR_ACCOUNT_DISABLED:        "Disabled",
+		STR_ADAPTED_FROM:            "Adapted from a recipe by " + placeholder,
+		STR_ADD:                     "Add",
+		STR_ADD_ARTICLES:            "Add articles",
+		STR_ADD_DAY:                 "Add day",
+		STR_ADD_MEAL:                "Add meal",
+		STR_ADD_PASSKEY:             "Add passkey",
+		STR_ADD_SLOT:                "Add slot",
+		STR_ADMIN:                   "Admin console",
+		STR_ADMIN_ROLE:              "Admin",
+		STR_ALLERGENS:               "Allergens",
+		STR_ALLERGEN_CELERY:         "Celery",
+		STR_ALLERGEN_CRUSTACEANS:    "Crustaceans",
+		STR_ALLERGEN_EGGS:           "Eggs",
+		STR_ALLERGEN_FISH:           "Fish",
+		STR_ALLERGEN_GLUTEN:         "Gluten",
+		STR_ALLERGEN_HONEY:          "Honey",
+		STR_ALLERGEN_LUPIN:          "Lupin",
+		STR_ALLERGEN_MEAT:           "Meat",
+		STR_ALLERGEN_MILK:           "Milk",
+		STR_ALLERGEN_MOLLUSCS:       "Molluscs",
+		STR_ALLERGEN_MUSTARD:        "Mustard",
+		STR_ALLERGEN_NUTS:           "Nuts",
+		STR_ALLERGEN_PEANUTS:        "Peanuts",
+		STR_ALLERGEN_SESAME:         "Sesame",
+		STR_ALLERGEN_SOY:            "Soy",
+		STR_ALLERGEN_SULPHITES:      "Sulphites",
+		STR_ALL_ARTICLES:            "All articles",
+		STR_ALL_RECIPES:             "All recipes",
+		STR_APPEND_ENTRIES:          "Add entries",
+		STR_ARTICLES:                "Articles",
+		STR_ARTICLES_COUNT:          "%% articles",
+		STR_AUDIT_DELETE:            "Deleted the account of %%",
+		STR_AUDIT_DISABLE:           "Disabled the account of %%",
+		STR_AUDIT_ENABLE:            "Enabled the account of %%",
+		STR_AUDIT_LOG:               "Audit log",
+		STR_AUDIT_LOG_EMPTY:         "No action has been taken yet",
+		STR_AUDIT_RESEND:            "Sent the verification email to %% again",
+		STR_BIO:                     "Bio",
+		STR_CALENDAR:                "Calendar",
+		STR_CALENDAR_DISABLED:       "The calendar is disabled.",
+		STR_CALENDAR_EXPIRATIONS:    "Storage expirations",
+		STR_CALENDAR_EXPIRES:        "%% expires",
+		STR_CALENDAR_MEALS:          "Meals (breakfast, lunch and dinner at their times)",
+		STR_CALENDAR_MENUS:          "Menus (one event per day)",
+		STR_CALENDAR_TEXT:           "Subscribe to these addresses from your calendar app to see your menus and the expiration dates of your storage. Anyone who knows them can see their content: if you have shared them by mistake, generate new ones.",
+		STR_CANCEL:                  "Cancel",
+		STR_CARBOHYDRATES:           "Carbohydrates",
+		STR_CATEGORY:                "Category",
+		STR_CATEGORY_BREAD:          "Bread and baked goods",
+		STR_CATEGORY_CHEESE:         "Cheese",
+		STR_CATEGORY_DAIRY:          "Milk and dairy",
+		STR_CATEGORY_FISH:           "Fish",
+		STR_CATEGORY_FRUIT:          "Fruit",
+		STR_CATEGORY_LEFTOVERS:      "Leftovers",
+		STR_CATEGORY_MEAT:           "Meat",
+		STR_CATEGORY_SAUCES:         "Sauces and preserves",
+		STR_CATEGORY_VEGETABLES:     "Vegetables",
+		STR_CHANGE_EMAIL:            "Email change",
+		STR_CHANGE_PASSWORD:         "Password change",
+		STR_CHANGE_USERNAME:         "Username change",
+		STR_CLICK_HERE:              "click here",
+		STR_CLONE:                   "Clone",
+		STR_CODE:                    "Source code",
+		STR_COLLECTIONS:             "Public collections",
+		STR_COLLECTIONS_TEXT:        "Every tag can be published as a collection: all the recipes with that tag will be visible at the same link.",
+		STR_COLLECTION_BY:           "A collection by " + placeholder,
+		STR_COLLECTION_SAVED:        "Recipes saved",
+		STR_COLLECTION_SKIPPED:      "Recipes saved, except for the ones with the same name as one of yours: %%",
+		STR_COMMENTS:                "Comments",
+		STR_CONFIRM:                 "Confirm",
+		STR_CONTAINS:                "Contains",
+		STR_COOK:                    "Who cooked it",
+		STR_COOKED:                  "I cooked it",
+		STR_COOKING_LOG:             "Cooking log",
+		STR_COOKING_LOG_EMPTY:       "This recipe has never been cooked",
+		STR_COOK_NOW:                "What can I cook?",
+		STR_COOK_NOW_EMPTY:          "None of your recipes uses the articles in the storage",
+		STR_COOK_NOW_TEXT:           "Your recipes, ordered by how many of their ingredients are in the storage. The articles about to expire count more.",
+		STR_CURRENT_SEARCH:          "Current search",
+		STR_CUSTOM_SLOTS:            "Other slots (one per line)",
+		STR_DATE:                    "Date",
+		STR_DAYS:                    "Days",
+		STR_DELETE:                  "Delete",
+		STR_DELETE_ACCOUNT_TEXT:     "Are you sure? The account and all of its data will be permanently deleted.",
+		STR_DELETE_CONFIRM_EMAIL:    "to permanently delete your account,",
+		STR_DELETE_LINK:             "Delete link",
+		STR_DELETE_MENU:             "Delete menu",
+		STR_DELETE_MENU_TEXT:        "Are you sure you want to delete this menu?",
+		STR_DELETE_RECIPE:           "Delete recipe",
+		STR_DELETE_RECIPE_TEXT:      "Are you sure you want to delete this recipe?",
+		STR_DELETE_SECTION_TEXT:     "Are you sure? All the articles inside this section will be deleted.",
+		STR_DELETE_SELECTED:         "Delete selected",
+		STR_DELETE_USER:             "Delete account",
+		STR_DELETE_USER_TEXT1:       "Are you sure to delete your account?",
+		STR_DELETE_USER_TEXT2:       "Are you REALLY sure to delete your account? This action is irreversible.",
+		STR_DIETARY_PROFILE:         "Dietary restrictions",
+		STR_DIETARY_PROFILE_TEXT:    "The allergens are detected automatically from the ingredients, looking for some known words: always check the recipes yourself.",
+		STR_DIETS:                   "Diets",
+		STR_DIET_VEGAN:              "Vegan",
+		STR_DIET_VEGETARIAN:         "Vegetarian",
+		STR_DIRECTIONS:              "Directions",
+		STR_DISABLE_ACCOUNT:         "Disable account",
+		STR_DISABLE_CALENDAR:        "Disable calendar",
+		STR_DISABLE_TOTP:            "Disable",
+		STR_EDIT:                    "Edit",
+		STR_EDIT_ARTICLE:            "Edit article",
+		STR_EDIT_DAY:                "Edit day",
+		STR_EDIT_MEALS:              "Edit meals",
+		STR_EDIT_ENTRY:              "Edit entry",
+		STR_EDIT_MENU:               "Edit menu",
+		STR_EDIT_RECIPE:             "Edit recipe",
+		STR_EDIT_SECTION:            "Edit section",
+		STR_EFFECTIVE_EXPIRATION:    "Effective expiration",
+		STR_EMAIL:                   "Email",
+		STR_EMAIL_CHANGED:           "Email changed succesfully",
+		STR_EMAIL_CHANGED_EMAIL:     "the email address of your account has been changed. If it wasn't you, contact the support.",
+		STR_EMAIL_LANG:              "Email language",
+		STR_EMAIL_NOT_VERIFIED:      "This address hasn't been verified yet.",
+		STR_EMAIL_PENDING:           "Waiting for confirmation: %%",
+		STR_EMAIL_SENT:              "We've sent you an email: please, check your inbox",
+		STR_EMAIL_SETTINGS:          "Email settings",
+		STR_EMAIL_VERIFICATION_SENT: "We sent a link to the new address: the current one will stay active until you confirm it",
+		STR_EMAIL_VERIFIED:          "Email verified",
+		STR_ENABLE_ACCOUNT:          "Enable account",
+		STR_ENABLE_CALENDAR:         "Enable calendar",
+		STR_ENABLE_TOTP:             "Enable",
+		STR_EVENT_DELETED:           "Account deleted",
+		STR_EVENT_EMAIL_CHANGED:     "Email changed",
+		STR_EVENT_IDENTITY_LINKED:   "Single sign-on linked",
+		STR_EVENT_PASSKEY_ADDED:     "Passkey added",
+		STR_EVENT_PASSKEY_DELETED:   "Passkey deleted",
+		STR_EVENT_PASSWORD_CHANGED:  "Password changed",
+		STR_EVENT_PASSWORD_RESET:    "Password reset",
+		STR_EVENT_SESSION_REVOKED:   "Device signed out",
+		STR_EVENT_SIGNIN:            "Signed in",
+		STR_EVENT_SIGNIN_FAILED:     "Failed sign in attempt",
+		STR_EVENT_TOTP_DISABLED:     "Two-factor authentication disabled",
+		STR_EVENT_TOTP_ENABLED:      "Two-factor authentication enabled",
+		STR_EVENT_USERNAME_CHANGED:  "Username changed",
+		STR_EXPIRATION:              "Expiration date",
+		STR_FATS:                    "Fats",
+		STR_FORGOTTEN_RECIPES:       "Not cooked in a while",
+		STR_FORGOT_PASSWORD:         "Forgot password",
+		STR_FRIDAY:                  "Friday",
+		STR_FROM:                    "From",
+		STR_FROZEN:                  "Frozen on",
+		STR_GENERATE_LINK:           "Generate link",
+		STR_GOOD_MORNING:            "Good morning " + placeholder + ",",
+		STR_GOODBYE:                 "Goodbye",
+		STR_GOODBYE_EMAIL:           "your account has been permanently deleted.",
+		STR_HISTORY:                 "History",
+		STR_IDENTITY_LINKED:         "Your %% account is linked",
+		STR_INFO:                    "Further informations",
+		STR_INFO_CODE:               "CucinAssistant is completely open source; you can see the source on <a href='" + placeholder + "'>Github</a>.",
+		STR_INFO_HISTORY:            "CucinAssistant is a project by Gianluca Parri, ideated in 2023 after its new life as <i>fuorisede</i>.<br>Since then, it's continuosly evolving, even if slowly.",
+		STR_INFO_INTRO:              "<b>CucinAssistant</b> is a simple website, with which you (and your roommates) can easily manage <b>menues</b>, <b>recipes</b>, <b>articles</b> in storage (with quantities and expirations) and a <b>shopping list</b>.",
+		STR_INFO_SUPPORT:            "For doubts, questions or suggestions, you can write an email at <a href='mailto:" + placeholder + "'>" + placeholder + "</a>.",
+		STR_INFO_TUTORIAL:           "There is a <a href='" + placeholder + "'>tutorial</a>, updated to the last version.",
+		STR_INFO_VERSION:            "The current version is the " + placeholder + ".",
+		STR_INGREDIENTS:             "Ingredients",
+		STR_INGREDIENTS_AVAILABLE:   "%% ingredients available",
+		STR_INVALID_DATES:           "Invalid dates",
+		STR_KCAL:                    "Calories (kcal)",
+		STR_LANGUAGE:                "Language",
+		STR_LAST_COOKED:             "Last cooked on " + placeholder,
+		STR_LINK_IDENTITY:           "Link your %% account",
+		STR_LOGOUT:                  "Logout",
+		STR_MEALS:                   "Meals",
+		STR_MENUS:                   "Menus",
+		STR_MISSING_INGREDIENTS:     "Missing: %%",
+		STR_MONDAY:                  "Monday",
+		STR_NAME:                    "Name",
+		STR_NETWORK_ERROR:           "Network error",
+		STR_NEW_DAY:                 "New day",
+		STR_NEW_EMAIL:               "New email",
+		STR_NEW_MENU:                "New menu",
+		STR_NEW_PASSWORD:            "New password",
+		STR_NEW_RECIPE:              "New recipe",
+		STR_NEW_SECTION:             "New section",
+		STR_NEW_SUBSECTION:          "New subsection",
+		STR_NEW_USERNAME:            "New username",
+		STR_NEXT_WEEK:               "Next week",
+		STR_NOREPLY:                 "This email is automatically generated. Please do not reply.",
+		STR_NOTES:                   "Notes",
+		STR_NOT_COUNTED:             "Not counted",
+		STR_NOT_SUITABLE:            "Not suitable for your dietary restrictions",
+		STR_NO_CATEGORY:             "No category",
+		STR_NO_MEALS_SUGGESTED:      "No meal has been filled: there are no empty meals or no suitable recipes",
+		STR_NO_PARENT_SECTION:       "None (top level)",
+		STR_NO_REPEAT_DAYS:          "Days before repeating a recipe",
+		STR_NO_TEMPLATE:             "None",
+		STR_NUTRITION:               "Nutrition",
+		STR_OK:                      "Ok",
+		STR_OLD_PASSWORD:            "Old password",
+		STR_ONLY_SUITABLE:           "Only suitable recipes",
+		STR_OPENED:                  "Opened on",
+		STR_ORDER_CHANGED:           "The order of the articles has changed",
+		STR_ORIGIN_UPDATED:          "The original recipe has been updated.",
+		STR_PAGE_EXPIRED:            "The page has expired, reload it and try again",
+		STR_PAGE_NOT_FOUND:          "Page not found",
+		STR_PARENT_SECTION:          "Contained in",
+		STR_PASSKEYS:                "Passkeys",
+		STR_PASSKEYS_EMPTY:          "You haven't added any passkey yet.",
+		STR_PASSKEYS_TEXT:           "Passkeys let you sign in without a password, using the fingerprint, the face or the screen lock of your device.",
+		STR_PASSKEY_ADDED:           "Passkey added",
+		STR_PASSKEY_CREATED:         "Added on %%",
+		STR_PASSKEY_LAST_USED:       "last used on %%",
+		STR_PASSKEY_NAME:            "Passkey name (like the name of the device)",
+		STR_PASSKEY_UNAVAILABLE:     "Your browser couldn't use a passkey",
+		STR_PASSWORD:                "Password",
+		STR_PASSWORDS_DISABLED:      "Passwords are disabled, sign in with your account",
+		STR_PASSWORD_CHANGED:        "Password changed successfully",
+		STR_PASSWORD_CHANGED_EMAIL:  "recently your password has been changed.",
+		STR_PER_SERVING:             "Per serving",
+		STR_PREVIOUS_WEEK:           "Previous week",
+		STR_PRINT:                   "Print",
+		STR_PROFILE_IS_PUBLIC:       "Your profile is visible at this link:",
+		STR_PROFILE_PUBLIC:          "Make my profile public",
+		STR_PROTEINS:                "Proteins",
+		STR_PUBLIC_PROFILE:          "Public profile",
+		STR_PULL_CONFLICTS:          "Some changes to the original recipe conflict with yours: both versions have been kept, review them before saving.",
+		STR_PULL_UPDATES:            "Pull updates",
+		STR_QUANTITY:                "Quantity",
+		STR_RATING:                  "Rating (optional)",
+		STR_RECIPE_HISTORY:          "Edit history",
+		STR_RECIPE_IS_SHARED:        "This recipe is currently shared at this link:",
+		STR_RECIPE_IS_UNSHARED:      "This recipe is not currently shared",
+		STR_RECIPES:                 "Recipes",
+		STR_RECIPES_EMPTY:           "No recipes found.",
+		STR_RECOVERY_CODES:          "Recovery codes",
+		STR_RECOVERY_CODES_LEFT:     "Recovery codes left: %%",
+		STR_RECOVERY_CODES_TEXT:     "Two-factor authentication is now enabled. Write down these recovery codes and keep them safe: each one can be used once to sign in if you lose access to your authenticator app. They won't be shown again.",
+		STR_REGARDS:                 "Regards",
+		STR_REGENERATE_LINK:         "Regenerate link",
+		STR_REPEAT_PASSWORD:         "Repeat password",
+		STR_RESEND_LINK:             "Send the link again",
+		STR_RESET_PASSWORD:          "Reset password",
+		STR_RESET_PASSWORD_EMAIL:    "to reset your password,",
+		STR_RESET_SHELF_LIVES:       "Restore the defaults",
+		STR_RESTORE:                 "Restore",
+		STR_RESTORE_REVISION_TEXT:   "Are you sure? The current version will be saved in the history, too.",
+		STR_REVISION:                "Revision " + placeholder,
+		STR_REVISIONS_EMPTY:         "This recipe has never been edited.",
+		STR_REVOKE:                  "Revoke",
+		STR_REVOKE_OTHER_SESSIONS:   "Sign out from the other devices",
+		STR_SATURDAY:                "Saturday",
+		STR_SAVE:                    "Save",
+		STR_SAVE_ALL:                "Save all",
+		STR_SAVE_AS_TEMPLATE:        "Save as template",
+		STR_SEARCH:                  "Search",
+		STR_SEARCH_ARTICLES:         "Search articles",
+		STR_SEARCH_EMPTY:            "No articles found.",
+		STR_SEARCH_NO_RESULTS:       "No results found.",
+		STR_SECTION:                 "Section",
+		STR_SECTION_EMPTY:           "This section is empty.",
+		STR_SECTIONS:                "Storage sections",
+		STR_SECURITY_LOG:            "Security log",
+		STR_SECURITY_LOG_EMPTY:      "Nothing has happened yet",
+		STR_SECURITY_LOG_TEXT:       "The recent activity of your account. If you don't recognize something, change your password and sign out from the other devices.",
+		STR_SEED:                    "Seed",
+		STR_SEE_TAGS:                "See tags",
+		STR_SERVINGS:                "Servings",
+		STR_SESSIONS:                "Active sessions",
+		STR_SESSIONS_REVOKED:        "You have been signed out from all the other devices",
+		STR_SESSIONS_TEXT:           "These are the devices you are signed in on. If you don't recognize one, sign out from it and change your password.",
+		STR_SESSION_CURRENT:         "This device",
+		STR_SESSION_LAST_SEEN:       "Last seen: %%",
+		STR_SESSION_REVOKED:         "You have been signed out from that device",
+		STR_SETTINGS:                "Settings",
+		STR_SETTINGS_SAVED:          "Settings saved",
+		STR_SHARE:                   "Share",
+		STR_SHARED_RECIPES:          "Shared recipes",
+		STR_SHARES:                  "Shared links",
+		STR_SHARES_EMPTY:            "You haven't shared any recipe yet",
+		STR_SHARES_INACTIVE:         "Old links",
+		STR_SHARE_EXPIRATION:        "Expires on (optional)",
+		STR_SHARE_EXPIRED:           "This link has expired",
+		STR_SHARE_EXPIRES:           "Expires on " + placeholder,
+		STR_SHARE_MAX_VIEWS:         "Maximum views (optional)",
+		STR_SHARE_REVOKED:           "Revoked on " + placeholder,
+		STR_SHARE_SAVES:             "Saves: " + placeholder,
+		STR_SHARE_VIEWS:             "Views: " + placeholder,
+		STR_SHELF_LIVES:             "Shelf lives",
+		STR_SHELF_LIVES_TEXT:        "How many days an article lasts once opened, or once frozen in the freezer. Leave the field empty if the article should not be kept there.",
+		STR_SHOPPINGLIST:            "Shopping list",
+		STR_SHOPPINGLIST_EMPTY:      "The list is empty.",
+		STR_SIGNIN:                  "Sign in",
+		STR_SIGNIN_PASSKEY:          "Sign in with a passkey",
+		STR_SIGNIN_WITH:             "Sign in with %%",
+		STR_SIGNUP:                  "Sign up",
+		STR_SIGNUP_DONE:             "Succesfully signed up",
+		STR_SLOTS:                   "Meal slots",
+		STR_SLOTS_TEXT:              "Every day of the menu has a meal for each slot.",
+		STR_SLOT_BREAKFAST:          "Breakfast",
+		STR_SLOT_DINNER:             "Dinner",
+		STR_SLOT_LUNCH:              "Lunch",
+		STR_SLOT_SNACK:              "Snack",
+		STR_SSO:                     "Single sign-on",
+		STR_SSO_FAILED:              "The sign in has failed, try again",
+		STR_SSO_LINK_REQUIRED:       "An account with this email already exists: sign in with your password, then link it from the settings.",
+		STR_START_DATE:              "Start date",
+		STR_START_DATE_TEXT:         "If set, the days of the menu follow the calendar, starting from this date.",
+		STR_STATS_HISTORY:           "Trends",
+		STR_STATS_HISTORY_EMPTY:     "The statistics are saved once a day: come back tomorrow.",
+		STR_STORAGE:                 "Storage",
+		STR_STORAGE_EMPTY:           "The storage is empty",
+		STR_STORAGE_FREEZER:         "Freezer",
+		STR_STORAGE_FRIDGE:          "Fridge",
+		STR_STORAGE_PANTRY:          "Pantry",
+		STR_STORAGE_TYPE:            "Storage type",
+		STR_STORAGE_TYPE_INHERITED:  "Same as the containing section",
+		STR_SUGGEST_MEALS:           "Suggest meals",
+		STR_SUGGEST_MEALS_TEXT:      "The empty meals will be filled with your recipes, preferring the ones with more stars and the ones that use articles of the storage that are about to expire.",
+		STR_SUNDAY:                  "Sunday",
+		STR_SUPPORT:                 "Support",
+		STR_TAGS:                    "Tags",
+		STR_TAG_CONSTRAINTS:         "Tags per week",
+		STR_TAG_CONSTRAINTS_TEXT:    "One per line, with the tag followed by the minimum or by the minimum and the maximum (like FISH 2 or MEAT 1-3)",
+		STR_TEMPLATE:                "Template",
+		STR_TEMPLATES:               "Templates",
+		STR_TEMPLATES_EMPTY:         "You have not saved any template yet: you can do it from the edit page of a menu.",
+		STR_TEMPLATE_SAVED:          "Template saved",
+		STR_TEMPLATE_TEXT:           "If you choose a template, the menu will have its slots and its meals, repeated over the days.",
+		STR_THIS_WEEK:               "This week",
+		STR_THURSDAY:                "Thursday",
+		STR_TO:                      "To",
+		STR_TODAY:                   "Today",
+		STR_TOO_MANY_REQUESTS:       "Too many requests, try again later",
+		STR_TOTAL:                   "Total",
+		STR_TOTP:                    "Two-factor authentication",
+		STR_TOTP_CODE:               "Code",
+		STR_TOTP_DISABLED:           "Two-factor authentication disabled",
+		STR_TOTP_DISABLED_EMAIL:     "two-factor authentication has been disabled on your account.",
+		STR_TOTP_ENABLED_EMAIL:      "two-factor authentication has been enabled on your account.",
+		STR_TOTP_IS_ENABLED:         "Two-factor authentication is enabled.",
+		STR_TOTP_SECRET:             "Secret key",
+		STR_TOTP_SETUP_TEXT:         "Open the link below (or add the secret key by hand) in your authenticator app, then enter the code it shows to complete the setup.",
+		STR_TOTP_SIGNIN_TEXT:        "Enter the code shown by your authenticator app, or one of your recovery codes.",
+		STR_TOTP_TEXT:               "With two-factor authentication, to sign in you will need both your password and a code generated by an authenticator app (like Aegis or Google Authenticator).",
+		STR_TUESDAY:                 "Tuesday",
+		STR_TUTORIAL:                "Tutorial",
+		STR_UNKNOWN_LANG:            "Unknown language",
+		STR_UNKNOWN_REQUEST:         "Unknown request",
+		STR_UNMATCHING_PASSWORDS:    "The two passwords do not match",
+		STR_UNSUBSCRIBE:             "To unsubscribe, ",
+		STR_USERS:                   "Users",
+		STR_USERS_EMPTY:             "No user found",
+		STR_USER_CREATED:            "Account created succesfully",
+		STR_USER_DELETED:            "Account deleted succesfully",
+		STR_USERNAME:                "Username",
+		STR_USERNAME_CHANGED:        "Username changed succesfully",
+		STR_USES_EXPIRING:           "About to expire: %%",
+		STR_VERIFICATION_RESENT:     "Verification email sent",
+		STR_VERIFY_EMAIL:            "Email verification",
+		STR_VERIFY_EMAIL_EMAIL:      "to confirm this email address,",
+		STR_VERIFY_EMAIL_TEXT:       "Confirm the email address of your account.",
+		STR_VERSION:                 "Version",
+		STR_WANT_NEWSLETTER:         "I want to receive the newsletter",
+		STR_WEDNESDAY:               "Wednesday",
+		STR_WEEK_EMPTY:              "There are no menus in this week",
+		STR_WELCOME_EMAIL:           "Welcome to CucinAssistant!",
+		STR_WELCOMEBACK:             "Welcome back, " + placeholder + "!",
+		String(database.ERR_ARTICLE_CATEGORY_INVALID):   "Invalid category",
+		String(database.ERR_ARTICLE_DATE_INVALID):       "Invalid opening or freezing date",
+		String(database.ERR_ARTICLE_DUPLICATED):         "An article with this name and expiration already exists",
 		String(database.ERR_ARTICLE_EXPIRATION_INVALID): "Invalid expiration",
 		String(database.ERR_ARTICLE_NOT_FOUND):          "Article not found",
 		String(database.ERR_ARTICLE_QUANTITY_INVALID):   "Invalid quantity",
+		String(database.ERR_CALENDAR_NOT_FOUND):         "Calendar not found",
+		String(database.ERR_COLLECTION_NOT_FOUND):       "Collection not found",
+		String(database.ERR_COOKING_DATE_INVALID):       "Invalid date",
+		String(database.ERR_COOKING_NOT_FOUND):          "Cooking log entry not found",
+		String(database.ERR_COOKING_STARS_INVALID):      "Invalid rating",
+		String(database.ERR_DIET_INVALID):               "Invalid dietary restrictions",
 		String(database.ERR_ENTRY_DUPLICATED):           "Entry already in list",
 		String(database.ERR_ENTRY_NOT_FOUND):            "Entry not found",
 		String(database.ERR_DAY_NOT_FOUND):              "Day not found",
 		String(database.ERR_DAY_NOT_MOVED):              "Cannot move this day",
+		String(database.ERR_FORK_NOT_FOUND):             "This recipe has not been saved from a shared one",
+		String(database.ERR_FORK_ORIGIN_CHANGED):        "The original recipe has been edited in the meantime: review the updates again",
+		String(database.ERR_FORK_ORIGIN_UNAVAILABLE):    "The original recipe is not shared anymore",
+		String(database.ERR_FORK_UP_TO_DATE):            "There are no updates",
+		String(database.ERR_IDENTITY_DUPLICATED):        "This account is already linked to another user",
+		String(database.ERR_IDENTITY_INVALID):           "The provider hasn't given a valid identity",
 		String(database.ERR_MEAL_NOT_FOUND):             "Meal not found",
 		String(database.ERR_MEALS_NEGATIVE):             "Invalid meals number",
 		String(database.ERR_MENU_NOT_FOUND):             "Menu not found",
+		String(database.ERR_MENU_START_INVALID):         "Invalid start date",
+		String(database.ERR_NUTRIENTS_INVALID):          "Invalid nutrients file",
+		String(database.ERR_PASSKEY_DUPLICATED):         "This passkey has already been added",
+		String(database.ERR_PASSKEY_INVALID):            "The passkey could not be verified",
+		String(database.ERR_PASSKEY_NAME_INVALID):       "The passkey name must be between 1 and 64 characters",
+		String(database.ERR_PASSKEY_NOT_FOUND):          "Passkey not found",
+		String(database.ERR_PROFILE_NOT_FOUND):          "Profile not found",
 		String(database.ERR_RECIPE_DUPLICATED):          "A recipe with this name already exists",
 		String(database.ERR_RECIPE_NOT_FOUND):           "Recipe not found",
+		String(database.ERR_REVISION_NOT_FOUND):         "Revision not found",
 		String(database.ERR_SECTION_DUPLICATED):         "A section with this name already exists",
 		String(database.ERR_SECTION_NOT_FOUND):          "Section not found",
+		String(database.ERR_SECTION_PARENT_INVALID):     "Invalid parent section",
+		String(database.ERR_SESSION_NOT_FOUND):          "Session not found",
+		String(database.ERR_SHARE_LIMITS_INVALID):       "Invalid link limits",
+		String(database.ERR_SHARE_NOT_FOUND):            "This recipe is not shared",
+		String(database.ERR_SHELF_LIFE_INVALID):         "Invalid shelf life",
+		String(database.ERR_SLOT_NAME_INVALID):          "Invalid slot name",
+		String(database.ERR_SLOT_NOT_FOUND):             "Slot not found",
+		String(database.ERR_STORAGE_TYPE_INVALID):       "Invalid storage type",
+		String(database.ERR_SUGGEST_INVALID):            "Invalid suggestion settings",
+		String(database.ERR_TAG_NOT_FOUND):              "Tag not found",
+		String(database.ERR_TEMPLATE_DUPLICATED):        "A template with this name already exists",
+		String(database.ERR_TEMPLATE_NOT_FOUND):         "Template not found",
+		String(database.ERR_TOTP_DISABLED):              "Two-factor authentication is not enabled",
+		String(database.ERR_TOTP_ENABLED):               "Two-factor authentication is already enabled",
+		String(database.ERR_TOTP_WRONG_CODE):            "Wrong code",
 		String(database.ERR_UNKNOWN):                    "Unknown error",
+		String(database.ERR_USER_DISABLED):              "This account has been disabled. Please contact support.",
 		String(database.ERR_USER_MAIL_INVALID):          "Invalid email",
 		String(database.ERR_USER_MAIL_UNAVAIL):          "Email not available",
+		String(database.ERR_USER_MAIL_VERIFIED):         "The email has already been verified",
 		String(database.ERR_USER_NAME_TOO_SHORT):        "Invalid username: must be at least 5 characters long",
 		String(database.ERR_USER_NAME_UNAVAIL):          "Username not available",
 		String(database.ERR_USER_PASS_TOO_SHORT):        "Invalid password: must be at least 8 characters long",
+		String(database.ERR_USER_SELF):                  "You can't take this action on your own account",
+		String(database.ERR_USER_TOKEN_EXPIRED):         "The link has expired",
 		String(database.ERR_USER_UNKNOWN):               "Unknown user",
 		String(database.ERR_USER_WRONG_CREDENTIALS):     "Wrong credentials",
 		String(database.ERR_USER_WRONG_TOKEN):           "Something went wrong",
 	},
+
+	Allergens: map[database.Allergen][]string{
+		database.ALLERGEN_CELERY:      {"celery", "celeriac"},
+		database.ALLERGEN_CRUSTACEANS: {"shrimp", "shrimps", "prawn", "prawns", "crab", "crabs", "lobster", "lobsters", "crayfish", "langoustines", "scampi"},
+		database.ALLERGEN_EGGS:        {"egg", "eggs", "mayonnaise", "meringue"},
+		database.ALLERGEN_FISH:        {"fish", "salmon", "tuna", "cod", "anchovy", "anchovies", "sardine", "sardines", "trout", "hake", "sea bass", "swordfish", "mackerel", "haddock"},
+		database.ALLERGEN_GLUTEN:      {"flour", "wheat", "bread", "breadcrumbs", "pasta", "spaghetti", "barley", "rye", "oats", "spelt", "couscous", "semolina", "noodles", "biscuits", "crackers", "seitan", "bulgur"},
+		database.ALLERGEN_HONEY:       {"honey"},
+		database.ALLERGEN_LUPIN:       {"lupin", "lupins"},
+		database.ALLERGEN_MEAT:        {"meat", "beef", "pork", "chicken", "turkey", "lamb", "veal", "ham", "bacon", "sausage", "sausages", "salami", "prosciutto", "pancetta", "duck", "rabbit", "mince"},
+		database.ALLERGEN_MILK:        {"milk", "butter", "cream", "cheese", "yogurt", "yoghurt", "mozzarella", "parmesan", "ricotta", "mascarpone", "ghee", "whey"},
+		database.ALLERGEN_MOLLUSCS:    {"mussel", "mussels", "clam", "clams", "oyster", "oysters", "squid", "octopus", "cuttlefish", "scallop", "scallops", "snails"},
+		database.ALLERGEN_MUSTARD:     {"mustard"},
+		database.ALLERGEN_NUTS:        {"nuts", "almond", "almonds", "hazelnut", "hazelnuts", "walnut", "walnuts", "cashew", "cashews", "pecan", "pecans", "pistachio", "pistachios", "macadamia"},
+		database.ALLERGEN_PEANUTS:     {"peanut", "peanuts"},
+		database.ALLERGEN_SESAME:      {"sesame", "tahini"},
+		database.ALLERGEN_SOY:         {"soy", "soya", "tofu", "edamame", "miso", "tempeh"},
+		database.ALLERGEN_SULPHITES:   {"wine", "sulphites", "sulfites"},
+	},
 }
@@ -5,169 +5,457 @@ import (
 )
 
 var italian *Lang = &Lang{
-	Tag:  "it",
-	Name: "Italiano",
+	Tag:          "it",
+	Name:         "Italiano",
+	SearchConfig: "italian",
 
 	Strings: map[String]string{
-		STR_ADD:                                 "Aggiungi",
-		STR_ADD_ARTICLES:                        "Aggiungi articoli",
-		STR_ADD_DAY:                             "Aggiungi giorno",
-		STR_ADD_MEAL:                            "Aggiungi pasto",
-		STR_ALL_ARTICLES:                        "Vedi tutti",
-		STR_APPEND_ENTRIES:                      "Aggiungi elementi",
-		STR_ARTICLES:                            "Articoli",
-		STR_CANCEL:                              "Annulla",
-		STR_CHANGE_EMAIL:                        "Cambio email",
-		STR_CHANGE_PASSWORD:                     "Cambio password",
-		STR_CHANGE_USERNAME:                     "Cambio nome utente",
-		STR_CLICK_HERE:                          "clicca qui",
-		STR_CLONE:                               "Clona",
-		STR_CODE:                                "Codice sorgente",
-		STR_CONFIRM:                             "Conferma",
-		STR_CURRENT_SEARCH:                      "Ricerca corrente",
-		STR_DAYS:                                "Giorni",
-		STR_DELETE:                              "Elimina",
-		STR_DELETE_CONFIRM_EMAIL:                "per eliminare definitivamente il tuo account,",
-		STR_DELETE_LINK:                         "Elimina link",
-		STR_DELETE_MENU:                         "Elimina menù",
-		STR_DELETE_MENU_TEXT:                    "Sei sicuro di voler cancellare questo menù?",
-		STR_DELETE_RECIPE:                       "Elimina ricetta",
-		STR_DELETE_RECIPE_TEXT:                  "Sei sicuro di voler eliminare questa ricetta?",
-		STR_DELETE_SECTION_TEXT:                 "Sei sicuro? Tutti gli articoli in questa sezione verranno eliminati.",
-		STR_DELETE_SELECTED:                     "Elimina selezionati",
-		STR_DELETE_USER:                         "Elimina account",
-		STR_DELETE_USER_TEXT1:                   "Sei sicuro di voler eliminare il tuo account?",
-		STR_DELETE_USER_TEXT2:                   "Sei DAVVERO sicuro di voler eliminare il tuo account? Questa azione è irreversibile.",
-		STR_DIRECTIONS:                          "Procedimento",
-		STR_EDIT:                                "Modifica",
-		STR_EDIT_ARTICLE:                        "Modifica articolo",
-		STR_EDIT_DAY:                            "Modifica giorno",
-		STR_EDIT_ENTRY:                          "Modifica elemento",
-		STR_EDIT_MEALS:                          "Modifica pasti",
-		STR_EDIT_MENU:                           "Modifica menù",
-		STR_EDIT_RECIPE:                         "Modifica ricetta",
-		STR_EDIT_SECTION:                        "Modifica sezione",
-		STR_EMAIL:                               "Email",
-		STR_EMAIL_CHANGED:                       "Email cambiata con successo",
-		STR_EMAIL_LANG:                          "Lingua email",
-		STR_EMAIL_SENT:                          "Ti abbiamo inviato un'email: controlla la tua casella di posta",
-		STR_EMAIL_SETTINGS:                      "Impostazioni email",
-		STR_EXPIRATION:                          "Scadenza",
-		STR_FORGOT_PASSWORD:                     "Password dimenticata",
-		STR_FROM:                                "Da",
-		STR_GENERATE_LINK:                       "Genera link",
-		STR_GOOD_MORNING:                        "Buongiorno " + placeholder + ",",
-		STR_GOODBYE:                             "Arrivederci",
-		STR_GOODBYE_EMAIL:                       "il tuo account è stato eliminato definitivamente.",
-		STR_HISTORY:                             "Storia",
-		STR_INFO:                                "Maggiori informazioni",
-		STR_INFO_CODE:                           "CucinAssistant è completamente open source; il codice sorgente è disponibile su <a href='" + placeholder + "'>Github</a>.",
-		STR_INFO_HISTORY:                        "CucinAssistant è un progetto sviluppato da Gianluca Parri, nato nel 2023 in seguito alla sua nuova vita da fuorisede universitario.<br>Da quel momento è in continua evoluzione, anche se lentamente.",
-		STR_INFO_INTRO:                          "<b>CucinAssistant</b> è un semplice sito web con il quale è possibile gestire più agevolmente (anche in più persone) <b>menù</b>, <b>ricette</b>, <b>articoli</b> in dispensa (con quantità e scadenze) e una <b>lista della spesa</b>.",
-		STR_INFO_STATS:                          "<p>Vedere la <a href='" + placeholder + "'>pagina apposita</a>.",
-		STR_INFO_SUPPORT:                        "Per ulteriori dubbi, domande o suggerimenti, potete scrivere una mail a <a href=mailto:'" + placeholder + "'>" + placeholder + "</a>.",
-		STR_INFO_TUTORIAL:                       "È disponibile una <a href='" + placeholder + "'>guida all'utilizzo</a> aggiornata all'ultima versione.",
-		STR_INFO_VERSION:                        "La versione attuale è la " + placeholder + ".",
-		STR_INGREDIENTS:                         "Ingredienti",
-		STR_INVALID_DATES:                       "Date non valide",
-		STR_LANGUAGE:                            "Lingua",
-		STR_LOGOUT:                              "Esci",
-		STR_MEALS:                               "Pasti",
-		STR_MEALS_NUMBER:                        "Numero di pasti giornaliero",
-		STR_MENUS:                               "Menù",
-		STR_NAME:                                "Nome",
-		STR_NETWORK_ERROR:                       "Errore di connessione",
-		STR_NEW_DAY:                             "Nuovo giorno",
-		STR_NEW_EMAIL:                           "Nuova email",
-		STR_NEW_MENU:                            "Nuovo menù",
-		STR_NEW_PASSWORD:                        "Nuova password",
-		STR_NEW_RECIPE:                          "Nuova ricetta",
-		STR_NEW_SECTION:                         "Nuova sezione",
-		STR_NEW_USERNAME:                        "Nuovo nome utente",
-		STR_NOREPLY:                             "Questa email è stata generata automaticamente. Si prega di non rispondere.",
-		STR_NOTES:                               "Note",
-		STR_OK:                                  "Va bene",
-		STR_OLD_PASSWORD:                        "Vecchia password",
-		STR_ORDER_CHANGED:                       "L'ordine degli articoli è cambiato",
-		STR_PAGE_NOT_FOUND:                      "Pagina non trovata",
-		STR_PASSWORD:                            "Password",
-		STR_PASSWORD_CHANGED:                    "Password cambiata con successo",
-		STR_PASSWORD_CHANGED_EMAIL:              "la tua password è stata cambiata di recente.",
-		STR_PRINT:                               "Stampa",
-		STR_QUANTITY:                            "Quantità",
-		STR_RECIPE_IS_SHARED:                    "Attualmente la ricetta è condivisa a questo link:",
-		STR_RECIPE_IS_UNSHARED:                  "Attualmente la ricetta non è condivisa.",
-		STR_RECIPES:                             "Ricette",
-		STR_RECIPES_EMPTY:                       "Nessuna ricetta trovata.",
-		STR_REGARDS:                             "Saluti",
-		STR_REGENERATE_LINK:                     "Rigenera link",
-		STR_REPEAT_PASSWORD:                     "Ripeti password",
-		STR_RESET_PASSWORD:                      "Reset password",
-		STR_RESET_PASSWORD_EMAIL:                "per resettare la tua password,",
-		STR_SAVE:                                "Salva",
-		STR_SEARCH_ARTICLES:                     "Ricerca articoli",
-		STR_SEARCH_EMPTY:                        "Nessun articolo trovato",
-		STR_SECTION:                             "Sezione",
-		STR_SECTION_EMPTY:                       "Questa sezione è vuota.",
-		STR_SECTIONS:                            "Sezioni della dispensa",
-		STR_SEE_TAGS:                            "Vedi categorie",
-		STR_SETTINGS:                            "Impostazioni",
-		STR_SETTINGS_SAVED:                      "Impostazioni salvate",
-		STR_SHARE:                               "Condividi",
-		STR_SHOPPINGLIST:                        "Lista della spesa",
-		STR_SHOPPINGLIST_EMPTY:                  "La lista è vuota.",
-		STR_SIGNIN:                              "Accedi",
-		STR_SIGNUP:                              "Registrati",
-		STR_SIGNUP_DONE:                         "Registrazione avvenuta",
-		STR_STARS:                               "Stelle",
-		STR_STATS:                               "Statistiche",
-		STR_STATS_ARTICLES:                      placeholder + " articoli",
-		STR_STATS_ENTRIES:                       placeholder + " elementi",
-		STR_STATS_MENUS:                         placeholder + " menù",
-		STR_STATS_RECIPES:                       placeholder + " ricette",
-		STR_STATS_SECTIONS:                      placeholder + " sezioni",
-		STR_STATS_USERS:                         placeholder + " utenti",
-		STR_STORAGE:                             "Dispensa",
-		STR_STORAGE_EMPTY:                       "La dispensa è vuota",
-		STR_SUPPORT:                             "Supporto",
-		STR_TAGS:                                "Categorie",
-		STR_TO:                                  "A",
-		STR_TUTORIAL:                            "Guida",
-		STR_UNKNOWN_LANG:                        "Lingua sconosciuta",
-		STR_UNKNOWN_REQUEST:                     "Richiesta sconosciuta",
-		STR_UNMATCHING_PASSWORDS:                "Le due password non corrispondono",
-		STR_UNSUBSCRIBE:                         "Per disiscriverti, ",
-		STR_USER_CREATED:                        "Account creato con successo",
-		STR_USER_DELETED:                        "Account eliminato con successo",
-		STR_USERNAME:                            "Nome utente",
-		STR_USERNAME_CHANGED:                    "Nome cambiato con successo",
-		STR_VERSION:                             "Versione",
-		STR_WANT_NEWSLETTER:                     "Voglio ricevere la newsletter",
-		STR_WELCOME_EMAIL:                       "Benvenuto/a su CucinAssistant!",
-		STR_WELCOMEBACK:                         "Bentornato/a, " + placeholder + "!",
-		String(database.ERR_ARTICLE_DUPLICATED): "Esiste già un articolo con stesso nome e scadenza",
+		STR_ACCOUNT_DISABLED:        "Disabilitato",
+		STR_ADAPTED_FROM:            "Adattata da una ricetta di " + placeholder,
+		STR_ADD:                     "Aggiungi",
+		STR_ADD_ARTICLES:            "Aggiungi articoli",
+		STR_ADD_DAY:                 "Aggiungi giorno",
+		STR_ADD_MEAL:                "Aggiungi pasto",
+		STR_ADD_PASSKEY:             "Aggiungi passkey",
+		STR_ADD_SLOT:                "Aggiungi pasto",
+		STR_ADMIN:                   "Console di amministrazione",
+		STR_ADMIN_ROLE:              "Amministratore",
+		STR_ALLERGENS:               "Allergeni",
+		STR_ALLERGEN_CELERY:         "Sedano",
+		STR_ALLERGEN_CRUSTACEANS:    "Crostacei",
+		STR_ALLERGEN_EGGS:           "Uova",
+		STR_ALLERGEN_FISH:           "Pesce",
+		STR_ALLERGEN_GLUTEN:         "Glutine",
+		STR_ALLERGEN_HONEY:          "Miele",
+		STR_ALLERGEN_LUPIN:          "Lupini",
+		STR_ALLERGEN_MEAT:           "Carne",
+		STR_ALLERGEN_MILK:           "Latte",
+		STR_ALLERGEN_MOLLUSCS:       "Molluschi",
+		STR_ALLERGEN_MUSTARD:        "Senape",
+		STR_ALLERGEN_NUTS:           "Frutta a guscio",
+		STR_ALLERGEN_PEANUTS:        "Arachidi",
+		STR_ALLERGEN_SESAME:         "Sesamo",
+		STR_ALLERGEN_SOY:            "Soia",
+		STR_ALLERGEN_SULPHITES:      "Solfiti",
+		STR_ALL_ARTICLES:            "Vedi tutti",
+		STR_ALL_RECIPES:             "Tutte le ricette",
+		STR_APPEND_ENTRIES:          "Aggiungi elementi",
+		STR_ARTICLES:                "Articoli",
+		STR_ARTICLES_COUNT:          "%% articoli",
+		STR_AUDIT_DELETE:            "Ha eliminato l'account di %%",
+		STR_AUDIT_DISABLE:           "Ha disabilitato l'account di %%",
+		STR_AUDIT_ENABLE:            "Ha riabilitato l'account di %%",
+		STR_AUDIT_LOG:               "Registro delle azioni",
+		STR_AUDIT_LOG_EMPTY:         "Non è stata ancora eseguita nessuna azione",
+		STR_AUDIT_RESEND:            "Ha inviato di nuovo l'email di verifica a %%",
+		STR_BIO:                     "Biografia",
+		STR_CALENDAR:                "Calendario",
+		STR_CALENDAR_DISABLED:       "Il calendario è disattivato.",
+		STR_CALENDAR_EXPIRATIONS:    "Scadenze della dispensa",
+		STR_CALENDAR_EXPIRES:        "Scade: %%",
+		STR_CALENDAR_MEALS:          "Pasti (colazione, pranzo e cena ai loro orari)",
+		STR_CALENDAR_MENUS:          "Menù (un evento al giorno)",
+		STR_CALENDAR_TEXT:           "Iscriviti a questi indirizzi dalla tua app di calendario per vedere i tuoi menù e le scadenze della tua dispensa. Chiunque li conosca può vederne il contenuto: se li hai condivisi per errore, generane di nuovi.",
+		STR_CANCEL:                  "Annulla",
+		STR_CARBOHYDRATES:           "Carboidrati",
+		STR_CATEGORY:                "Categoria",
+		STR_CATEGORY_BREAD:          "Pane e prodotti da forno",
+		STR_CATEGORY_CHEESE:         "Formaggi",
+		STR_CATEGORY_DAIRY:          "Latte e latticini",
+		STR_CATEGORY_FISH:           "Pesce",
+		STR_CATEGORY_FRUIT:          "Frutta",
+		STR_CATEGORY_LEFTOVERS:      "Avanzi",
+		STR_CATEGORY_MEAT:           "Carne",
+		STR_CATEGORY_SAUCES:         "Salse e conserve",
+		STR_CATEGORY_VEGETABLES:     "Verdure",
+		STR_CHANGE_EMAIL:            "Cambio email",
+		STR_CHANGE_PASSWORD:         "Cambio password",
+		STR_CHANGE_USERNAME:         "Cambio nome utente",
+		STR_CLICK_HERE:              "clicca qui",
+		STR_CLONE:                   "Clona",
+		STR_CODE:                    "Codice sorgente",
+		STR_COLLECTIONS:             "Raccolte pubbliche",
+		STR_COLLECTIONS_TEXT:        "Ogni tag può essere pubblicato come raccolta: tutte le ricette con quel tag saranno visibili allo stesso link.",
+		STR_COLLECTION_BY:           "Una raccolta di " + placeholder,
+		STR_COLLECTION_SAVED:        "Ricette salvate",
+		STR_COLLECTION_SKIPPED:      "Ricette salvate, tranne quelle con lo stesso nome di una delle tue: %%",
+		STR_COMMENTS:                "Commenti",
+		STR_CONFIRM:                 "Conferma",
+		STR_CONTAINS:                "Contiene",
+		STR_COOK:                    "Chi l'ha cucinata",
+		STR_COOKED:                  "L'ho cucinata",
+		STR_COOKING_LOG:             "Diario di cucina",
+		STR_COOKING_LOG_EMPTY:       "Questa ricetta non è mai stata cucinata",
+		STR_COOK_NOW:                "Cosa posso cucinare?",
+		STR_COOK_NOW_EMPTY:          "Nessuna delle tue ricette usa gli articoli in dispensa",
+		STR_COOK_NOW_TEXT:           "Le tue ricette, ordinate per quanti dei loro ingredienti sono in dispensa. Gli articoli in scadenza contano di più.",
+		STR_CURRENT_SEARCH:          "Ricerca corrente",
+		STR_CUSTOM_SLOTS:            "Altri pasti (uno per riga)",
+		STR_DATE:                    "Data",
+		STR_DAYS:                    "Giorni",
+		STR_DELETE:                  "Elimina",
+		STR_DELETE_ACCOUNT_TEXT:     "Sei sicuro? L'account e tutti i suoi dati verranno eliminati definitivamente.",
+		STR_DELETE_CONFIRM_EMAIL:    "per eliminare definitivamente il tuo account,",
+		STR_DELETE_LINK:             "Elimina link",
+		STR_DELETE_MENU:             "Elimina menù",
+		STR_DELETE_MENU_TEXT:        "Sei sicuro di voler cancellare questo menù?",
+		STR_DELETE_RECIPE:           "Elimina ricetta",
+		STR_DELETE_RECIPE_TEXT:      "Sei sicuro di voler eliminare questa ricetta?",
+		STR_DELETE_SECTION_TEXT:     "Sei sicuro? Tutti gli articoli in questa sezione verranno eliminati.",
+		STR_DELETE_SELECTED:         "Elimina selezionati",
+		STR_DELETE_USER:             "Elimina account",
+		STR_DELETE_USER_TEXT1:       "Sei sicuro di voler eliminare il tuo account?",
+		STR_DELETE_USER_TEXT2:       "Sei DAVVERO sicuro di voler eliminare il tuo account? Questa azione è irreversibile.",
+		STR_DIETARY_PROFILE:         "Restrizioni alimentari",
+		STR_DIETARY_PROFILE_TEXT:    "Gli allergeni vengono rilevati automaticamente dagli ingredienti, cercando alcune parole note: controlla sempre le ricette di persona.",
+		STR_DIETS:                   "Diete",
+		STR_DIET_VEGAN:              "Vegana",
+		STR_DIET_VEGETARIAN:         "Vegetariana",
+		STR_DIRECTIONS:              "Procedimento",
+		STR_DISABLE_ACCOUNT:         "Disabilita account",
+		STR_DISABLE_CALENDAR:        "Disattiva calendario",
+		STR_DISABLE_TOTP:            "Disattiva",
+		STR_EDIT:                    "Modifica",
+		STR_EDIT_ARTICLE:            "Modifica articolo",
+		STR_EDIT_DAY:                "Modifica giorno",
+		STR_EDIT_ENTRY:              "Modifica elemento",
+		STR_EDIT_MEALS:              "Modifica pasti",
+		STR_EDIT_MENU:               "Modifica menù",
+		STR_EDIT_RECIPE:             "Modifica ricetta",
+		STR_EDIT_SECTION:            "Modifica sezione",
+		STR_EFFECTIVE_EXPIRATION:    "Scadenza effettiva",
+		STR_EMAIL:                   "Email",
+		STR_EMAIL_CHANGED:           "Email cambiata con successo",
+		STR_EMAIL_CHANGED_EMAIL:     "l'indirizzo email del tuo account è stato cambiato. Se non sei stato tu, contatta l'assistenza.",
+		STR_EMAIL_LANG:              "Lingua email",
+		STR_EMAIL_NOT_VERIFIED:      "Questo indirizzo non è ancora stato verificato.",
+		STR_EMAIL_PENDING:           "In attesa di conferma: %%",
+		STR_EMAIL_SENT:              "Ti abbiamo inviato un'email: controlla la tua casella di posta",
+		STR_EMAIL_SETTINGS:          "Impostazioni email",
+		STR_EMAIL_VERIFICATION_SENT: "Abbiamo inviato un link al nuovo indirizzo: quello attuale resterà attivo finché non lo confermi",
+		STR_EMAIL_VERIFIED:          "Email verificata",
+		STR_ENABLE_ACCOUNT:          "Riabilita account",
+		STR_ENABLE_CALENDAR:         "Attiva calendario",
+		STR_ENABLE_TOTP:             "Attiva",
+		STR_EVENT_DELETED:           "Account eliminato",
+		STR_EVENT_EMAIL_CHANGED:     "Email cambiata",
+		STR_EVENT_IDENTITY_LINKED:   "Accesso unico collegato",
+		STR_EVENT_PASSKEY_ADDED:     "Passkey aggiunta",
+		STR_EVENT_PASSKEY_DELETED:   "Passkey eliminata",
+		STR_EVENT_PASSWORD_CHANGED:  "Password cambiata",
+		STR_EVENT_PASSWORD_RESET:    "Password reimpostata",
+		STR_EVENT_SESSION_REVOKED:   "Dispositivo disconnesso",
+		STR_EVENT_SIGNIN:            "Accesso effettuato",
+		STR_EVENT_SIGNIN_FAILED:     "Tentativo di accesso fallito",
+		STR_EVENT_TOTP_DISABLED:     "Autenticazione a due fattori disattivata",
+		STR_EVENT_TOTP_ENABLED:      "Autenticazione a due fattori attivata",
+		STR_EVENT_USERNAME_CHANGED:  "Nome cambiato",
+		STR_EXPIRATION:              "Scadenza",
+		STR_FATS:                    "Grassi",
+		STR_FORGOTTEN_RECIPES:       "Non cucinate da un po'",
+		STR_FORGOT_PASSWORD:         "Password dimenticata",
+		STR_FRIDAY:                  "Venerdì",
+		STR_FROM:                    "Da",
+		STR_FROZEN:                  "Congelato il",
+		STR_GENERATE_LINK:           "Genera link",
+		STR_GOOD_MORNING:            "Buongiorno " + placeholder + ",",
+		STR_GOODBYE:                 "Arrivederci",
+		STR_GOODBYE_EMAIL:           "il tuo account è stato eliminato definitivamente.",
+		STR_HISTORY:                 "Storia",
+		STR_IDENTITY_LINKED:         "Il tuo account %% è collegato",
+		STR_INFO:                    "Maggiori informazioni",
+		STR_INFO_CODE:               "CucinAssistant è completamente open source; il codice sorgente è disponibile su <a href='" + placeholder + "'>Github</a>.",
+		STR_INFO_HISTORY:            "CucinAssistant è un progetto sviluppato da Gianluca Parri, nato nel 2023 in seguito alla sua nuova vita da fuorisede universitario.<br>Da quel momento è in continua evoluzione, anche se lentamente.",
+		STR_INFO_INTRO:              "<b>CucinAssistant</b> è un semplice sito web con il quale è possibile gestire più agevolmente (anche in più persone) <b>menù</b>, <b>ricette</b>, <b>articoli</b> in dispensa (con quantità e scadenze) e una <b>lista della spesa</b>.",
+		STR_INFO_SUPPORT:            "Per ulteriori dubbi, domande o suggerimenti, potete scrivere una mail a <a href=mailto:'" + placeholder + "'>" + placeholder + "</a>.",
+		STR_INFO_TUTORIAL:           "È disponibile una <a href='" + placeholder + "'>guida all'utilizzo</a> aggiornata all'ultima versione.",
+		STR_INFO_VERSION:            "La versione attuale è la " + placeholder + ".",
+		STR_INGREDIENTS:             "Ingredienti",
+		STR_INGREDIENTS_AVAILABLE:   "%% ingredienti disponibili",
+		STR_INVALID_DATES:           "Date non valide",
+		STR_KCAL:                    "Calorie (kcal)",
+		STR_LANGUAGE:                "Lingua",
+		STR_LAST_COOKED:             "Cucinata l'ultima volta il " + placeholder,
+		STR_LINK_IDENTITY:           "Collega il tuo account %%",
+		STR_LOGOUT:                  "Esci",
+		STR_MEALS:                   "Pasti",
+		STR_MENUS:                   "Menù",
+		STR_MISSING_INGREDIENTS:     "Mancano: %%",
+		STR_MONDAY:                  "Lunedì",
+		STR_NAME:                    "Nome",
+		STR_NETWORK_ERROR:           "Errore di connessione",
+		STR_NEW_DAY:                 "Nuovo giorno",
+		STR_NEW_EMAIL:               "Nuova email",
+		STR_NEW_MENU:                "Nuovo menù",
+		STR_NEW_PASSWORD:            "Nuova password",
+		STR_NEW_RECIPE:              "Nuova ricetta",
+		STR_NEW_SECTION:             "Nuova sezione",
+		STR_NEW_SUBSECTION:          "Nuova sottosezione",
+		STR_NEW_USERNAME:            "Nuovo nome utente",
+		STR_NEXT_WEEK:               "Settimana successiva",
+		STR_NOREPLY:                 "Questa email è stata generata automaticamente. Si prega di non rispondere.",
+		STR_NOTES:                   "Note",
+		STR_NOT_COUNTED:             "Non conteggiati",
+		STR_NOT_SUITABLE:            "Non adatta alle tue restrizioni alimentari",
+		STR_NO_CATEGORY:             "Nessuna categoria",
+		STR_NO_MEALS_SUGGESTED:      "Nessun pasto è stato riempito: non ci sono pasti vuoti o ricette adatte",
+		STR_NO_PARENT_SECTION:       "Nessuna (livello principale)",
+		STR_NO_REPEAT_DAYS:          "Giorni prima di ripetere una ricetta",
+		STR_NO_TEMPLATE:             "Nessuno",
+		STR_NUTRITION:               "Valori nutrizionali",
+		STR_OK:                      "Va bene",
+		STR_OLD_PASSWORD:            "Vecchia password",
+		STR_ONLY_SUITABLE:           "Solo ricette adatte",
+		STR_OPENED:                  "Aperto il",
+		STR_ORDER_CHANGED:           "L'ordine degli articoli è cambiato",
+		STR_ORIGIN_UPDATED:          "La ricetta originale è stata aggiornata.",
+		STR_PAGE_EXPIRED:            "La pagina è scaduta, ricaricala e riprova",
+		STR_PAGE_NOT_FOUND:          "Pagina non trovata",
+		STR_PARENT_SECTION:          "Contenuta in",
+		STR_PASSKEYS:                "Passkey",
+		STR_PASSKEYS_EMPTY:          "Non hai ancora aggiunto nessuna passkey.",
+		STR_PASSKEYS_TEXT:           "Le passkey ti permettono di accedere senza password, usando l'impronta, il volto o il blocco schermo del tuo dispositivo.",
+		STR_PASSKEY_ADDED:           "Passkey aggiunta",
+		STR_PASSKEY_CREATED:         "Aggiunta il %%",
+		STR_PASSKEY_LAST_USED:       "ultimo uso il %%",
+		STR_PASSKEY_NAME:            "Nome della passkey (come il nome del dispositivo)",
+		STR_PASSKEY_UNAVAILABLE:     "Il tuo browser non è riuscito a usare una passkey",
+		STR_PASSWORD:                "Password",
+		STR_PASSWORDS_DISABLED:      "Le password sono disabilitate, accedi con il tuo account",
+		STR_PASSWORD_CHANGED:        "Password cambiata con successo",
+		STR_PASSWORD_CHANGED_EMAIL:  "la tua password è stata cambiata di recente.",
+		STR_PER_SERVING:             "Per porzione",
+		STR_PREVIOUS_WEEK:           "Settimana precedente",
+		STR_PRINT:                   "Stampa",
+		STR_PROFILE_IS_PUBLIC:       "Il tuo profilo è visibile a questo link:",
+		STR_PROFILE_PUBLIC:          "Rendi pubblico il mio profilo",
+		STR_PROTEINS:                "Proteine",
+		STR_PUBLIC_PROFILE:          "Profilo pubblico",
+		STR_PULL_CONFLICTS:          "Alcune modifiche alla ricetta originale sono in conflitto con le tue: sono state mantenute entrambe le versioni, controllale prima di salvare.",
+		STR_PULL_UPDATES:            "Importa aggiornamenti",
+		STR_QUANTITY:                "Quantità",
+		STR_RATING:                  "Valutazione (opzionale)",
+		STR_RECIPE_HISTORY:          "Cronologia modifiche",
+		STR_RECIPE_IS_SHARED:        "Attualmente la ricetta è condivisa a questo link:",
+		STR_RECIPE_IS_UNSHARED:      "Attualmente la ricetta non è condivisa.",
+		STR_RECIPES:                 "Ricette",
+		STR_RECIPES_EMPTY:           "Nessuna ricetta trovata.",
+		STR_RECOVERY_CODES:          "Codici di recupero",
+		STR_RECOVERY_CODES_LEFT:     "Codici di recupero rimasti: %%",
+		STR_RECOVERY_CODES_TEXT:     "L'autenticazione a due fattori è ora attiva. Annota questi codici di recupero e conservali al sicuro: ognuno può essere usato una volta per accedere se perdi l'accesso alla tua app di autenticazione. Non verranno più mostrati.",
+		STR_REGARDS:                 "Saluti",
+		STR_REGENERATE_LINK:         "Rigenera link",
+		STR_REPEAT_PASSWORD:         "Ripeti password",
+		STR_RESEND_LINK:             "Invia di nuovo il link",
+		STR_RESET_PASSWORD:          "Reset password",
+		STR_RESET_PASSWORD_EMAIL:    "per resettare la tua password,",
+		STR_RESET_SHELF_LIVES:       "Ripristina i valori predefiniti",
+		STR_RESTORE:                 "Ripristina",
+		STR_RESTORE_REVISION_TEXT:   "Sei sicuro? Anche la versione corrente verrà salvata nella cronologia.",
+		STR_REVISION:                "Revisione " + placeholder,
+		STR_REVISIONS_EMPTY:         "Questa ricetta non è mai stata modificata.",
+		STR_REVOKE:                  "Revoca",
+		STR_REVOKE_OTHER_SESSIONS:   "Esci dagli altri dispositivi",
+		STR_SATURDAY:                "Sabato",
+		STR_SAVE:                    "Salva",
+		STR_SAVE_ALL:                "Salva tutte",
+		STR_SAVE_AS_TEMPLATE:        "Salva come modello",
+		STR_SEARCH:                  "Cerca",
+		STR_SEARCH_ARTICLES:         "Ricerca articoli",
+		STR_SEARCH_EMPTY:            "Nessun articolo trovato",
+		STR_SEARCH_NO_RESULTS:       "Nessun risultato trovato.",
+		STR_SECTION:                 "Sezione",
+		STR_SECTION_EMPTY:           "Questa sezione è vuota.",
+		STR_SECTIONS:                "Sezioni della dispensa",
+		STR_SECURITY_LOG:            "Registro di sicurezza",
+		STR_SECURITY_LOG_EMPTY:      "Non è ancora successo niente",
+		STR_SECURITY_LOG_TEXT:       "L'attività recente del tuo account. Se non riconosci qualcosa, cambia la password ed esci dagli altri dispositivi.",
+		STR_SEED:                    "Seme",
+		STR_SEE_TAGS:                "Vedi categorie",
+		STR_SERVINGS:                "Porzioni",
+		STR_SESSIONS:                "Sessioni attive",
+		STR_SESSIONS_REVOKED:        "Sei uscito da tutti gli altri dispositivi",
+		STR_SESSIONS_TEXT:           "Questi sono i dispositivi su cui hai effettuato l'accesso. Se non ne riconosci uno, esci da esso e cambia la password.",
+		STR_SESSION_CURRENT:         "Questo dispositivo",
+		STR_SESSION_LAST_SEEN:       "Ultimo accesso: %%",
+		STR_SESSION_REVOKED:         "Sei uscito da quel dispositivo",
+		STR_SETTINGS:                "Impostazioni",
+		STR_SETTINGS_SAVED:          "Impostazioni salvate",
+		STR_SHARE:                   "Condividi",
+		STR_SHARED_RECIPES:          "Ricette condivise",
+		STR_SHARES:                  "Link condivisi",
+		STR_SHARES_EMPTY:            "Non hai ancora condiviso nessuna ricetta",
+		STR_SHARES_INACTIVE:         "Vecchi link",
+		STR_SHARE_EXPIRATION:        "Scade il (opzionale)",
+		STR_SHARE_EXPIRED:           "Questo link è scaduto",
+		STR_SHARE_EXPIRES:           "Scade il " + placeholder,
+		STR_SHARE_MAX_VIEWS:         "Visualizzazioni massime (opzionale)",
+		STR_SHARE_REVOKED:           "Revocato il " + placeholder,
+		STR_SHARE_SAVES:             "Salvataggi: " + placeholder,
+		STR_SHARE_VIEWS:             "Visualizzazioni: " + placeholder,
+		STR_SHELF_LIVES:             "Durate di conservazione",
+		STR_SHELF_LIVES_TEXT:        "Quanti giorni dura un articolo una volta aperto, o una volta congelato nel congelatore. Lascia il campo vuoto se l'articolo non va conservato lì.",
+		STR_SHOPPINGLIST:            "Lista della spesa",
+		STR_SHOPPINGLIST_EMPTY:      "La lista è vuota.",
+		STR_SIGNIN:                  "Accedi",
+		STR_SIGNIN_PASSKEY:          "Accedi con una passkey",
+		STR_SIGNIN_WITH:             "Accedi con %%",
+		STR_SIGNUP:                  "Registrati",
+		STR_SIGNUP_DONE:             "Registrazione avvenuta",
+		STR_SLOTS:                   "Pasti della giornata",
+		STR_SLOTS_TEXT:              "Ogni giorno del menù ha un pasto per ciascuno di questi.",
+		STR_SLOT_BREAKFAST:          "Colazione",
+		STR_SLOT_DINNER:             "Cena",
+		STR_SLOT_LUNCH:              "Pranzo",
+		STR_SLOT_SNACK:              "Merenda",
+		STR_SSO:                     "Accesso unico",
+		STR_SSO_FAILED:              "L'accesso non è riuscito, riprova",
+		STR_SSO_LINK_REQUIRED:       "Esiste già un account con questa email: accedi con la password, poi collegalo dalle impostazioni.",
+		STR_START_DATE:              "Data di inizio",
+		STR_START_DATE_TEXT:         "Se impostata, i giorni del menù seguono il calendario, a partire da questa data.",
+		STR_STATS_HISTORY:           "Andamento",
+		STR_STATS_HISTORY_EMPTY:     "Le statistiche vengono salvate una volta al giorno: torna domani.",
+		STR_STORAGE:                 "Dispensa",
+		STR_STORAGE_EMPTY:           "La dispensa è vuota",
+		STR_STORAGE_FREEZER:         "Congelatore",
+		STR_STORAGE_FRIDGE:          "Frigorifero",
+		STR_STORAGE_PANTRY:          "Dispensa",
+		STR_STORAGE_TYPE:            "Tipo di conservazione",
+		STR_STORAGE_TYPE_INHERITED:  "Come la sezione che la contiene",
+		STR_SUGGEST_MEALS:           "Suggerisci pasti",
+		STR_SUGGEST_MEALS_TEXT:      "I pasti vuoti saranno riempiti con le tue ricette, preferendo quelle con più stelle e quelle che usano articoli della dispensa in scadenza.",
+		STR_SUNDAY:                  "Domenica",
+		STR_SUPPORT:                 "Supporto",
+		STR_TAGS:                    "Categorie",
+		STR_TAG_CONSTRAINTS:         "Tag per settimana",
+		STR_TAG_CONSTRAINTS_TEXT:    "Uno per riga, con il tag seguito dal minimo o dal minimo e dal massimo (come PESCE 2 o CARNE 1-3)",
+		STR_TEMPLATE:                "Modello",
+		STR_TEMPLATES:               "Modelli",
+		STR_TEMPLATES_EMPTY:         "Non hai ancora salvato alcun modello: puoi farlo dalla pagina di modifica di un menù.",
+		STR_TEMPLATE_SAVED:          "Modello salvato",
+		STR_TEMPLATE_TEXT:           "Se scegli un modello, il menù avrà i suoi pasti, ripetuti nei vari giorni.",
+		STR_THIS_WEEK:               "Questa settimana",
+		STR_THURSDAY:                "Giovedì",
+		STR_TO:                      "A",
+		STR_TODAY:                   "Oggi",
+		STR_TOO_MANY_REQUESTS:       "Troppe richieste, riprova più tardi",
+		STR_TOTAL:                   "Totale",
+		STR_TOTP:                    "Autenticazione a due fattori",
+		STR_TOTP_CODE:               "Codice",
+		STR_TOTP_DISABLED:           "Autenticazione a due fattori disattivata",
+		STR_TOTP_DISABLED_EMAIL:     "l'autenticazione a due fattori è stata disattivata sul tuo account.",
+		STR_TOTP_ENABLED_EMAIL:      "l'autenticazione a due fattori è stata attivata sul tuo account.",
+		STR_TOTP_IS_ENABLED:         "L'autenticazione a due fattori è attiva.",
+		STR_TOTP_SECRET:             "Chiave segreta",
+		STR_TOTP_SETUP_TEXT:         "Apri il link qui sotto (o inserisci a mano la chiave segreta) nella tua app di autenticazione, poi inserisci il codice che mostra per completare l'attivazione.",
+		STR_TOTP_SIGNIN_TEXT:        "Inserisci il codice mostrato dalla tua app di autenticazione, o uno dei tuoi codici di recupero.",
+		STR_TOTP_TEXT:               "Con l'autenticazione a due fattori, per accedere serviranno sia la password che un codice generato da un'app di autenticazione (come Aegis o Google Authenticator).",
+		STR_TUESDAY:                 "Martedì",
+		STR_TUTORIAL:                "Guida",
+		STR_UNKNOWN_LANG:            "Lingua sconosciuta",
+		STR_UNKNOWN_REQUEST:         "Richiesta sconosciuta",
+		STR_UNMATCHING_PASSWORDS:    "Le due password non corrispondono",
+		STR_UNSUBSCRIBE:             "Per disiscriverti, ",
+		STR_USERS:                   "Utenti",
+		STR_USERS_EMPTY:             "Nessun utente trovato",
+		STR_USER_CREATED:            "Account creato con successo",
+		STR_USER_DELETED:            "Account eliminato con successo",
+		STR_USERNAME:                "Nome utente",
+		STR_USERNAME_CHANGED:        "Nome cambiato con successo",
+		STR_USES_EXPIRING:           "In scadenza: %%",
+		STR_VERIFICATION_RESENT:     "Email di verifica inviata",
+		STR_VERIFY_EMAIL:            "Verifica email",
+		STR_VERIFY_EMAIL_EMAIL:      "per confermare questo indirizzo email,",
+		STR_VERIFY_EMAIL_TEXT:       "Conferma l'indirizzo email del tuo account.",
+		STR_VERSION:                 "Versione",
+		STR_WANT_NEWSLETTER:         "Voglio ricevere la newsletter",
+		STR_WEDNESDAY:               "Mercoledì",
+		STR_WEEK_EMPTY:              "Non ci sono menù in questa settimana",
+		STR_WELCOME_EMAIL:           "Benvenuto/a su CucinAssistant!",
+		STR_WELCOMEBACK:             "Bentornato/a, " + placeholder + "!",
+		String(database.ERR_ARTICLE_CATEGORY_INVALID):   "Categoria non valida",
+		String(database.ERR_ARTICLE_DATE_INVALID):       "Data di apertura o congelamento non valida",
+		String(database.ERR_ARTICLE_DUPLICATED):         "Esiste già un articolo con stesso nome e scadenza",
 		String(database.ERR_ARTICLE_EXPIRATION_INVALID): "Scadenza non valida",
 		String(database.ERR_ARTICLE_NOT_FOUND):          "Articolo non trovata",
 		String(database.ERR_ARTICLE_QUANTITY_INVALID):   "Quantità non valida",
+		String(database.ERR_CALENDAR_NOT_FOUND):         "Calendario non trovato",
+		String(database.ERR_COLLECTION_NOT_FOUND):       "Raccolta non trovata",
+		String(database.ERR_COOKING_DATE_INVALID):       "Data non valida",
+		String(database.ERR_COOKING_NOT_FOUND):          "Voce del diario non trovata",
+		String(database.ERR_COOKING_STARS_INVALID):      "Valutazione non valida",
+		String(database.ERR_DIET_INVALID):               "Restrizioni alimentari non valide",
 		String(database.ERR_ENTRY_DUPLICATED):           "Elemento già in lista",
 		String(database.ERR_ENTRY_NOT_FOUND):            "Elemento non trovato",
 		String(database.ERR_DAY_NOT_FOUND):              "Giorno non trovato",
 		String(database.ERR_DAY_NOT_MOVED):              "Impossibile spostare questo giorno",
+		String(database.ERR_FORK_NOT_FOUND):             "Questa ricetta non è stata salvata da una condivisa",
+		String(database.ERR_FORK_ORIGIN_CHANGED):        "La ricetta originale è stata modificata nel frattempo: rivedi di nuovo gli aggiornamenti",
+		String(database.ERR_FORK_ORIGIN_UNAVAILABLE):    "La ricetta originale non è più condivisa",
+		String(database.ERR_FORK_UP_TO_DATE):            "Non ci sono aggiornamenti",
+		String(database.ERR_IDENTITY_DUPLICATED):        "Questo account è già collegato a un altro utente",
+		String(database.ERR_IDENTITY_INVALID):           "Il provider non ha fornito un'identità valida",
 		String(database.ERR_MEAL_NOT_FOUND):             "Pasto non trovato",
 		String(database.ERR_MEALS_NEGATIVE):             "Numero di pasti non valido",
 		String(database.ERR_MENU_NOT_FOUND):             "Menù non trovato",
+		String(database.ERR_MENU_START_INVALID):         "Data di inizio non valida",
+		String(database.ERR_NUTRIENTS_INVALID):          "File dei nutrienti non valido",
+		String(database.ERR_PASSKEY_DUPLICATED):         "Questa passkey è già stata aggiunta",
+		String(database.ERR_PASSKEY_INVALID):            "Non è stato possibile verificare la passkey",
+		String(database.ERR_PASSKEY_NAME_INVALID):       "Il nome della passkey deve essere lungo tra 1 e 64 caratteri",
+		String(database.ERR_PASSKEY_NOT_FOUND):          "Passkey non trovata",
+		String(database.ERR_PROFILE_NOT_FOUND):          "Profilo non trovato",
 		String(database.ERR_RECIPE_DUPLICATED):          "Esiste già una ricetta con questo nome",
 		String(database.ERR_RECIPE_NOT_FOUND):           "Ricetta non trovata",
+		String(database.ERR_REVISION_NOT_FOUND):         "Revisione non trovata",
 		String(database.ERR_SECTION_DUPLICATED):         "Esiste già una sezione con lo stesso nome",
 		String(database.ERR_SECTION_NOT_FOUND):          "Sezione non trovata",
+		String(database.ERR_SECTION_PARENT_INVALID):     "Sezione superiore non valida",
+		String(database.ERR_SESSION_NOT_FOUND):          "Sessione non trovata",
+		String(database.ERR_SHARE_LIMITS_INVALID):       "Limiti del link non validi",
+		String(database.ERR_SHARE_NOT_FOUND):            "Questa ricetta non è condivisa",
+		String(database.ERR_SHELF_LIFE_INVALID):         "Durata di conservazione non valida",
+		String(database.ERR_SLOT_NAME_INVALID):          "Nome del pasto non valido",
+		String(database.ERR_SLOT_NOT_FOUND):             "Pasto non trovato",
+		String(database.ERR_STORAGE_TYPE_INVALID):       "Tipo di conservazione non valido",
+		String(database.ERR_SUGGEST_INVALID):            "Impostazioni dei suggerimenti non valide",
+		String(database.ERR_TAG_NOT_FOUND):              "Tag non trovato",
+		String(database.ERR_TEMPLATE_DUPLICATED):        "Esiste già un modello con questo nome",
+		String(database.ERR_TEMPLATE_NOT_FOUND):         "Modello non trovato",
+		String(database.ERR_TOTP_DISABLED):              "L'autenticazione a due fattori non è attiva",
+		String(database.ERR_TOTP_ENABLED):               "L'autenticazione a due fattori è già attiva",
+		String(database.ERR_TOTP_WRONG_CODE):            "Codice errato",
 		String(database.ERR_UNKNOWN):                    "Errore sconosciuto",
+		String(database.ERR_USER_DISABLED):              "Questo account è stato disabilitato. Contatta l'assistenza.",
 		String(database.ERR_USER_MAIL_INVALID):          "Email non valida",
 		String(database.ERR_USER_MAIL_UNAVAIL):          "Email non disponibile",
+		String(database.ERR_USER_MAIL_VERIFIED):         "L'email è già stata verificata",
 		String(database.ERR_USER_NAME_TOO_SHORT):        "Nome utente non valido: lunghezza minima 5 caratteri",
 		String(database.ERR_USER_NAME_UNAVAIL):          "Nome utente non disponibile",
 		String(database.ERR_USER_PASS_TOO_SHORT):        "Password non valida: lunghezza minima 8 caratteri",
+		String(database.ERR_USER_SELF):                  "Non puoi eseguire questa azione sul tuo account",
+		String(database.ERR_USER_TOKEN_EXPIRED):         "Il link è scaduto",
 		String(database.ERR_USER_UNKNOWN):               "Utente sconosciuto",
 		String(database.ERR_USER_WRONG_CREDENTIALS):     "Credenziali non valide",
 		String(database.ERR_USER_WRONG_TOKEN):           "Qualcosa è andato storto",
 	},
+
+	Allergens: map[database.Allergen][]string{
+		database.ALLERGEN_CELERY:      {"sedano"},
+		database.ALLERGEN_CRUSTACEANS: {"gambero", "gamberi", "gamberetti", "gamberoni", "scampi", "aragosta", "astice", "granchio", "granchi", "mazzancolle"},
+		database.ALLERGEN_EGGS:        {"uovo", "uova", "tuorlo", "tuorli", "albume", "albumi", "maionese"},
+		database.ALLERGEN_FISH:        {"pesce", "salmone", "tonno", "merluzzo", "baccalà", "acciuga", "acciughe", "alici", "sardina", "sardine", "trota", "nasello", "branzino", "spigola", "sgombro"},
+		database.ALLERGEN_GLUTEN:      {"farina", "frumento", "grano", "pane", "pangrattato", "pasta", "spaghetti", "orzo", "segale", "avena", "farro", "cuscus", "couscous", "semola", "gnocchi", "biscotti", "seitan", "bulgur"},
+		database.ALLERGEN_HONEY:       {"miele"},
+		database.ALLERGEN_LUPIN:       {"lupino", "lupini"},
+		database.ALLERGEN_MEAT:        {"carne", "manzo", "maiale", "pollo", "tacchino", "agnello", "vitello", "prosciutto", "pancetta", "salsiccia", "salsicce", "salame", "speck", "guanciale", "anatra", "coniglio", "macinato", "bresaola", "mortadella"},
+		database.ALLERGEN_MILK:        {"latte", "burro", "panna", "formaggio", "formaggi", "yogurt", "mozzarella", "parmigiano", "grana", "ricotta", "mascarpone", "pecorino", "stracchino", "besciamella"},
+		database.ALLERGEN_MOLLUSCS:    {"cozza", "cozze", "vongola", "vongole", "ostrica", "ostriche", "calamaro", "calamari", "polpo", "seppia", "seppie", "capesante", "lumache"},
+		database.ALLERGEN_MUSTARD:     {"senape"},
+		database.ALLERGEN_NUTS:        {"mandorla", "mandorle", "nocciola", "nocciole", "noci", "gherigli", "anacardi", "pistacchio", "pistacchi", "macadamia"},
+		database.ALLERGEN_PEANUTS:     {"arachide", "arachidi", "noccioline"},
+		database.ALLERGEN_SESAME:      {"sesamo", "tahina"},
+		database.ALLERGEN_SOY:         {"soia", "tofu", "edamame", "miso", "tempeh"},
+		database.ALLERGEN_SULPHITES:   {"vino", "solfiti"},
+	},
 }
@@ -21,8 +21,16 @@ type Lang struct {
 	// Name is the language name, in that language
 	Name string
 
+	// SearchConfig is the PostgreSQL text search configuration used
+	// to stem the words when searching
+	SearchConfig string
+
 	// Strings contains the translations for the Strings
 	Strings map[String]string
+
+	// Allergens contains, for every allergen, some words that
+	// (if found in the ingredients) suggest its presence
+	Allergens map[database.Allergen][]string
 }
 
 // Get returns the language with that tag.
@@ -1,6 +1,7 @@
 package langs
 
 import (
+	"slices"
 	"testing"
 
 	"cucinassistant/database"
@@ -30,4 +31,48 @@ func (l *Lang) test(t *testing.T) {
 			t.Errorf("Missing <%s> in language <%s>", s.String(), l.Tag)
 		}
 	}
+
+	// Checks the allergens keywords
+	allergens := slices.Clone(database.Allergens)
+	for _, diet := range database.Diets {
+		allergens = append(allergens, diet...)
+	}
+	for _, a := range allergens {
+		if len(l.Allergens[a]) == 0 {
+			t.Errorf("Missing keywords for allergen <%s> in language <%s>", a, l.Tag)
+		} else if AllergenName(a) == 0 {
+			t.Errorf("Missing name for allergen <%s>", a)
+		}
+	}
+}
+
+// TestDetectAllergens checks that the allergens are found only
+// with the keywords of the given language
+func TestDetectAllergens(t *testing.T) {
+	cases := []struct {
+		Message string
+		Lang    string
+		Text    string
+
+		Expected []database.Allergen
+	}{
+		{
+			"(english)", "en", "200 g of flour\n2 Eggs\n- some Parmesan cheese\nnutmeg",
+			[]database.Allergen{database.ALLERGEN_EGGS, database.ALLERGEN_GLUTEN, database.ALLERGEN_MILK},
+		},
+		{
+			"(italian)", "it", "200 g di farina\n2 Uova\n- un po' di parmigiano\nnoce moscata",
+			[]database.Allergen{database.ALLERGEN_EGGS, database.ALLERGEN_GLUTEN, database.ALLERGEN_MILK},
+		},
+		{
+			"found keywords of other language", "en", "200 g di farina\n2 uova",
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		if got := DetectAllergens(tc.Lang, tc.Text); !slices.Equal(got, tc.Expected) {
+			t.Errorf("%s: expected allergens <%v>, got <%v>", tc.Message, tc.Expected, got)
+		}
+	}
 }
@@ -15,24 +15,91 @@ const (
 
 	str_begin String = String(iota + database.ErrorsNumber)
 
+	STR_ACCOUNT_DISABLED
+	STR_ADAPTED_FROM
 	STR_ADD
 	STR_ADD_ARTICLES
 	STR_ADD_DAY
 	STR_ADD_MEAL
+	STR_ADD_PASSKEY
+	STR_ADD_SLOT
+	STR_ADMIN
+	STR_ADMIN_ROLE
+	STR_ALLERGENS
+	STR_ALLERGEN_CELERY
+	STR_ALLERGEN_CRUSTACEANS
+	STR_ALLERGEN_EGGS
+	STR_ALLERGEN_FISH
+	STR_ALLERGEN_GLUTEN
+	STR_ALLERGEN_HONEY
+	STR_ALLERGEN_LUPIN
+	STR_ALLERGEN_MEAT
+	STR_ALLERGEN_MILK
+	STR_ALLERGEN_MOLLUSCS
+	STR_ALLERGEN_MUSTARD
+	STR_ALLERGEN_NUTS
+	STR_ALLERGEN_PEANUTS
+	STR_ALLERGEN_SESAME
+	STR_ALLERGEN_SOY
+	STR_ALLERGEN_SULPHITES
 	STR_ALL_ARTICLES
+	STR_ALL_RECIPES
 	STR_APPEND_ENTRIES
 	STR_ARTICLES
+	STR_ARTICLES_COUNT
+	STR_AUDIT_DELETE
+	STR_AUDIT_DISABLE
+	STR_AUDIT_ENABLE
+	STR_AUDIT_LOG
+	STR_AUDIT_LOG_EMPTY
+	STR_AUDIT_RESEND
+	STR_BIO
+	STR_CALENDAR
+	STR_CALENDAR_DISABLED
+	STR_CALENDAR_EXPIRATIONS
+	STR_CALENDAR_EXPIRES
+	STR_CALENDAR_MEALS
+	STR_CALENDAR_MENUS
+	STR_CALENDAR_TEXT
 	STR_CANCEL
+	STR_CARBOHYDRATES
+	STR_CATEGORY
+	STR_CATEGORY_BREAD
+	STR_CATEGORY_CHEESE
+	STR_CATEGORY_DAIRY
+	STR_CATEGORY_FISH
+	STR_CATEGORY_FRUIT
+	STR_CATEGORY_LEFTOVERS
+	STR_CATEGORY_MEAT
+	STR_CATEGORY_SAUCES
+	STR_CATEGORY_VEGETABLES
 	STR_CHANGE_EMAIL
 	STR_CHANGE_PASSWORD
 	STR_CHANGE_USERNAME
 	STR_CLICK_HERE
 	STR_CLONE
 	STR_CODE
+	STR_COLLECTIONS
+	STR_COLLECTIONS_TEXT
+	STR_COLLECTION_BY
+	STR_COLLECTION_SAVED
+	STR_COLLECTION_SKIPPED
+	STR_COMMENTS
 	STR_CONFIRM
+	STR_CONTAINS
+	STR_COOK
+	STR_COOKED
+	STR_COOKING_LOG
+	STR_COOKING_LOG_EMPTY
+	STR_COOK_NOW
+	STR_COOK_NOW_EMPTY
+	STR_COOK_NOW_TEXT
 	STR_CURRENT_SEARCH
+	STR_CUSTOM_SLOTS
+	STR_DATE
 	STR_DAYS
 	STR_DELETE
+	STR_DELETE_ACCOUNT_TEXT
 	STR_DELETE_CONFIRM_EMAIL
 	STR_DELETE_LINK
 	STR_DELETE_MENU
@@ -44,7 +111,15 @@ const (
 	STR_DELETE_USER
 	STR_DELETE_USER_TEXT1
 	STR_DELETE_USER_TEXT2
+	STR_DIETARY_PROFILE
+	STR_DIETARY_PROFILE_TEXT
+	STR_DIETS
+	STR_DIET_VEGAN
+	STR_DIET_VEGETARIAN
 	STR_DIRECTIONS
+	STR_DISABLE_ACCOUNT
+	STR_DISABLE_CALENDAR
+	STR_DISABLE_TOTP
 	STR_EDIT
 	STR_EDIT_ARTICLE
 	STR_EDIT_DAY
@@ -53,34 +128,65 @@ const (
 	STR_EDIT_MENU
 	STR_EDIT_RECIPE
 	STR_EDIT_SECTION
+	STR_EFFECTIVE_EXPIRATION
 	STR_EMAIL
 	STR_EMAIL_CHANGED
+	STR_EMAIL_CHANGED_EMAIL
 	STR_EMAIL_LANG
+	STR_EMAIL_NOT_VERIFIED
+	STR_EMAIL_PENDING
 	STR_EMAIL_SENT
 	STR_EMAIL_SETTINGS
+	STR_EMAIL_VERIFICATION_SENT
+	STR_EMAIL_VERIFIED
+	STR_ENABLE_ACCOUNT
+	STR_ENABLE_CALENDAR
+	STR_ENABLE_TOTP
+	STR_EVENT_DELETED
+	STR_EVENT_EMAIL_CHANGED
+	STR_EVENT_IDENTITY_LINKED
+	STR_EVENT_PASSKEY_ADDED
+	STR_EVENT_PASSKEY_DELETED
+	STR_EVENT_PASSWORD_CHANGED
+	STR_EVENT_PASSWORD_RESET
+	STR_EVENT_SESSION_REVOKED
+	STR_EVENT_SIGNIN
+	STR_EVENT_SIGNIN_FAILED
+	STR_EVENT_TOTP_DISABLED
+	STR_EVENT_TOTP_ENABLED
+	STR_EVENT_USERNAME_CHANGED
 	STR_EXPIRATION
+	STR_FATS
+	STR_FORGOTTEN_RECIPES
 	STR_FORGOT_PASSWORD
+	STR_FRIDAY
 	STR_FROM
+	STR_FROZEN
 	STR_GENERATE_LINK
 	STR_GOOD_MORNING
 	STR_GOODBYE
 	STR_GOODBYE_EMAIL
 	STR_HISTORY
+	STR_IDENTITY_LINKED
 	STR_INFO
 	STR_INFO_CODE
 	STR_INFO_HISTORY
 	STR_INFO_INTRO
-	STR_INFO_STATS
 	STR_INFO_SUPPORT
 	STR_INFO_TUTORIAL
 	STR_INFO_VERSION
 	STR_INGREDIENTS
+	STR_INGREDIENTS_AVAILABLE
 	STR_INVALID_DATES
+	STR_KCAL
 	STR_LANGUAGE
+	STR_LAST_COOKED
+	STR_LINK_IDENTITY
 	STR_LOGOUT
 	STR_MEALS
-	STR_MEALS_NUMBER
 	STR_MENUS
+	STR_MISSING_INGREDIENTS
+	STR_MONDAY
 	STR_NAME
 	STR_NETWORK_ERROR
 	STR_NEW_DAY
@@ -89,66 +195,187 @@ const (
 	STR_NEW_PASSWORD
 	STR_NEW_RECIPE
 	STR_NEW_SECTION
+	STR_NEW_SUBSECTION
 	STR_NEW_USERNAME
+	STR_NEXT_WEEK
 	STR_NOREPLY
 	STR_NOTES
+	STR_NOT_COUNTED
+	STR_NOT_SUITABLE
+	STR_NO_CATEGORY
+	STR_NO_MEALS_SUGGESTED
+	STR_NO_PARENT_SECTION
+	STR_NO_REPEAT_DAYS
+	STR_NO_TEMPLATE
+	STR_NUTRITION
 	STR_OK
 	STR_OLD_PASSWORD
+	STR_ONLY_SUITABLE
+	STR_OPENED
 	STR_ORDER_CHANGED
+	STR_ORIGIN_UPDATED
+	STR_PAGE_EXPIRED
 	STR_PAGE_NOT_FOUND
+	STR_PARENT_SECTION
+	STR_PASSKEYS
+	STR_PASSKEYS_EMPTY
+	STR_PASSKEYS_TEXT
+	STR_PASSKEY_ADDED
+	STR_PASSKEY_CREATED
+	STR_PASSKEY_LAST_USED
+	STR_PASSKEY_NAME
+	STR_PASSKEY_UNAVAILABLE
 	STR_PASSWORD
+	STR_PASSWORDS_DISABLED
 	STR_PASSWORD_CHANGED
 	STR_PASSWORD_CHANGED_EMAIL
+	STR_PER_SERVING
+	STR_PREVIOUS_WEEK
 	STR_PRINT
+	STR_PROFILE_IS_PUBLIC
+	STR_PROFILE_PUBLIC
+	STR_PROTEINS
+	STR_PUBLIC_PROFILE
+	STR_PULL_CONFLICTS
+	STR_PULL_UPDATES
 	STR_QUANTITY
+	STR_RATING
+	STR_RECIPE_HISTORY
 	STR_RECIPE_IS_SHARED
 	STR_RECIPE_IS_UNSHARED
 	STR_RECIPES
 	STR_RECIPES_EMPTY
+	STR_RECOVERY_CODES
+	STR_RECOVERY_CODES_LEFT
+	STR_RECOVERY_CODES_TEXT
 	STR_REGARDS
 	STR_REGENERATE_LINK
 	STR_REPEAT_PASSWORD
+	STR_RESEND_LINK
 	STR_RESET_PASSWORD
 	STR_RESET_PASSWORD_EMAIL
+	STR_RESET_SHELF_LIVES
+	STR_RESTORE
+	STR_RESTORE_REVISION_TEXT
+	STR_REVISION
+	STR_REVISIONS_EMPTY
+	STR_REVOKE
+	STR_REVOKE_OTHER_SESSIONS
+	STR_SATURDAY
 	STR_SAVE
+	STR_SAVE_ALL
+	STR_SAVE_AS_TEMPLATE
+	STR_SEARCH
 	STR_SEARCH_ARTICLES
 	STR_SEARCH_EMPTY
+	STR_SEARCH_NO_RESULTS
 	STR_SECTION
 	STR_SECTION_EMPTY
 	STR_SECTIONS
+	STR_SECURITY_LOG
+	STR_SECURITY_LOG_EMPTY
+	STR_SECURITY_LOG_TEXT
+	STR_SEED
 	STR_SEE_TAGS
+	STR_SERVINGS
+	STR_SESSIONS
+	STR_SESSIONS_REVOKED
+	STR_SESSIONS_TEXT
+	STR_SESSION_CURRENT
+	STR_SESSION_LAST_SEEN
+	STR_SESSION_REVOKED
 	STR_SETTINGS
 	STR_SETTINGS_SAVED
 	STR_SHARE
+	STR_SHARED_RECIPES
+	STR_SHARES
+	STR_SHARES_EMPTY
+	STR_SHARES_INACTIVE
+	STR_SHARE_EXPIRATION
+	STR_SHARE_EXPIRED
+	STR_SHARE_EXPIRES
+	STR_SHARE_MAX_VIEWS
+	STR_SHARE_REVOKED
+	STR_SHARE_SAVES
+	STR_SHARE_VIEWS
+	STR_SHELF_LIVES
+	STR_SHELF_LIVES_TEXT
 	STR_SHOPPINGLIST
 	STR_SHOPPINGLIST_EMPTY
 	STR_SIGNIN
+	STR_SIGNIN_PASSKEY
+	STR_SIGNIN_WITH
 	STR_SIGNUP
 	STR_SIGNUP_DONE
-	STR_STARS
-	STR_STATS
-	STR_STATS_ARTICLES
-	STR_STATS_ENTRIES
-	STR_STATS_MENUS
-	STR_STATS_RECIPES
-	STR_STATS_SECTIONS
-	STR_STATS_USERS
+	STR_SLOTS
+	STR_SLOTS_TEXT
+	STR_SLOT_BREAKFAST
+	STR_SLOT_DINNER
+	STR_SLOT_LUNCH
+	STR_SLOT_SNACK
+	STR_SSO
+	STR_SSO_FAILED
+	STR_SSO_LINK_REQUIRED
+	STR_START_DATE
+	STR_START_DATE_TEXT
+	STR_STATS_HISTORY
+	STR_STATS_HISTORY_EMPTY
 	STR_STORAGE
 	STR_STORAGE_EMPTY
+	STR_STORAGE_FREEZER
+	STR_STORAGE_FRIDGE
+	STR_STORAGE_PANTRY
+	STR_STORAGE_TYPE
+	STR_STORAGE_TYPE_INHERITED
+	STR_SUGGEST_MEALS
+	STR_SUGGEST_MEALS_TEXT
+	STR_SUNDAY
 	STR_SUPPORT
 	STR_TAGS
+	STR_TAG_CONSTRAINTS
+	STR_TAG_CONSTRAINTS_TEXT
+	STR_TEMPLATE
+	STR_TEMPLATES
+	STR_TEMPLATES_EMPTY
+	STR_TEMPLATE_SAVED
+	STR_TEMPLATE_TEXT
+	STR_THIS_WEEK
+	STR_THURSDAY
 	STR_TO
+	STR_TODAY
+	STR_TOO_MANY_REQUESTS
+	STR_TOTAL
+	STR_TOTP
+	STR_TOTP_CODE
+	STR_TOTP_DISABLED
+	STR_TOTP_DISABLED_EMAIL
+	STR_TOTP_ENABLED_EMAIL
+	STR_TOTP_IS_ENABLED
+	STR_TOTP_SECRET
+	STR_TOTP_SETUP_TEXT
+	STR_TOTP_SIGNIN_TEXT
+	STR_TOTP_TEXT
+	STR_TUESDAY
 	STR_TUTORIAL
 	STR_UNKNOWN_LANG
 	STR_UNKNOWN_REQUEST
 	STR_UNMATCHING_PASSWORDS
 	STR_UNSUBSCRIBE
+	STR_USERS
+	STR_USERS_EMPTY
 	STR_USER_CREATED
 	STR_USER_DELETED
 	STR_USERNAME
 	STR_USERNAME_CHANGED
+	STR_USES_EXPIRING
+	STR_VERIFICATION_RESENT
+	STR_VERIFY_EMAIL
+	STR_VERIFY_EMAIL_EMAIL
+	STR_VERIFY_EMAIL_TEXT
 	STR_VERSION
 	STR_WANT_NEWSLETTER
+	STR_WEDNESDAY
+	STR_WEEK_EMPTY
 	STR_WELCOME_EMAIL
 	STR_WELCOMEBACK
 
@@ -3,6 +3,8 @@ package langs
 import (
 	"context"
 	"strings"
+
+	"cucinassistant/database"
 )
 
 const contextKey string = "lang"
@@ -28,3 +30,79 @@ func Translate(ctx context.Context, s String) string {
 func TranslateArg(ctx context.Context, s String, a string) string {
 	return strings.ReplaceAll(Translate(ctx, s), placeholder, a)
 }
+
+// Weekdays contains the Strings of the days of the week,
+// starting from monday
+var Weekdays = [7]String{
+	STR_MONDAY, STR_TUESDAY, STR_WEDNESDAY, STR_THURSDAY,
+	STR_FRIDAY, STR_SATURDAY, STR_SUNDAY,
+}
+
+// slotNames contains the String of every predefined slot
+var slotNames = map[string]String{
+	database.SLOT_BREAKFAST: STR_SLOT_BREAKFAST,
+	database.SLOT_LUNCH:     STR_SLOT_LUNCH,
+	database.SLOT_SNACK:     STR_SLOT_SNACK,
+	database.SLOT_DINNER:    STR_SLOT_DINNER,
+}
+
+// TranslateSlot translates the name of a slot, if it is a predefined one
+func TranslateSlot(ctx context.Context, slot string) string {
+	if s, found := slotNames[slot]; found {
+		return Translate(ctx, s)
+	}
+
+	return slot
+}
+
+// categoryNames contains the String of every category
+var categoryNames = map[database.Category]String{
+	database.CATEGORY_BREAD:      STR_CATEGORY_BREAD,
+	database.CATEGORY_CHEESE:     STR_CATEGORY_CHEESE,
+	database.CATEGORY_DAIRY:      STR_CATEGORY_DAIRY,
+	database.CATEGORY_FISH:       STR_CATEGORY_FISH,
+	database.CATEGORY_FRUIT:      STR_CATEGORY_FRUIT,
+	database.CATEGORY_LEFTOVERS:  STR_CATEGORY_LEFTOVERS,
+	database.CATEGORY_MEAT:       STR_CATEGORY_MEAT,
+	database.CATEGORY_SAUCES:     STR_CATEGORY_SAUCES,
+	database.CATEGORY_VEGETABLES: STR_CATEGORY_VEGETABLES,
+}
+
+// storageTypeNames contains the String of every storage type
+var storageTypeNames = map[database.StorageType]String{
+	database.STORAGE_FRIDGE:  STR_STORAGE_FRIDGE,
+	database.STORAGE_FREEZER: STR_STORAGE_FREEZER,
+	database.STORAGE_PANTRY:  STR_STORAGE_PANTRY,
+}
+
+// CategoryName returns the String with the name of a category
+func CategoryName(c database.Category) String {
+	return categoryNames[c]
+}
+
+// StorageTypeName returns the String with the name of a storage type
+func StorageTypeName(t database.StorageType) String {
+	return storageTypeNames[t]
+}
+
+// eventNames contains the String of every security event
+var eventNames = map[database.SecurityEvent]String{
+	database.EVENT_DELETED:          STR_EVENT_DELETED,
+	database.EVENT_EMAIL_CHANGED:    STR_EVENT_EMAIL_CHANGED,
+	database.EVENT_IDENTITY_LINKED:  STR_EVENT_IDENTITY_LINKED,
+	database.EVENT_PASSKEY_ADDED:    STR_EVENT_PASSKEY_ADDED,
+	database.EVENT_PASSKEY_DELETED:  STR_EVENT_PASSKEY_DELETED,
+	database.EVENT_PASSWORD_CHANGED: STR_EVENT_PASSWORD_CHANGED,
+	database.EVENT_PASSWORD_RESET:   STR_EVENT_PASSWORD_RESET,
+	database.EVENT_SESSION_REVOKED:  STR_EVENT_SESSION_REVOKED,
+	database.EVENT_SIGNIN:           STR_EVENT_SIGNIN,
+	database.EVENT_SIGNIN_FAILED:    STR_EVENT_SIGNIN_FAILED,
+	database.EVENT_TOTP_DISABLED:    STR_EVENT_TOTP_DISABLED,
+	database.EVENT_TOTP_ENABLED:     STR_EVENT_TOTP_ENABLED,
+	database.EVENT_USERNAME_CHANGED: STR_EVENT_USERNAME_CHANGED,
+}
+
+// EventName returns the String with the name of a security event
+func EventName(e database.SecurityEvent) String {
+	return eventNames[e]
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"cucinassistant/configs"
 	"cucinassistant/database"
@@ -26,6 +27,7 @@ func main() {
 	slog.Warn("Connecting to the database...")
 	database.Connect()
 	database.Check()
+	database.StartCleanup(time.Hour)
 
 	// Adds a listener for shutting down the server if it's on debug mode
 	slog.Warn("Starting web server...")
@@ -0,0 +1,161 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is the tolerance used when checking the expiration
+const clockSkew = time.Minute
+
+// jwk is a public key of the JWKS document
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWK returns the RSA or P-256 public key contained in a JWK
+func parseJWK(k jwk) (any, bool) {
+	switch k.Kty {
+	case "RSA":
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, false
+		}
+
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, true
+
+	case "EC":
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if k.Crv != "P-256" || errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, false
+		}
+
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, false
+		}
+		return pub, true
+	}
+
+	return nil, false
+}
+
+// fetchKeys downloads the public keys of the provider
+func (p *Provider) fetchKeys() error {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.getJSON(p.JWKSURI, &doc); err != nil {
+		return err
+	}
+
+	keys := make(map[string]any)
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, ok := parseJWK(k); ok {
+			keys[k.Kid] = key
+		}
+	}
+
+	p.keys = keys
+	return nil
+}
+
+// getKey returns the public key with the given ID. If it's not known,
+// the keys are downloaded again (the provider may have rotated them).
+func (p *Provider) getKey(kid string) (any, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, found := p.keys[kid]; found {
+		return key, true
+	} else if p.fetchKeys() != nil {
+		return nil, false
+	}
+
+	key, found := p.keys[kid]
+	return key, found
+}
+
+// verifyIDToken verifies the signature (RS256 or ES256) and the claims
+// of an ID token, then returns them
+func (p *Provider) verifyIDToken(raw string, nonce string, now time.Time) (Claims, error) {
+	var claims Claims
+
+	// Splits and decodes the token
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return claims, ErrInvalidToken
+	}
+	header, errH := base64.RawURLEncoding.DecodeString(parts[0])
+	payload, errP := base64.RawURLEncoding.DecodeString(parts[1])
+	signature, errS := base64.RawURLEncoding.DecodeString(parts[2])
+	if errH != nil || errP != nil || errS != nil {
+		return claims, ErrInvalidToken
+	}
+
+	var h struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if json.Unmarshal(header, &h) != nil {
+		return claims, ErrInvalidToken
+	}
+
+	// Verifies the signature
+	key, found := p.getKey(h.Kid)
+	if !found {
+		return claims, ErrInvalidToken
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	var valid bool
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		valid = h.Alg == "RS256" && rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], signature) == nil
+	case *ecdsa.PublicKey:
+		valid = h.Alg == "ES256" && len(signature) == 64 &&
+			ecdsa.Verify(pub, hash[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:]))
+	}
+	if !valid {
+		return claims, ErrInvalidToken
+	}
+
+	// Checks the claims
+	if json.Unmarshal(payload, &claims) != nil {
+		return claims, ErrInvalidToken
+	}
+	if strings.TrimSuffix(claims.Issuer, "/") != p.Issuer || claims.Subject == "" ||
+		!slices.Contains(claims.Audience, p.ClientID) ||
+		(len(claims.Audience) > 1 && claims.AuthorizedParty != p.ClientID) ||
+		now.After(time.Unix(claims.Expiry, 0).Add(clockSkew)) ||
+		nonce == "" || claims.Nonce != nonce {
+		return Claims{}, ErrInvalidToken
+	}
+
+	return claims, nil
+}
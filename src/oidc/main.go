@@ -0,0 +1,225 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"cucinassistant/configs"
+)
+
+// ErrProvider is returned when the provider can't be reached
+// or gives an unexpected response
+var ErrProvider = errors.New("oidc: invalid response from the provider")
+
+// ErrInvalidToken is returned when the ID token is not valid
+var ErrInvalidToken = errors.New("oidc: invalid id token")
+
+// Provider is an OpenID Connect provider, configured
+// with its discovery document
+type Provider struct {
+	// Issuer is the URL of the provider
+	Issuer string
+
+	// ClientID and ClientSecret are the credentials of CucinAssistant
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the URL the users are sent back to
+	RedirectURL string
+
+	// AuthorizationEndpoint, TokenEndpoint and JWKSURI are
+	// read from the discovery document
+	AuthorizationEndpoint string
+	TokenEndpoint         string
+	JWKSURI               string
+
+	// client is used to make the requests
+	client *http.Client
+
+	// keys caches the public keys of the provider, by their ID
+	keys map[string]any
+
+	// mutex protects keys
+	mutex sync.Mutex
+}
+
+// Claims contains the claims of an ID token that are used
+type Claims struct {
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	AuthorizedParty   string   `json:"azp"`
+	Expiry            int64    `json:"exp"`
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	PreferredUsername string   `json:"preferred_username"`
+}
+
+// audience is the aud claim, that can be either a string or an array
+type audience []string
+
+// UnmarshalJSON accepts both a string and an array of strings
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// provider is the provider read from configs, once it has been discovered
+var provider *Provider
+
+// providerMutex protects provider
+var providerMutex sync.Mutex
+
+// Enabled returns true if a provider has been configured
+func Enabled() bool {
+	return configs.OIDCIssuer != ""
+}
+
+// Get returns the provider read from configs. It is discovered
+// the first time it's needed, so that CucinAssistant can start
+// even if the provider is not reachable.
+func Get() (*Provider, error) {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+
+	if provider == nil {
+		p, err := Discover(configs.OIDCIssuer, configs.OIDCClientID, configs.OIDCClientSecret,
+			configs.BaseURL+"/user/oidc/callback")
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	}
+
+	return provider, nil
+}
+
+// Discover reads the discovery document of the provider
+func Discover(issuer string, clientID string, clientSecret string, redirectURL string) (*Provider, error) {
+	p := &Provider{
+		Issuer:       strings.TrimSuffix(issuer, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+
+	// Fetches the document
+	var doc struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	if err := p.getJSON(p.Issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, err
+	}
+
+	// Ensures it's complete and it belongs to the issuer
+	if strings.TrimSuffix(doc.Issuer, "/") != p.Issuer || doc.AuthorizationEndpoint == "" ||
+		doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, ErrProvider
+	}
+
+	p.AuthorizationEndpoint = doc.AuthorizationEndpoint
+	p.TokenEndpoint = doc.TokenEndpoint
+	p.JWKSURI = doc.JWKSURI
+	return p, nil
+}
+
+// getJSON fetches and decodes a JSON document
+func (p *Provider) getJSON(url string, v any) error {
+	res, err := p.client.Get(url)
+	if err != nil {
+		return ErrProvider
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || json.NewDecoder(res.Body).Decode(v) != nil {
+		return ErrProvider
+	}
+
+	return nil
+}
+
+// RandomString returns a random string, to be used as
+// a state, a nonce or a code verifier
+func RandomString() string {
+	buffer := make([]byte, 32)
+	rand.Read(buffer)
+	return base64.RawURLEncoding.EncodeToString(buffer)
+}
+
+// AuthURL returns the URL the user has to be sent to in order to sign in.
+// The verifier is used for PKCE.
+func (p *Provider) AuthURL(state string, nonce string, verifier string) string {
+	challenge := sha256.Sum256([]byte(verifier))
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.ClientID},
+		"redirect_uri":          {p.RedirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(p.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return p.AuthorizationEndpoint + separator + params.Encode()
+}
+
+// Exchange exchanges the code received on the callback for an ID token,
+// then verifies it and returns its claims
+func (p *Provider) Exchange(code string, verifier string, nonce string, now time.Time) (Claims, error) {
+	// Prepares the request
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequest("POST", p.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, ErrProvider
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+
+	// Sends it
+	res, err := p.client.Do(req)
+	if err != nil {
+		return Claims{}, ErrProvider
+	}
+	defer res.Body.Close()
+
+	// Reads the token
+	var body struct {
+		IDToken string `json:"id_token"`
+	}
+	if res.StatusCode != http.StatusOK || json.NewDecoder(res.Body).Decode(&body) != nil || body.IDToken == "" {
+		return Claims{}, ErrProvider
+	}
+
+	return p.verifyIDToken(body.IDToken, nonce, now)
+}
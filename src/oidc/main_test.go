@@ -0,0 +1,238 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockIssuer is a local OpenID Connect provider, that signs
+// the ID tokens with both an RSA and an ECDSA key
+type mockIssuer struct {
+	server *httptest.Server
+
+	rsaKey *rsa.PrivateKey
+	ecKey  *ecdsa.PrivateKey
+
+	// codes contains, for every code, the PKCE challenge
+	// and the claims of the token
+	codes map[string]mockCode
+}
+
+// mockCode is an authorization code given by the mockIssuer
+type mockCode struct {
+	challenge string
+	alg       string
+	claims    map[string]any
+}
+
+// newMockIssuer starts a mockIssuer
+func newMockIssuer(t *testing.T) *mockIssuer {
+	m := &mockIssuer{codes: make(map[string]mockCode)}
+
+	var err error
+	if m.rsaKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatalf("Cannot generate RSA key: %s", err.Error())
+	} else if m.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		t.Fatalf("Cannot generate EC key: %s", err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		e := big64(m.rsaKey.E)
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encode(m.rsaKey.N.Bytes()), "e": e},
+			{"kty": "EC", "kid": "ec", "crv": "P-256",
+				"x": encode(m.ecKey.X.FillBytes(make([]byte, 32))), "y": encode(m.ecKey.Y.FillBytes(make([]byte, 32)))},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		c, found := m.codes[r.PostFormValue("code")]
+		challenge := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if id != "client" || secret != "secret" || !found || encode(challenge[:]) != c.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		delete(m.codes, r.PostFormValue("code"))
+		json.NewEncoder(w).Encode(map[string]string{"id_token": m.sign(t, c.alg, c.claims)})
+	})
+	m.server = httptest.NewServer(mux)
+
+	return m
+}
+
+// encode encodes some bytes as base64url
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// big64 encodes an RSA exponent as base64url
+func big64(e int) string {
+	return encode([]byte{byte(e >> 16), byte(e >> 8), byte(e)})
+}
+
+// sign returns an ID token with the given claims, signed with the given algorithm
+func (m *mockIssuer) sign(t *testing.T, alg string, claims map[string]any) string {
+	kid := map[string]string{"RS256": "rsa", "ES256": "ec"}[alg]
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := encode(header) + "." + encode(payload)
+	hash := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		signature, _ = rsa.SignPKCS1v15(rand.Reader, m.rsaKey, crypto.SHA256, hash[:])
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, m.ecKey, hash[:])
+		if err != nil {
+			t.Fatalf("Cannot sign token: %s", err.Error())
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signed + "." + encode(signature)
+}
+
+// authorize simulates the sign in of a user, following the URL generated
+// by the provider: it returns the code that is given to the callback
+func (m *mockIssuer) authorize(t *testing.T, authURL string, alg string, claims map[string]any) string {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("Cannot parse auth URL: %s", err.Error())
+	}
+
+	code := RandomString()
+	m.codes[code] = mockCode{challenge: u.Query().Get("code_challenge"), alg: alg, claims: claims}
+	return code
+}
+
+func TestDiscover(t *testing.T) {
+	m := newMockIssuer(t)
+	defer m.server.Close()
+
+	type data struct {
+		Issuer string
+
+		ExpectedErr error
+	}
+
+	cases := []struct {
+		Message string
+		Data    data
+	}{
+		{"discovered unknown provider", data{Issuer: m.server.URL + "/unknown", ExpectedErr: ErrProvider}},
+		{"discovered unreachable provider", data{Issuer: "http://127.0.0.1:1", ExpectedErr: ErrProvider}},
+		{"", data{Issuer: m.server.URL}},
+		{"(trailing slash)", data{Issuer: m.server.URL + "/"}},
+	}
+
+	for _, tc := range cases {
+		p, err := Discover(tc.Data.Issuer, "client", "secret", "https://cucinassistant.com/callback")
+		if err != tc.Data.ExpectedErr {
+			t.Errorf("%s: expected err <%v>, got <%v>", tc.Message, tc.Data.ExpectedErr, err)
+		} else if err == nil && p.TokenEndpoint != m.server.URL+"/token" {
+			t.Errorf("%s: wrong token endpoint <%s>", tc.Message, p.TokenEndpoint)
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	m := newMockIssuer(t)
+	defer m.server.Close()
+
+	p, err := Discover(m.server.URL, "client", "secret", "https://cucinassistant.com/callback")
+	if err != nil {
+		t.Fatalf("Cannot discover provider: %s", err.Error())
+	}
+
+	now := time.Now()
+	claims := func(changes map[string]any) map[string]any {
+		c := map[string]any{
+			"iss": m.server.URL, "sub": "subject", "aud": "client", "exp": now.Add(time.Hour).Unix(),
+			"nonce": "nonce", "email": "user@email.com", "email_verified": true, "preferred_username": "user",
+		}
+		for k, v := range changes {
+			c[k] = v
+		}
+		return c
+	}
+
+	type data struct {
+		Alg      string
+		Claims   map[string]any
+		Verifier string
+
+		ExpectedErr error
+	}
+
+	cases := []struct {
+		Message string
+		Data    data
+	}{
+		{"exchanged with wrong verifier", data{Alg: "RS256", Claims: claims(nil), Verifier: "wrong", ExpectedErr: ErrProvider}},
+		{"exchanged unsigned token", data{Alg: "none", Claims: claims(nil), ExpectedErr: ErrInvalidToken}},
+		{"exchanged token of another issuer", data{Alg: "RS256", Claims: claims(map[string]any{"iss": "https://evil.com"}), ExpectedErr: ErrInvalidToken}},
+		{"exchanged token for another client", data{Alg: "RS256", Claims: claims(map[string]any{"aud": "other"}), ExpectedErr: ErrInvalidToken}},
+		{"exchanged token for many clients", data{Alg: "RS256", Claims: claims(map[string]any{"aud": []string{"client", "other"}}), ExpectedErr: ErrInvalidToken}},
+		{"exchanged expired token", data{Alg: "RS256", Claims: claims(map[string]any{"exp": now.Add(-time.Hour).Unix()}), ExpectedErr: ErrInvalidToken}},
+		{"exchanged token with wrong nonce", data{Alg: "RS256", Claims: claims(map[string]any{"nonce": "other"}), ExpectedErr: ErrInvalidToken}},
+		{"", data{Alg: "RS256", Claims: claims(nil)}},
+		{"(ES256)", data{Alg: "ES256", Claims: claims(nil)}},
+		{"(many audiences)", data{Alg: "RS256", Claims: claims(map[string]any{"aud": []string{"client", "other"}, "azp": "client"})}},
+	}
+
+	for _, tc := range cases {
+		verifier := RandomString()
+		code := m.authorize(t, p.AuthURL("state", "nonce", verifier), tc.Data.Alg, tc.Data.Claims)
+		if tc.Data.Verifier != "" {
+			verifier = tc.Data.Verifier
+		}
+
+		got, err := p.Exchange(code, verifier, "nonce", now)
+		if err != tc.Data.ExpectedErr {
+			t.Errorf("%s: expected err <%v>, got <%v>", tc.Message, tc.Data.ExpectedErr, err)
+		} else if err == nil && (got.Subject != "subject" || got.Email != "user@email.com" ||
+			!got.EmailVerified || got.PreferredUsername != "user") {
+			t.Errorf("%s: wrong claims <%+v>", tc.Message, got)
+		}
+	}
+}
+
+func TestAuthURL(t *testing.T) {
+	p := Provider{ClientID: "client", RedirectURL: "https://cucinassistant.com/callback",
+		AuthorizationEndpoint: "https://issuer.com/authorize?tenant=family"}
+
+	u, err := url.Parse(p.AuthURL("state", "nonce", "verifier"))
+	if err != nil {
+		t.Fatalf("Cannot parse auth URL: %s", err.Error())
+	}
+
+	challenge := sha256.Sum256([]byte("verifier"))
+	q := u.Query()
+	if q.Get("tenant") != "family" || q.Get("state") != "state" || q.Get("nonce") != "nonce" ||
+		q.Get("code_challenge") != encode(challenge[:]) || !strings.Contains(q.Get("scope"), "openid") {
+		t.Errorf("wrong auth URL <%s>", u.String())
+	}
+}
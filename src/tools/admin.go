@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"cucinassistant/configs"
+	"cucinassistant/database"
+)
+
+func main() {
+	// Prints a welcome text
+	welcome := "CucinAssistant Admin Tool"
+	fmt.Println(welcome)
+	fmt.Println(strings.Repeat("=", len(welcome)))
+
+	// Initializes all the modules
+	configs.LoadAndParse()
+	database.Connect()
+
+	scanner := bufio.NewScanner(os.Stdin)
+
+	// Asks for the user
+	fmt.Printf("Username: ")
+	scanner.Scan()
+	user, err := database.GetUser("username", strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Printf("\nCannot find the user: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	// Grants the role, or revokes it if the user is already an admin
+	if user.Admin {
+		fmt.Printf("%s is an admin: the role will be revoked. ", user.Username)
+	} else {
+		fmt.Printf("%s is not an admin: the role will be granted. ", user.Username)
+	}
+	if !confirm(scanner) {
+		fmt.Println("\nAborted.")
+		os.Exit(1)
+	}
+
+	if err = user.SetAdmin(!user.Admin); err != nil {
+		fmt.Printf("\nCannot change the role: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Println("Done.")
+}
+
+func confirm(scanner *bufio.Scanner) bool {
+	fmt.Printf("Confirm? [y/n] ")
+	scanner.Scan()
+	return scanner.Text() == "y"
+}
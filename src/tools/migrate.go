@@ -65,6 +65,9 @@ func main() {
 		switch version { // remember to add fallthrough
 		case 8:
 			update8to9(db)
+			fallthrough
+		case 9:
+			update9to10(db)
 		}
 	} else {
 		fmt.Println("Setting up the schema...")
@@ -89,3 +92,92 @@ func update8to9(db *sql.DB) {
 	db.Exec(`ALTER TABLE days DROP CONSTRAINT days_pkey;`)
 	db.Exec(`ALTER TABLE days ADD CONSTRAINT days_pkey PRIMARY KEY (mid, position) DEFERRABLE INITIALLY IMMEDIATE;`)
 }
+
+func update9to10(db *sql.DB) {
+	fmt.Println("Upgrading from version 9 to version 10...")
+
+	// Updates the schema version
+	db.Exec(`UPDATE ca_version SET id=$1;`, configs.VersionCode)
+
+	// Adds the recipe revisions
+	db.Exec(`CREATE TABLE revisions (rid INT NOT NULL, number INT NOT NULL, created TIMESTAMP NOT NULL DEFAULT NOW(), name VARCHAR(64) NOT NULL, ingredients VARCHAR(4096) NOT NULL DEFAULT '', directions VARCHAR(4096) NOT NULL DEFAULT '', notes VARCHAR(4096) NOT NULL DEFAULT '', PRIMARY KEY (rid, number), FOREIGN KEY (rid) REFERENCES recipes (rid) ON DELETE CASCADE);`)
+
+	// Adds the recipe forks
+	db.Exec(`CREATE TABLE forks (rid INT NOT NULL, origin INT, code VARCHAR(32) NOT NULL, collection BOOLEAN NOT NULL DEFAULT FALSE, author VARCHAR(250) NOT NULL, ingredients VARCHAR(4096) NOT NULL DEFAULT '', directions VARCHAR(4096) NOT NULL DEFAULT '', PRIMARY KEY (rid), FOREIGN KEY (rid) REFERENCES recipes (rid) ON DELETE CASCADE, FOREIGN KEY (origin) REFERENCES recipes (rid) ON DELETE SET NULL);`)
+
+	// Adds the public collections and profiles
+	db.Exec(`CREATE TABLE collections (uid INT NOT NULL, tag VARCHAR NOT NULL, code VARCHAR(32) NOT NULL, PRIMARY KEY (uid, tag), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE, UNIQUE (code));`)
+	db.Exec(`CREATE TABLE profiles (uid INT NOT NULL, bio VARCHAR(1024) NOT NULL DEFAULT '', PRIMARY KEY (uid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE);`)
+
+	// Adds the share links limits and counters
+	db.Exec(`ALTER TABLE recipes ALTER COLUMN code TYPE VARCHAR(32);`)
+	db.Exec(`CREATE TABLE shares (code VARCHAR(32) NOT NULL, rid INT NOT NULL, created TIMESTAMP NOT NULL DEFAULT NOW(), expiration DATE, max_views INT, revoked TIMESTAMP, views INT NOT NULL DEFAULT 0, saves INT NOT NULL DEFAULT 0, PRIMARY KEY (code), FOREIGN KEY (rid) REFERENCES recipes (rid) ON DELETE CASCADE);`)
+	db.Exec(`INSERT INTO shares (code, rid) SELECT code, rid FROM recipes WHERE code IS NOT NULL;`)
+
+	// Adds the cooking log
+	db.Exec(`CREATE TABLE cookings (rid INT NOT NULL, cid SERIAL NOT NULL, date DATE NOT NULL, cook VARCHAR(64) NOT NULL DEFAULT '', stars INT, comments VARCHAR(1024) NOT NULL DEFAULT '', PRIMARY KEY (cid), FOREIGN KEY (rid) REFERENCES recipes (rid) ON DELETE CASCADE);`)
+	db.Exec(`CREATE INDEX cookings_rid_date ON cookings (rid, date);`)
+
+	// Adds the recipe servings and the nutrients database
+	db.Exec(`ALTER TABLE recipes ADD COLUMN servings INT NOT NULL DEFAULT 1;`)
+	db.Exec(`CREATE TABLE nutrients (name VARCHAR(250) NOT NULL, kcal REAL NOT NULL DEFAULT 0, proteins REAL NOT NULL DEFAULT 0, carbohydrates REAL NOT NULL DEFAULT 0, fats REAL NOT NULL DEFAULT 0, piece REAL, PRIMARY KEY (name));`)
+
+	// Adds the dietary profiles
+	db.Exec(`CREATE TABLE dietary_profiles (uid INT NOT NULL, allergens VARCHAR(32)[] NOT NULL DEFAULT '{}', diets VARCHAR(32)[] NOT NULL DEFAULT '{}', PRIMARY KEY (uid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE);`)
+
+	// Adds the menus start date
+	db.Exec(`ALTER TABLE menus ADD COLUMN start DATE;`)
+
+	// Adds the calendar feeds
+	db.Exec(`ALTER TABLE days ADD COLUMN updated TIMESTAMP NOT NULL DEFAULT NOW();`)
+	db.Exec(`ALTER TABLE articles ADD COLUMN updated TIMESTAMP NOT NULL DEFAULT NOW();`)
+	db.Exec(`CREATE TABLE calendars (uid INT NOT NULL, token VARCHAR(32) NOT NULL, PRIMARY KEY (uid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE, UNIQUE (token));`)
+
+	// Adds the meal slots and the menu templates
+	db.Exec(`ALTER TABLE menus ADD COLUMN slots VARCHAR(64)[] NOT NULL DEFAULT '{}';`)
+	db.Exec(`CREATE TABLE menu_templates (uid INT NOT NULL, tid SERIAL NOT NULL, name VARCHAR(64) NOT NULL, slots VARCHAR(64)[] NOT NULL DEFAULT '{}', PRIMARY KEY (tid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE, UNIQUE (uid, name));`)
+	db.Exec(`CREATE TABLE template_days (tid INT NOT NULL, position INT NOT NULL, meals VARCHAR(512)[] NOT NULL DEFAULT '{}', PRIMARY KEY (tid, position), FOREIGN KEY (tid) REFERENCES menu_templates (tid) ON DELETE CASCADE);`)
+
+	// Adds the nested storage sections
+	db.Exec(`ALTER TABLE sections ADD COLUMN parent INT REFERENCES sections (sid) ON DELETE CASCADE;`)
+	db.Exec(`ALTER TABLE sections DROP CONSTRAINT sections_uid_name_key;`)
+	db.Exec(`CREATE UNIQUE INDEX sections_uid_parent_name ON sections (uid, COALESCE(parent, 0), name);`)
+
+	// Adds the opening and freezing dates and the shelf lives
+	db.Exec(`ALTER TABLE sections ADD COLUMN storage VARCHAR(16) NOT NULL DEFAULT '';`)
+	db.Exec(`ALTER TABLE articles ADD COLUMN category VARCHAR(32) NOT NULL DEFAULT '', ADD COLUMN opened DATE, ADD COLUMN frozen DATE;`)
+	db.Exec(`CREATE TABLE shelf_lives (uid INT NOT NULL, category VARCHAR(32) NOT NULL, storage VARCHAR(16) NOT NULL, days INT NOT NULL, PRIMARY KEY (uid, category, storage), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE);`)
+
+	// Adds the two-factor authentication
+	db.Exec(`CREATE TABLE totp (uid INT NOT NULL, secret VARCHAR(64) NOT NULL, enabled BOOLEAN NOT NULL DEFAULT FALSE, last_step BIGINT NOT NULL DEFAULT 0, PRIMARY KEY (uid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE);`)
+	db.Exec(`CREATE TABLE recovery_codes (rcid SERIAL NOT NULL, uid INT NOT NULL, hash VARCHAR(250) NOT NULL, PRIMARY KEY (rcid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE);`)
+	db.Exec(`CREATE INDEX recovery_codes_uid ON recovery_codes (uid);`)
+
+	// Adds the passkeys
+	db.Exec(`CREATE TABLE passkeys (pkid SERIAL NOT NULL, uid INT NOT NULL, credential VARCHAR(1400) NOT NULL, public_key BYTEA NOT NULL, sign_count BIGINT NOT NULL DEFAULT 0, name VARCHAR(64) NOT NULL, created TIMESTAMP NOT NULL DEFAULT NOW(), last_used TIMESTAMP, PRIMARY KEY (pkid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE, UNIQUE (credential));`)
+
+	// Adds the email verification (the current emails are considered verified)
+	db.Exec(`ALTER TABLE ca_users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT TRUE, ADD COLUMN pending_email VARCHAR(250);`)
+	db.Exec(`ALTER TABLE ca_users ALTER COLUMN email_verified SET DEFAULT FALSE;`)
+
+	// Moves the user tokens to their own table (the current ones are dropped)
+	db.Exec(`CREATE TABLE user_tokens (tid SERIAL NOT NULL, uid INT NOT NULL, purpose VARCHAR(32) NOT NULL, hash VARCHAR(250) NOT NULL, expires TIMESTAMP NOT NULL, PRIMARY KEY (tid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE, UNIQUE (uid, purpose));`)
+	db.Exec(`ALTER TABLE ca_users DROP COLUMN token;`)
+
+	// Adds the active sessions (the users will have to sign in again)
+	db.Exec(`CREATE TABLE user_sessions (sid SERIAL NOT NULL, uid INT NOT NULL, key VARCHAR(64) NOT NULL, user_agent VARCHAR(250) NOT NULL DEFAULT '', ip VARCHAR(64) NOT NULL DEFAULT '', created TIMESTAMP NOT NULL DEFAULT NOW(), last_seen TIMESTAMP NOT NULL DEFAULT NOW(), PRIMARY KEY (sid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE, UNIQUE (key));`)
+	db.Exec(`CREATE INDEX user_sessions_uid ON user_sessions (uid);`)
+
+	// Adds the OpenID Connect identities
+	db.Exec(`CREATE TABLE user_identities (uid INT NOT NULL, issuer VARCHAR(250) NOT NULL, subject VARCHAR(250) NOT NULL, PRIMARY KEY (issuer, subject), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE CASCADE);`)
+	db.Exec(`CREATE INDEX user_identities_uid ON user_identities (uid);`)
+
+	// Adds the admin console
+	db.Exec(`ALTER TABLE ca_users ADD COLUMN admin BOOLEAN NOT NULL DEFAULT FALSE, ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;`)
+	db.Exec(`CREATE TABLE audit_log (aeid SERIAL NOT NULL, admin VARCHAR(250) NOT NULL, action VARCHAR(32) NOT NULL, target VARCHAR(250) NOT NULL, time TIMESTAMP NOT NULL DEFAULT NOW(), PRIMARY KEY (aeid));`)
+	db.Exec(`CREATE TABLE stats_history (day DATE NOT NULL, users INT NOT NULL, menus INT NOT NULL, sections INT NOT NULL, articles INT NOT NULL, entries INT NOT NULL, recipes INT NOT NULL, PRIMARY KEY (day));`)
+
+	// Adds the security events
+	db.Exec(`CREATE TABLE security_events (eid SERIAL NOT NULL, uid INT, username VARCHAR(250) NOT NULL, event VARCHAR(32) NOT NULL, ip VARCHAR(64) NOT NULL DEFAULT '', user_agent VARCHAR(250) NOT NULL DEFAULT '', time TIMESTAMP NOT NULL DEFAULT NOW(), PRIMARY KEY (eid), FOREIGN KEY (uid) REFERENCES ca_users (uid) ON DELETE SET NULL);`)
+	db.Exec(`CREATE INDEX security_events_uid ON security_events (uid);`)
+}
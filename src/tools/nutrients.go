@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"cucinassistant/configs"
+	"cucinassistant/database"
+)
+
+func main() {
+	slog.SetLogLoggerLevel(slog.LevelError)
+
+	// Prints a welcome text
+	fmt.Println("CucinAssistant Nutrients Importer")
+	fmt.Println("=================================")
+
+	// Checks the arguments
+	if len(os.Args) != 2 {
+		fmt.Println("Usage: go run tools/nutrients.go <file.csv>")
+		os.Exit(1)
+	}
+
+	// Initializes all the modules
+	configs.LoadAndParse()
+	database.Connect()
+	fmt.Println("Connected to the database.")
+
+	// Opens the file
+	file, err := os.Open(os.Args[1])
+	if err != nil {
+		fmt.Printf("Error opening the file: %s\n", err.Error())
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	// Imports the entries
+	fmt.Printf("Importing the nutrients from %s...\n", os.Args[1])
+	imported, err := database.ImportNutrients(file)
+	if err != nil {
+		fmt.Printf("Error after %d entries: %s\n", imported, err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("Done (%d entries imported).\n", imported)
+}
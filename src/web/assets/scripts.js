@@ -189,3 +189,67 @@ function formatExpirationInputs() {
         }
     });
 }
+
+
+
+// Encodes an ArrayBuffer as base64url
+function toBase64URL(buffer) {
+    return btoa(String.fromCharCode(...new Uint8Array(buffer)))
+        .replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+// Decodes a base64url string into an ArrayBuffer
+function fromBase64URL(text) {
+    text = text.replace(/-/g, '+').replace(/_/g, '/');
+    return Uint8Array.from(atob(text), c => c.charCodeAt(0)).buffer;
+}
+
+// Creates a new passkey with the data of the form, then submits
+// it with the response of the authenticator
+function createPasskey(event) {
+    event.preventDefault();
+    $('#passkey-error').addClass('hidden');
+
+    let form = event.target.closest('form');
+    if (!form.reportValidity()) { return; }
+
+    navigator.credentials.create({publicKey: {
+        challenge: fromBase64URL(form.dataset.challenge),
+        rp: {id: form.dataset.rp, name: 'CucinAssistant'},
+        user: {
+            id: fromBase64URL(form.dataset.user),
+            name: form.dataset.username,
+            displayName: form.dataset.username,
+        },
+        // ES256, EdDSA and RS256
+        pubKeyCredParams: [-7, -8, -257].map(alg => ({type: 'public-key', alg: alg})),
+        authenticatorSelection: {residentKey: 'required', userVerification: 'required'},
+        attestation: 'none',
+    }}).then(credential => {
+        form.elements['client-data'].value = toBase64URL(credential.response.clientDataJSON);
+        form.elements['attestation-object'].value = toBase64URL(credential.response.attestationObject);
+        form.submit();
+    }).catch(() => $('#passkey-error').removeClass('hidden'));
+}
+
+// Asks the authenticator to sign the challenge of the form with
+// a passkey, then submits it with the response
+function getPasskey(event) {
+    event.preventDefault();
+    $('#passkey-error').addClass('hidden');
+
+    let form = event.target.closest('form');
+    navigator.credentials.get({publicKey: {
+        challenge: fromBase64URL(form.dataset.challenge),
+        rpId: form.dataset.rp,
+        userVerification: 'required',
+    }}).then(credential => {
+        let response = credential.response;
+        form.elements['credential'].value = credential.id;
+        form.elements['user-handle'].value = response.userHandle ? toBase64URL(response.userHandle) : '';
+        form.elements['client-data'].value = toBase64URL(response.clientDataJSON);
+        form.elements['authenticator-data'].value = toBase64URL(response.authenticatorData);
+        form.elements['signature'].value = toBase64URL(response.signature);
+        form.submit();
+    }).catch(() => $('#passkey-error').removeClass('hidden'));
+}
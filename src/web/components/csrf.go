@@ -0,0 +1,19 @@
+package components
+
+import (
+	"context"
+)
+
+const csrfKey string = "csrf"
+
+// WithCSRF returns a copy of the context in which is saved
+// the CSRF token, used by CSRFInput
+func WithCSRF(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfKey, token)
+}
+
+// csrfToken returns the CSRF token saved in the context
+func csrfToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfKey).(string)
+	return token
+}
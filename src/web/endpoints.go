@@ -26,10 +26,51 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		GetHandler:  handlers.GetLang,
 		PostHandler: handlers.PostLang,
 	},
+
 	{
-		Path:        "/stats",
-		Unprotected: true,
-		GetHandler:  handlers.GetStats,
+		Path:       "/admin",
+		Admin:      true,
+		GetHandler: handlers.GetAdmin,
+	},
+	{
+		Path:       "/admin/audit",
+		Admin:      true,
+		GetHandler: handlers.GetAdminAudit,
+	},
+	{
+		Path:       "/admin/events",
+		Admin:      true,
+		GetHandler: handlers.GetAdminEvents,
+	},
+	{
+		Path:       "/admin/stats",
+		Admin:      true,
+		GetHandler: handlers.GetAdminStats,
+	},
+	{
+		Path:       "/admin/users/{UID}",
+		Admin:      true,
+		GetHandler: handlers.GetAdminUser,
+	},
+	{
+		Path:        "/admin/users/{UID}/delete",
+		Admin:       true,
+		PostHandler: handlers.PostAdminUserDelete,
+	},
+	{
+		Path:        "/admin/users/{UID}/disable",
+		Admin:       true,
+		PostHandler: handlers.PostAdminUserDisable,
+	},
+	{
+		Path:        "/admin/users/{UID}/enable",
+		Admin:       true,
+		PostHandler: handlers.PostAdminUserEnable,
+	},
+	{
+		Path:        "/admin/users/{UID}/resend_verification",
+		Admin:       true,
+		PostHandler: handlers.PostAdminUserResendVerification,
 	},
 
 	{
@@ -41,6 +82,18 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		GetHandler:  handlers.GetMenusNew,
 		PostHandler: handlers.PostMenusNew,
 	},
+	{
+		Path:       "/menus/templates",
+		GetHandler: handlers.GetMenusTemplates,
+	},
+	{
+		Path:        "/menus/templates/{TID}/delete",
+		PostHandler: handlers.PostMenusTemplateDelete,
+	},
+	{
+		Path:       "/menus/week",
+		GetHandler: handlers.GetMenusWeek,
+	},
 	{
 		Path:       "/menus/{MID}",
 		GetHandler: handlers.GetMenu,
@@ -89,6 +142,22 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		Path:        "/menus/{MID}/edit/name",
 		PostHandler: handlers.PostMenuEditName,
 	},
+	{
+		Path:        "/menus/{MID}/edit/slots/add",
+		PostHandler: handlers.PostMenuEditSlotsAdd,
+	},
+	{
+		Path:        "/menus/{MID}/edit/slots/{SPos}/delete",
+		PostHandler: handlers.PostMenuEditSlotDelete,
+	},
+	{
+		Path:        "/menus/{MID}/edit/slots/{SPos}/name",
+		PostHandler: handlers.PostMenuEditSlotName,
+	},
+	{
+		Path:        "/menus/{MID}/edit/start",
+		PostHandler: handlers.PostMenuEditStart,
+	},
 	{
 		Path:        "/menus/{MID}/delete",
 		PostHandler: handlers.PostMenuDelete,
@@ -97,25 +166,74 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		Path:        "/menus/{MID}/duplicate",
 		PostHandler: handlers.PostMenuDuplicate,
 	},
+	{
+		Path:        "/menus/{MID}/suggest",
+		GetHandler:  handlers.GetMenuSuggest,
+		PostHandler: handlers.PostMenuSuggest,
+	},
+	{
+		Path:        "/menus/{MID}/template",
+		PostHandler: handlers.PostMenuTemplate,
+	},
 
+	{
+		Path:        "/public_collections/{code}",
+		Unprotected: true,
+		GetHandler:  handlers.GetPublicCollection,
+		RateLimited: true,
+	},
+	{
+		Path:        "/public_collections/{code}/save",
+		PostHandler: handlers.PostPublicCollectionSave,
+	},
 	{
 		Path:        "/public_recipes/{code}",
 		Unprotected: true,
 		GetHandler:  handlers.GetPublicRecipe,
+		RateLimited: true,
 	},
 	{
 		Path:        "/public_recipes/{code}/save",
 		PostHandler: handlers.PostPublicRecipeSave,
 	},
+	{
+		Path:        "/public_users/{username}",
+		Unprotected: true,
+		GetHandler:  handlers.GetPublicProfile,
+	},
 	{
 		Path:       "/recipes",
 		GetHandler: handlers.GetRecipes,
 	},
+	{
+		Path:       "/recipes/cookable",
+		GetHandler: handlers.GetRecipesCookable,
+	},
 	{
 		Path:        "/recipes/new",
 		GetHandler:  handlers.GetRecipesNew,
 		PostHandler: handlers.PostRecipesNew,
 	},
+	{
+		Path:       "/recipes/collections",
+		GetHandler: handlers.GetRecipesCollections,
+	},
+	{
+		Path:        "/recipes/collections/publish",
+		PostHandler: handlers.PostRecipesCollectionsPublish,
+	},
+	{
+		Path:        "/recipes/collections/unpublish",
+		PostHandler: handlers.PostRecipesCollectionsUnpublish,
+	},
+	{
+		Path:       "/recipes/shares",
+		GetHandler: handlers.GetRecipesShares,
+	},
+	{
+		Path:        "/recipes/shares/{RID}/revoke",
+		PostHandler: handlers.PostRecipesSharesRevoke,
+	},
 	{
 		Path:       "/recipes/tags",
 		GetHandler: handlers.GetRecipesTags,
@@ -133,6 +251,32 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		Path:        "/recipes/{RID}/delete",
 		PostHandler: handlers.PostRecipeDelete,
 	},
+	{
+		Path:       "/recipes/{RID}/history",
+		GetHandler: handlers.GetRecipeHistory,
+	},
+	{
+		Path:       "/recipes/{RID}/history/{Rev}",
+		GetHandler: handlers.GetRecipeRevision,
+	},
+	{
+		Path:        "/recipes/{RID}/history/{Rev}/restore",
+		PostHandler: handlers.PostRecipeRevisionRestore,
+	},
+	{
+		Path:        "/recipes/{RID}/log",
+		GetHandler:  handlers.GetRecipeLog,
+		PostHandler: handlers.PostRecipeLog,
+	},
+	{
+		Path:        "/recipes/{RID}/log/{CID}/delete",
+		PostHandler: handlers.PostRecipeLogDelete,
+	},
+	{
+		Path:        "/recipes/{RID}/pull",
+		GetHandler:  handlers.GetRecipePull,
+		PostHandler: handlers.PostRecipePull,
+	},
 	{
 		Path:        "/recipes/{RID}/share",
 		GetHandler:  handlers.GetRecipeShare,
@@ -166,6 +310,11 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		PostHandler: handlers.PostEntryToggle,
 	},
 
+	{
+		Path:       "/search",
+		GetHandler: handlers.GetSearch,
+	},
+
 	{
 		Path:       "/side",
 		GetHandler: handlers.GetSide,
@@ -180,6 +329,15 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		GetHandler:  handlers.GetStorageNew,
 		PostHandler: handlers.PostStorageNew,
 	},
+	{
+		Path:        "/storage/shelflives",
+		GetHandler:  handlers.GetStorageShelfLives,
+		PostHandler: handlers.PostStorageShelfLives,
+	},
+	{
+		Path:        "/storage/shelflives/reset",
+		PostHandler: handlers.PostStorageShelfLivesReset,
+	},
 	{
 		Path:       "/storage/{SID}",
 		GetHandler: handlers.GetStorageSection,
@@ -212,6 +370,40 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		PostHandler: handlers.PostStorageArticleDelete,
 	},
 
+	{
+		Path:        "/calendar/{token}/menus.ics",
+		Unprotected: true,
+		GetHandler:  handlers.GetCalendarMenus,
+		RateLimited: true,
+	},
+	{
+		Path:        "/calendar/{token}/storage.ics",
+		Unprotected: true,
+		GetHandler:  handlers.GetCalendarStorage,
+		RateLimited: true,
+	},
+
+	{
+		Path:       "/user/2fa",
+		GetHandler: handlers.GetUserTOTP,
+	},
+	{
+		Path:        "/user/2fa/disable",
+		PostHandler: handlers.PostUserTOTPDisable,
+	},
+	{
+		Path:        "/user/2fa/enable",
+		PostHandler: handlers.PostUserTOTPEnable,
+	},
+	{
+		Path:        "/user/2fa/start",
+		PostHandler: handlers.PostUserTOTPStart,
+	},
+	{
+		Path:        "/user/calendar",
+		GetHandler:  handlers.GetUserCalendar,
+		PostHandler: handlers.PostUserCalendar,
+	},
 	{
 		Path:        "/user/change_email",
 		GetHandler:  handlers.GetUserChangeEmail,
@@ -254,11 +446,53 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		GetHandler:  handlers.GetUserDelete2,
 		PostHandler: handlers.PostUserDelete2,
 	},
+	{
+		Path:        "/user/diet",
+		GetHandler:  handlers.GetUserDiet,
+		PostHandler: handlers.PostUserDiet,
+	},
+	{
+		Path:       "/user/events",
+		GetHandler: handlers.GetUserEvents,
+	},
 	{
 		Path:        "/user/forgot_password",
 		Unprotected: true,
 		GetHandler:  handlers.GetForgotPassword,
 		PostHandler: handlers.PostForgotPassword,
+		Throttle:    &utils.Throttle{AccountField: "email", Email: true},
+	},
+	{
+		Path:        "/user/oidc",
+		Unprotected: true,
+		GetHandler:  handlers.GetUserOIDC,
+	},
+	{
+		Path:        "/user/oidc/callback",
+		Unprotected: true,
+		GetHandler:  handlers.GetUserOIDCCallback,
+	},
+	{
+		Path:        "/user/oidc/link",
+		GetHandler:  handlers.GetUserOIDCLink,
+		PostHandler: handlers.PostUserOIDCLink,
+	},
+	{
+		Path:       "/user/passkeys",
+		GetHandler: handlers.GetUserPasskeys,
+	},
+	{
+		Path:        "/user/passkeys/new",
+		PostHandler: handlers.PostUserPasskeysNew,
+	},
+	{
+		Path:        "/user/passkeys/{PKID}/delete",
+		PostHandler: handlers.PostUserPasskeyDelete,
+	},
+	{
+		Path:        "/user/profile",
+		GetHandler:  handlers.GetUserProfile,
+		PostHandler: handlers.PostUserProfile,
 	},
 	{
 		Path:        "/user/reset_password",
@@ -266,6 +500,18 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		GetHandler:  handlers.GetResetPassword,
 		PostHandler: handlers.PostResetPassword,
 	},
+	{
+		Path:       "/user/sessions",
+		GetHandler: handlers.GetUserSessions,
+	},
+	{
+		Path:        "/user/sessions/revoke",
+		PostHandler: handlers.PostUserSessionsRevoke,
+	},
+	{
+		Path:        "/user/sessions/{SID}/revoke",
+		PostHandler: handlers.PostUserSessionRevoke,
+	},
 	{
 		Path:       "/user/settings",
 		GetHandler: handlers.GetUserSettings,
@@ -275,16 +521,42 @@ var endpoints []utils.Endpoint = []utils.Endpoint{
 		Unprotected: true,
 		GetHandler:  handlers.GetUserSignIn,
 		PostHandler: handlers.PostUserSignIn,
+		Throttle:    &utils.Throttle{AccountField: "username"},
+	},
+	{
+		Path:        "/user/signin/2fa",
+		Unprotected: true,
+		GetHandler:  handlers.GetUserSignIn2FA,
+		PostHandler: handlers.PostUserSignIn2FA,
+		Throttle:    &utils.Throttle{PendingAccount: true},
+	},
+	{
+		Path:        "/user/signin/passkey",
+		Unprotected: true,
+		GetHandler:  handlers.GetUserSignInPasskey,
+		PostHandler: handlers.PostUserSignInPasskey,
 	},
 	{
 		Path:        "/user/signout",
 		Unprotected: true,
 		PostHandler: handlers.PostUserSignOut,
 	},
+	{
+		Path:        "/user/verify_email",
+		Unprotected: true,
+		GetHandler:  handlers.GetUserVerifyEmail,
+		PostHandler: handlers.PostUserVerifyEmail,
+	},
+	{
+		Path:        "/user/verify_email/resend",
+		PostHandler: handlers.PostUserVerifyEmailResend,
+		Throttle:    &utils.Throttle{Email: true},
+	},
 	{
 		Path:        "/user/signup",
 		Unprotected: true,
 		GetHandler:  handlers.GetUserSignUp,
 		PostHandler: handlers.PostUserSignUp,
+		Throttle:    &utils.Throttle{AccountField: "email"},
 	},
 }
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"strconv"
+
+	"cucinassistant/database"
+	"cucinassistant/email"
+	"cucinassistant/langs"
+	"cucinassistant/web/components"
+	"cucinassistant/web/utils"
+)
+
+// statsHistoryDays is the number of days shown in the stats trends
+const statsHistoryDays = 90
+
+func getUID(c *utils.Context) (int, error) {
+	return getID(c, "UID", database.ERR_USER_UNKNOWN)
+}
+
+func GetAdmin(c *utils.Context) (err error) {
+	var users []database.User
+
+	query := c.R.URL.Query().Get("q")
+	if users, err = database.SearchUsers(query); err == nil {
+		utils.RenderComponent(c, components.Admin(query, users))
+	}
+
+	return
+}
+
+func GetAdminAudit(c *utils.Context) (err error) {
+	var entries []database.AuditEntry
+
+	if entries, err = database.GetAuditLog(); err == nil {
+		utils.RenderComponent(c, components.AdminAudit(entries))
+	}
+
+	return
+}
+
+func GetAdminEvents(c *utils.Context) (err error) {
+	var events []database.Event
+
+	if events, err = database.GetAllEvents(); err == nil {
+		utils.RenderComponent(c, components.AdminEvents(events))
+	}
+
+	return
+}
+
+func GetAdminStats(c *utils.Context) (err error) {
+	var history []database.DailyStats
+
+	if history, err = database.GetStatsHistory(statsHistoryDays); err == nil {
+		utils.RenderComponent(c, components.AdminStats(database.GetStats(), history))
+	}
+
+	return
+}
+
+func GetAdminUser(c *utils.Context) (err error) {
+	var UID int
+	var user database.User
+	var events []database.Event
+
+	if UID, err = getUID(c); err == nil {
+		if user, err = database.GetUser("UID", UID); err == nil {
+			if events, err = user.GetEvents(); err == nil {
+				utils.RenderComponent(c, components.AdminUser(user, UID == c.U.UID, events))
+			}
+		}
+	}
+
+	return
+}
+
+func PostAdminUserDelete(c *utils.Context) (err error) {
+	var UID int
+	var user database.User
+
+	if UID, err = getUID(c); err == nil {
+		if user, err = database.GetUser("UID", UID); err == nil {
+			if err = c.U.DeleteUser(UID); err == nil {
+				utils.LogEvent(c, user, database.EVENT_DELETED)
+				go email.Goodbye.Write(&user, "").Send()
+				utils.ShowMessage(c, langs.STR_USER_DELETED, "/admin")
+			}
+		}
+	}
+
+	return
+}
+
+func PostAdminUserDisable(c *utils.Context) (err error) {
+	var UID int
+
+	if UID, err = getUID(c); err == nil {
+		if err = c.U.DisableUser(UID); err == nil {
+			utils.Redirect(c, "/admin/users/"+strconv.Itoa(UID))
+		}
+	}
+
+	return
+}
+
+func PostAdminUserEnable(c *utils.Context) (err error) {
+	var UID int
+
+	if UID, err = getUID(c); err == nil {
+		if err = c.U.EnableUser(UID); err == nil {
+			utils.Redirect(c, "/admin/users/"+strconv.Itoa(UID))
+		}
+	}
+
+	return
+}
+
+func PostAdminUserResendVerification(c *utils.Context) (err error) {
+	var UID int
+	var user database.User
+
+	if UID, err = getUID(c); err == nil {
+		if user, err = database.GetUser("UID", UID); err == nil {
+			if err = sendEmailVerification(&user); err == nil {
+				if err = c.U.LogAdminAction(database.ADMIN_RESEND_VERIFICATION, user); err == nil {
+					utils.ShowMessage(c, langs.STR_VERIFICATION_RESENT, "/admin/users/"+strconv.Itoa(UID))
+				}
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"github.com/gorilla/mux"
+	"strconv"
+	"strings"
+	"time"
+
+	"cucinassistant/configs"
+	"cucinassistant/database"
+	"cucinassistant/langs"
+	"cucinassistant/web/components"
+	"cucinassistant/web/utils"
+)
+
+// mealTime is the time in which a meal is eaten
+type mealTime struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// mealTimes contains the times of the predefined slots
+var mealTimes = map[string]mealTime{
+	database.SLOT_BREAKFAST: {8 * time.Hour, 9 * time.Hour},
+	database.SLOT_LUNCH:     {12*time.Hour + 30*time.Minute, 13*time.Hour + 30*time.Minute},
+	database.SLOT_SNACK:     {16*time.Hour + 30*time.Minute, 17 * time.Hour},
+	database.SLOT_DINNER:    {19*time.Hour + 30*time.Minute, 20*time.Hour + 30*time.Minute},
+}
+
+// anonymousSlots contains the slots assumed for the days of the menus
+// without slots, depending on how many meals they have
+var anonymousSlots = map[int][]string{
+	2: {database.SLOT_LUNCH, database.SLOT_DINNER},
+	3: {database.SLOT_BREAKFAST, database.SLOT_LUNCH, database.SLOT_DINNER},
+	4: {database.SLOT_BREAKFAST, database.SLOT_LUNCH, database.SLOT_SNACK, database.SLOT_DINNER},
+}
+
+// getCalendarUser returns the owner of the calendar token in the URL,
+// and sets the language of the context to the one of their emails
+func getCalendarUser(c *utils.Context) (database.User, error) {
+	u, err := database.GetCalendarUser(mux.Vars(c.R)["token"])
+	if err == nil && u.EmailLang != nil {
+		c.L = *u.EmailLang
+	}
+
+	return u, err
+}
+
+// eventUID returns the UID of a calendar event, made of a kind and some IDs
+func eventUID(kind string, IDs ...int) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(configs.BaseURL, "https://"), "http://")
+	for _, ID := range IDs {
+		kind += "-" + strconv.Itoa(ID)
+	}
+
+	return kind + "@" + host
+}
+
+// mealSummary returns a meal on a single line
+func mealSummary(meal string) string {
+	var lines []string
+	for _, line := range strings.Split(meal, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, ", ")
+}
+
+func GetCalendarMenus(c *utils.Context) (err error) {
+	var u database.User
+	var days []database.CalendarDay
+
+	if u, err = getCalendarUser(c); err == nil {
+		if days, err = u.Menus().GetCalendar(); err == nil {
+			timed := c.R.URL.Query().Get("timed") == "1"
+
+			var events []utils.Event
+			for _, day := range days {
+				description := day.Menu + " - " + day.Name
+				slots := day.Slots
+				if slots == nil {
+					slots = anonymousSlots[len(day.Meals)]
+				}
+
+				// Shows every meal of a known slot at its time, and
+				// collects the others in an all-day event
+				var meals []string
+				for i, meal := range day.Meals {
+					summary := mealSummary(meal)
+					if summary == "" {
+						continue
+					}
+
+					var slot string
+					if i < len(slots) {
+						slot = slots[i]
+					}
+
+					if t, found := mealTimes[slot]; timed && found {
+						events = append(events, utils.Event{
+							UID:         eventUID("meal", day.MID, day.Position, i),
+							Summary:     summary,
+							Description: description,
+							Start:       day.Date.Add(t.start),
+							End:         day.Date.Add(t.end),
+							Modified:    day.Updated,
+						})
+					} else {
+						meals = append(meals, summary)
+					}
+				}
+
+				if len(meals) > 0 {
+					events = append(events, utils.Event{
+						UID:         eventUID("day", day.MID, day.Position),
+						Summary:     strings.Join(meals, " / "),
+						Description: description + "\n\n" + strings.Join(meals, "\n"),
+						Start:       *day.Date,
+						AllDay:      true,
+						Modified:    day.Updated,
+					})
+				}
+			}
+
+			ctx := langs.Get(&c.L).Ctx()
+			utils.RenderCalendar(c, langs.Translate(ctx, langs.STR_MENUS), events)
+		}
+	}
+
+	return
+}
+
+func GetCalendarStorage(c *utils.Context) (err error) {
+	var u database.User
+	var articles []database.CalendarArticle
+
+	if u, err = getCalendarUser(c); err == nil {
+		if articles, err = u.Storage().GetCalendar(); err == nil {
+			ctx := langs.Get(&c.L).Ctx()
+
+			var events []utils.Event
+			for _, article := range articles {
+				events = append(events, utils.Event{
+					UID:      eventUID("article", article.AID),
+					Summary:  langs.TranslateArg(ctx, langs.STR_CALENDAR_EXPIRES, article.Name),
+					Start:    *article.Expires(),
+					AllDay:   true,
+					Modified: article.Updated,
+				})
+			}
+
+			utils.RenderCalendar(c, langs.Translate(ctx, langs.STR_CALENDAR_EXPIRATIONS), events)
+		}
+	}
+
+	return
+}
+
+func GetUserCalendar(c *utils.Context) (err error) {
+	var token string
+
+	if token, err = c.U.GetCalendarToken(); err == nil {
+		utils.RenderComponent(c, components.UserCalendar(token, configs.BaseURL))
+	}
+
+	return
+}
+
+func PostUserCalendar(c *utils.Context) (err error) {
+	if c.R.FormValue("action") == "disable" {
+		err = c.U.DisableCalendar()
+	} else {
+		_, err = c.U.EnableCalendar()
+	}
+
+	if err == nil {
+		utils.Redirect(c, "/user/calendar")
+	}
+
+	return
+}
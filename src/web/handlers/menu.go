@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"math/rand"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"cucinassistant/database"
+	"cucinassistant/langs"
 	"cucinassistant/web/components"
 	"cucinassistant/web/utils"
 )
@@ -27,6 +30,89 @@ func getDPos(c *utils.Context) (int, int, error) {
 	}
 }
 
+// getStart parses the start date of a menu, which is nil if empty
+func getStart(c *utils.Context) (*time.Time, error) {
+	value := strings.TrimSpace(c.R.FormValue("start"))
+	if value == "" {
+		return nil, nil
+	}
+
+	start, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, database.ERR_MENU_START_INVALID
+	}
+
+	return &start, nil
+}
+
+// getSuggestOptions parses the options of the meals suggestions:
+// the constraints are written one per line, as "TAG MIN" or "TAG MIN-MAX"
+func getSuggestOptions(c *utils.Context) (database.SuggestOptions, error) {
+	options := database.SuggestOptions{ExpiringDays: 7}
+
+	var err error
+	if options.Seed, err = strconv.ParseInt(strings.TrimSpace(c.R.FormValue("seed")), 10, 64); err != nil {
+		return options, database.ERR_SUGGEST_INVALID
+	}
+	if value := strings.TrimSpace(c.R.FormValue("norepeat")); value != "" {
+		if options.NoRepeatDays, err = strconv.Atoi(value); err != nil {
+			return options, database.ERR_SUGGEST_INVALID
+		}
+	}
+
+	for _, line := range strings.Split(c.R.FormValue("constraints"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		} else if len(fields) < 2 {
+			return options, database.ERR_SUGGEST_INVALID
+		}
+
+		// The last field contains the limits, the others the tag
+		var constraint database.TagConstraint
+		constraint.Tag = strings.Join(fields[:len(fields)-1], " ")
+		min, max, found := strings.Cut(fields[len(fields)-1], "-")
+		if constraint.Min, err = strconv.Atoi(min); err != nil {
+			return options, database.ERR_SUGGEST_INVALID
+		}
+		if found {
+			if constraint.Max, err = strconv.Atoi(max); err != nil {
+				return options, database.ERR_SUGGEST_INVALID
+			}
+		}
+
+		options.Constraints = append(options.Constraints, constraint)
+	}
+
+	return options, nil
+}
+
+// getAllergenWarnings returns, for every recipe in the menu that contains
+// some allergens the user must avoid, those allergens (indexed by the
+// recipe's database.MealKey)
+func getAllergenWarnings(c *utils.Context, MID int) (map[string][]database.Allergen, error) {
+	warnings := make(map[string][]database.Allergen)
+
+	profile, err := c.U.GetDietaryProfile()
+	if err != nil {
+		return warnings, err
+	}
+
+	recipes, err := c.U.Menus().GetRecipes(MID)
+	if err != nil {
+		return warnings, err
+	}
+
+	avoided := profile.Avoided()
+	for key, recipe := range recipes {
+		if found := langs.FilterAllergens(c.L, recipe.Ingredients, avoided); len(found) > 0 {
+			warnings[key] = found
+		}
+	}
+
+	return warnings, nil
+}
+
 func PostMenuAddDay(c *utils.Context) (err error) {
 	var MID int
 
@@ -52,19 +138,81 @@ func GetMenus(c *utils.Context) (err error) {
 }
 
 func GetMenusNew(c *utils.Context) (err error) {
-	utils.RenderComponent(c, components.MenusNew())
+	var templates []database.MenuTemplate
+
+	if templates, err = c.U.Menus().GetTemplates(); err == nil {
+		utils.RenderComponent(c, components.MenusNew(templates))
+	}
+
 	return
 }
 
 func PostMenusNew(c *utils.Context) (err error) {
 	var MID int
+	var start *time.Time
+	c.R.ParseForm()
 
 	name := c.R.FormValue("name")
-	meals, _ := strconv.Atoi(c.R.FormValue("meals"))
 	days := strings.Split(c.R.FormValue("days"), "\n")
 
-	if MID, err = c.U.Menus().New(name, days, meals); err == nil {
-		utils.Redirect(c, "/menus/"+strconv.Itoa(MID)+"/edit")
+	if start, err = getStart(c); err == nil {
+		if TID, _ := strconv.Atoi(c.R.FormValue("template")); TID != 0 {
+			MID, err = c.U.Menus().NewFromTemplate(TID, name, days)
+		} else {
+			slots := c.R.PostForm["slot"]
+			for _, slot := range strings.Split(c.R.FormValue("custom-slots"), "\n") {
+				if strings.TrimSpace(slot) != "" {
+					slots = append(slots, slot)
+				}
+			}
+
+			MID, err = c.U.Menus().NewWithSlots(name, days, slots)
+		}
+
+		if err == nil {
+			if err = c.U.Menus().SetStart(MID, start); err == nil {
+				utils.Redirect(c, "/menus/"+strconv.Itoa(MID)+"/edit")
+			}
+		}
+	}
+
+	return
+}
+
+func GetMenusTemplates(c *utils.Context) (err error) {
+	var templates []database.MenuTemplate
+
+	if templates, err = c.U.Menus().GetTemplates(); err == nil {
+		utils.RenderComponent(c, components.MenusTemplates(templates))
+	}
+
+	return
+}
+
+func PostMenusTemplateDelete(c *utils.Context) (err error) {
+	var TID int
+
+	if TID, err = getID(c, "TID", database.ERR_TEMPLATE_NOT_FOUND); err == nil {
+		if err = c.U.Menus().DeleteTemplate(TID); err == nil {
+			utils.Redirect(c, "/menus/templates")
+		}
+	}
+
+	return
+}
+
+func GetMenusWeek(c *utils.Context) (err error) {
+	var week database.Week
+
+	date := time.Now()
+	if value := c.R.URL.Query().Get("date"); value != "" {
+		if date, err = time.Parse(time.DateOnly, value); err != nil {
+			return database.ERR_MENU_START_INVALID
+		}
+	}
+
+	if week, err = c.U.Menus().GetWeek(date); err == nil {
+		utils.RenderComponent(c, components.MenusWeek(week))
 	}
 
 	return
@@ -73,10 +221,13 @@ func PostMenusNew(c *utils.Context) (err error) {
 func GetMenu(c *utils.Context) (err error) {
 	var MID int
 	var menu database.Menu
+	var nutrition database.MenuNutrition
 
 	if MID, err = getMID(c); err == nil {
 		if menu, err = c.U.Menus().GetOne(MID); err == nil {
-			utils.RenderComponent(c, components.Menu(menu))
+			if nutrition, err = c.U.Menus().GetNutrition(MID); err == nil {
+				utils.RenderComponent(c, components.Menu(menu, nutrition))
+			}
 		}
 	}
 
@@ -164,7 +315,13 @@ func PostMenuEditDayMealsRemove(c *utils.Context) (err error) {
 					return
 				}
 
-				day.Meals = append(day.Meals[:MPos], day.Meals[MPos+1:]...)
+				// The meals of the slots are only emptied, so that
+				// the following ones stay in their slots
+				if MPos < len(day.Slots) {
+					day.Meals[MPos] = ""
+				} else {
+					day.Meals = append(day.Meals[:MPos], day.Meals[MPos+1:]...)
+				}
 
 				if err = c.U.Menus().SetDayMeals(MID, DPos, day.Meals); err == nil {
 					utils.Redirect(c, "/menus/"+strconv.Itoa(MID)+"/edit/meals")
@@ -217,10 +374,13 @@ func PostMenuEditDayName(c *utils.Context) (err error) {
 func GetMenuEditMeals(c *utils.Context) (err error) {
 	var MID int
 	var menu database.Menu
+	var warnings map[string][]database.Allergen
 
 	if MID, err = getMID(c); err == nil {
 		if menu, err = c.U.Menus().GetOne(MID); err == nil {
-			utils.RenderComponent(c, components.MenuEditMeals(menu))
+			if warnings, err = getAllergenWarnings(c, MID); err == nil {
+				utils.RenderComponent(c, components.MenuEditMeals(menu, warnings))
+			}
 		}
 	}
 
@@ -239,6 +399,61 @@ func PostMenuEditName(c *utils.Context) (err error) {
 	return
 }
 
+func PostMenuEditSlotsAdd(c *utils.Context) (err error) {
+	var MID int
+
+	if MID, err = getMID(c); err == nil {
+		if err = c.U.Menus().AddSlot(MID, c.R.FormValue("name")); err == nil {
+			utils.Redirect(c, "/menus/"+strconv.Itoa(MID)+"/edit")
+		}
+	}
+
+	return
+}
+
+func PostMenuEditSlotDelete(c *utils.Context) (err error) {
+	var MID, SPos int
+
+	if MID, err = getMID(c); err == nil {
+		if SPos, err = getID(c, "SPos", database.ERR_SLOT_NOT_FOUND); err == nil {
+			if err = c.U.Menus().RemoveSlot(MID, SPos); err == nil {
+				utils.Redirect(c, "/menus/"+strconv.Itoa(MID)+"/edit")
+			}
+		}
+	}
+
+	return
+}
+
+func PostMenuEditSlotName(c *utils.Context) (err error) {
+	var MID, SPos int
+
+	if MID, err = getMID(c); err == nil {
+		if SPos, err = getID(c, "SPos", database.ERR_SLOT_NOT_FOUND); err == nil {
+			if err = c.U.Menus().SetSlotName(MID, SPos, c.R.FormValue("name")); err == nil {
+				utils.Redirect(c, "/menus/"+strconv.Itoa(MID)+"/edit")
+			}
+		}
+	}
+
+	return
+}
+
+func PostMenuEditStart(c *utils.Context) (err error) {
+	var MID int
+	var start *time.Time
+
+	if MID, err = getMID(c); err == nil {
+		if start, err = getStart(c); err == nil {
+			if err = c.U.Menus().SetStart(MID, start); err == nil {
+				utils.Redirect(c, "/menus/"+strconv.Itoa(MID)+"/edit")
+			}
+		}
+	}
+
+	return
+}
+
 func PostMenuDelete(c *utils.Context) (err error) {
 	var MID int
 
@@ -251,6 +466,50 @@ func PostMenuDelete(c *utils.Context) (err error) {
 	return
 }
 
+func PostMenuTemplate(c *utils.Context) (err error) {
+	var MID int
+
+	if MID, err = getMID(c); err == nil {
+		if _, err = c.U.Menus().SaveTemplate(MID, c.R.FormValue("name")); err == nil {
+			utils.ShowMessage(c, langs.STR_TEMPLATE_SAVED, "/menus/"+strconv.Itoa(MID)+"/edit")
+		}
+	}
+
+	return
+}
+
+func GetMenuSuggest(c *utils.Context) (err error) {
+	var MID int
+	var menu database.Menu
+
+	if MID, err = getMID(c); err == nil {
+		if menu, err = c.U.Menus().GetOne(MID); err == nil {
+			utils.RenderComponent(c, components.MenuSuggest(menu, rand.Int63n(1000000)))
+		}
+	}
+
+	return
+}
+
+func PostMenuSuggest(c *utils.Context) (err error) {
+	var MID, filled int
+	var options database.SuggestOptions
+
+	if MID, err = getMID(c); err == nil {
+		if options, err = getSuggestOptions(c); err == nil {
+			if filled, err = c.U.Menus().SuggestMeals(MID, options); err == nil {
+				if filled == 0 {
+					utils.ShowMessage(c, langs.STR_NO_MEALS_SUGGESTED, "/menus/"+strconv.Itoa(MID))
+				} else {
+					utils.Redirect(c, "/menus/"+strconv.Itoa(MID))
+				}
+			}
+		}
+	}
+
+	return
+}
+
 func PostMenuDuplicate(c *utils.Context) (err error) {
 	var MID int
 
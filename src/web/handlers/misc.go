@@ -5,6 +5,7 @@ import (
 	"github.com/gorilla/mux"
 	"net/http"
 	"strconv"
+	"time"
 
 	"cucinassistant/configs"
 	"cucinassistant/database"
@@ -23,7 +24,12 @@ func getID(c *utils.Context, name string, notFound error) (int, error) {
 }
 
 func GetIndex(c *utils.Context) (err error) {
-	utils.RenderComponent(c, components.Index(c.U.Username))
+	var week database.Week
+
+	if week, err = c.U.Menus().GetWeek(time.Now()); err == nil {
+		utils.RenderComponent(c, components.Index(c.U.Username, week))
+	}
+
 	return
 }
 
@@ -56,6 +62,17 @@ func PostLang(c *utils.Context) (err error) {
 	return
 }
 
+func GetSearch(c *utils.Context) (err error) {
+	var results database.SearchResults
+
+	query := c.R.URL.Query().Get("q")
+	if results, err = c.U.Search(query, langs.Get(&c.L).SearchConfig); err == nil {
+		utils.RenderComponent(c, components.Search(query, results))
+	}
+
+	return
+}
+
 func GetSide(c *utils.Context) (err error) {
 	var menus []database.Menu
 	var sections []database.Section
@@ -73,8 +90,3 @@ func GetSide(c *utils.Context) (err error) {
 	utils.RenderSide(c, components.Side(menus, sections, recipes))
 	return
 }
-
-func GetStats(c *utils.Context) (err error) {
-	utils.RenderComponent(c, components.Stats(database.GetStats()))
-	return
-}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"cucinassistant/configs"
+	"cucinassistant/database"
+	"cucinassistant/email"
+	"cucinassistant/langs"
+	"cucinassistant/oidc"
+	"cucinassistant/web/components"
+	"cucinassistant/web/utils"
+)
+
+// startOIDC sends the user to the OpenID Connect provider.
+// If UID is not 0, the identity will be linked to that user.
+func startOIDC(c *utils.Context, UID int) {
+	if !oidc.Enabled() {
+		utils.ShowError(c, langs.STR_PAGE_NOT_FOUND, "/", http.StatusNotFound)
+		return
+	}
+
+	p, err := oidc.Get()
+	if err != nil {
+		utils.ShowError(c, langs.STR_SSO_FAILED, "/user/signin", http.StatusBadGateway)
+		return
+	}
+
+	state, nonce, verifier := oidc.RandomString(), oidc.RandomString(), oidc.RandomString()
+	utils.SaveOIDCState(c, state, nonce, verifier, UID)
+	utils.Redirect(c, p.AuthURL(state, nonce, verifier))
+}
+
+func GetUserOIDC(c *utils.Context) (err error) {
+	startOIDC(c, 0)
+	return
+}
+
+func GetUserOIDCLink(c *utils.Context) (err error) {
+	var linked bool
+
+	if !oidc.Enabled() {
+		utils.ShowError(c, langs.STR_PAGE_NOT_FOUND, "/", http.StatusNotFound)
+		return
+	}
+
+	if linked, err = c.U.HasIdentity(configs.OIDCIssuer); err == nil {
+		utils.RenderComponent(c, components.UserLinkIdentity(configs.OIDCName, linked))
+	}
+
+	return
+}
+
+func PostUserOIDCLink(c *utils.Context) (err error) {
+	startOIDC(c, c.U.UID)
+	return
+}
+
+func GetUserOIDCCallback(c *utils.Context) (err error) {
+	var p *oidc.Provider
+	var claims oidc.Claims
+
+	// Ensures the callback belongs to the sign in that has been started
+	state, nonce, verifier, UID := popOIDCState(c)
+	if state == "" {
+		return
+	}
+
+	// Reads the identity
+	if p, err = oidc.Get(); err == nil {
+		claims, err = p.Exchange(c.R.URL.Query().Get("code"), verifier, nonce, time.Now())
+	}
+	if err != nil {
+		utils.ShowError(c, langs.STR_SSO_FAILED, "/user/signin", http.StatusBadRequest)
+		return nil
+	}
+
+	// Links the identity to the user
+	if UID != 0 {
+		if err = (database.User{UID: UID}).LinkIdentity(configs.OIDCIssuer, claims.Subject); err == nil {
+			utils.LogEvent(c, database.User{UID: UID}, database.EVENT_IDENTITY_LINKED)
+			utils.ShowMessage(c, langs.STR_SETTINGS_SAVED, "/user/oidc/link")
+		}
+		return
+	}
+
+	// Or signs the user in
+	var user database.User
+	var created bool
+	if user, created, err = database.SignInWithIdentity(configs.OIDCIssuer, claims.Subject, claims.Email,
+		claims.EmailVerified, claims.PreferredUsername); err == nil {
+		if created {
+			user.ChangeEmailLang(c.L)
+			go email.Welcome.Write(&user, "").Send()
+			if !user.EmailVerified {
+				err = sendEmailVerification(&user)
+			}
+		}
+
+		if err == nil {
+			err = completeSignIn(c, user, langs.STR_NONE)
+		}
+	} else if err == database.ERR_USER_MAIL_UNAVAIL {
+		// The account has to be linked by hand, after signing in
+		utils.ShowError(c, langs.STR_SSO_LINK_REQUIRED, "/user/signin", http.StatusConflict)
+		return nil
+	}
+
+	return
+}
+
+// popOIDCState returns the values saved when the sign in has been
+// started, after making sure the provider has sent back the same state.
+// Otherwise, it shows an error and returns an empty state.
+func popOIDCState(c *utils.Context) (state string, nonce string, verifier string, UID int) {
+	state, nonce, verifier, UID = utils.PopOIDCState(c)
+
+	query := c.R.URL.Query()
+	if state == "" || query.Get("state") != state || query.Get("error") != "" || query.Get("code") == "" {
+		utils.ShowError(c, langs.STR_SSO_FAILED, "/user/signin", http.StatusBadRequest)
+		return "", "", "", 0
+	}
+
+	return
+}
@@ -4,9 +4,11 @@ import (
 	"github.com/gorilla/mux"
 	"strconv"
 	"strings"
+	"time"
 
 	"cucinassistant/configs"
 	"cucinassistant/database"
+	"cucinassistant/langs"
 	"cucinassistant/web/components"
 	"cucinassistant/web/utils"
 )
@@ -15,12 +17,67 @@ func getRID(c *utils.Context) (int, error) {
 	return getID(c, "RID", database.ERR_RECIPE_NOT_FOUND)
 }
 
+func getCooking(c *utils.Context) (int, int, error) {
+	RID, errR := getRID(c)
+	CID, errC := getID(c, "CID", database.ERR_COOKING_NOT_FOUND)
+
+	if errR != nil {
+		return RID, CID, errR
+	} else if errC != nil {
+		return RID, CID, errC
+	} else {
+		return RID, CID, nil
+	}
+}
+
+func getRevision(c *utils.Context) (int, int, error) {
+	RID, errR := getRID(c)
+	number, errN := getID(c, "Rev", database.ERR_REVISION_NOT_FOUND)
+
+	if errR != nil {
+		return RID, number, errR
+	} else if errN != nil {
+		return RID, number, errN
+	} else {
+		return RID, number, nil
+	}
+}
+
+// filterSuitable removes the recipes that contain some allergens
+// the user must avoid
+func filterSuitable(c *utils.Context, recipes []database.Recipe) ([]database.Recipe, error) {
+	var suitable []database.Recipe
+
+	profile, err := c.U.GetDietaryProfile()
+	if err != nil {
+		return suitable, err
+	}
+
+	ingredients, err := c.U.Recipes().GetAllIngredients()
+	if err != nil {
+		return suitable, err
+	}
+
+	avoided := profile.Avoided()
+	for _, recipe := range recipes {
+		if len(langs.FilterAllergens(c.L, ingredients[recipe.RID], avoided)) == 0 {
+			suitable = append(suitable, recipe)
+		}
+	}
+
+	return suitable, nil
+}
+
 func GetPublicRecipe(c *utils.Context) (err error) {
 	var recipe database.Recipe
+	var nutrition database.RecipeNutrition
 
 	code := mux.Vars(c.R)["code"]
 	if recipe, err = database.GetPublicRecipe(code); err == nil {
-		utils.RenderComponent(c, components.Recipe(recipe, configs.BaseURL))
+		if nutrition, err = database.GetNutrition(recipe); err == nil {
+			allergens := langs.DetectAllergens(c.L, recipe.Ingredients)
+			utils.RenderComponent(c, components.Recipe(recipe, nil, nutrition, allergens, nil, configs.BaseURL))
+		}
 	}
 
 	return
@@ -36,11 +93,57 @@ func PostPublicRecipeSave(c *utils.Context) (err error) {
 	return
 }
 
+func GetPublicCollection(c *utils.Context) (err error) {
+	var collection database.Collection
+
+	code := mux.Vars(c.R)["code"]
+	if collection, err = database.GetPublicCollection(code); err == nil {
+		utils.RenderComponent(c, components.PublicCollection(collection, configs.BaseURL))
+	}
+
+	return
+}
+
+func PostPublicCollectionSave(c *utils.Context) (err error) {
+	var skipped []string
+
+	if _, skipped, err = c.U.Recipes().SaveCollection(mux.Vars(c.R)["code"]); err == nil {
+		if len(skipped) > 0 {
+			utils.ShowMessageArg(c, langs.STR_COLLECTION_SKIPPED, strings.Join(skipped, ", "), "/recipes")
+		} else {
+			utils.ShowMessage(c, langs.STR_COLLECTION_SAVED, "/recipes")
+		}
+	}
+
+	return
+}
+
 func GetRecipes(c *utils.Context) (err error) {
 	var recipes []database.Recipe
+	var forgotten []database.ForgottenRecipe
+
+	suitable := c.R.FormValue("suitable") == "1"
 
 	if recipes, err = c.U.Recipes().GetAll(); err == nil {
-		utils.RenderComponent(c, components.Recipes(recipes))
+		if suitable {
+			recipes, err = filterSuitable(c, recipes)
+		}
+
+		if err == nil {
+			if forgotten, err = c.U.Recipes().GetForgotten(30, 5); err == nil {
+				utils.RenderComponent(c, components.Recipes(recipes, forgotten, suitable))
+			}
+		}
+	}
+
+	return
+}
+
+func GetRecipesCookable(c *utils.Context) (err error) {
+	var recipes []database.CookableRecipe
+
+	if recipes, err = c.U.Recipes().GetCookable(time.Now(), 3); err == nil {
+		utils.RenderComponent(c, components.RecipesCookable(recipes))
 	}
 
 	return
@@ -71,13 +174,79 @@ func GetRecipesTags(c *utils.Context) (err error) {
 	return
 }
 
+func GetRecipesCollections(c *utils.Context) (err error) {
+	var tags []database.Tag
+	var collections []database.Collection
+
+	if tags, err = c.U.Recipes().GetTags(); err == nil {
+		if collections, err = c.U.Collections().GetAll(); err == nil {
+			utils.RenderComponent(c, components.RecipesCollections(tags, collections, configs.BaseURL))
+		}
+	}
+
+	return
+}
+
+func PostRecipesCollectionsPublish(c *utils.Context) (err error) {
+	if _, err = c.U.Collections().Publish(c.R.FormValue("tag")); err == nil {
+		utils.Redirect(c, "/recipes/collections")
+	}
+
+	return
+}
+
+func PostRecipesCollectionsUnpublish(c *utils.Context) (err error) {
+	if err = c.U.Collections().Unpublish(c.R.FormValue("tag")); err == nil {
+		utils.Redirect(c, "/recipes/collections")
+	}
+
+	return
+}
+
+func GetRecipesShares(c *utils.Context) (err error) {
+	var shares []database.Share
+
+	if shares, err = c.U.Recipes().GetShares(); err == nil {
+		utils.RenderComponent(c, components.RecipesShares(shares, configs.BaseURL))
+	}
+
+	return
+}
+
+func PostRecipesSharesRevoke(c *utils.Context) (err error) {
+	var RID int
+
+	if RID, err = getRID(c); err == nil {
+		if err = c.U.Recipes().Unshare(RID); err == nil {
+			utils.Redirect(c, "/recipes/shares")
+		}
+	}
+
+	return
+}
+
 func GetRecipe(c *utils.Context) (err error) {
 	var RID int
 	var recipe database.Recipe
+	var nutrition database.RecipeNutrition
+	var profile database.DietaryProfile
 
 	if RID, err = getRID(c); err == nil {
 		if recipe, err = c.U.Recipes().GetOne(RID); err == nil {
-			utils.RenderComponent(c, components.Recipe(recipe, configs.BaseURL))
+			if nutrition, err = database.GetNutrition(recipe); err == nil {
+				if profile, err = c.U.GetDietaryProfile(); err == nil {
+					allergens := langs.DetectAllergens(c.L, recipe.Ingredients)
+					unsuitable := langs.FilterAllergens(c.L, recipe.Ingredients, profile.Avoided())
+
+					if fork, errF := c.U.Recipes().GetFork(RID); errF == nil {
+						utils.RenderComponent(c, components.Recipe(recipe, &fork, nutrition, allergens, unsuitable, configs.BaseURL))
+					} else if errF == database.ERR_FORK_NOT_FOUND {
+						utils.RenderComponent(c, components.Recipe(recipe, nil, nutrition, allergens, unsuitable, configs.BaseURL))
+					} else {
+						err = errF
+					}
+				}
+			}
 		}
 	}
 
@@ -102,11 +271,11 @@ func PostRecipeEdit(c *utils.Context) (err error) {
 
 	if RID, err = getRID(c); err == nil {
 		tags := strings.Split(strings.ToUpper(c.R.FormValue("tags")), "\n")
-		stars, _ := strconv.ParseFloat(c.R.FormValue("stars"), 32)
+		servings, _ := strconv.Atoi(c.R.FormValue("servings"))
 		newData := database.Recipe{
 			Name:        c.R.FormValue("name"),
 			Tags:        tags,
-			Stars:       int(stars * 2),
+			Servings:    servings,
 			Ingredients: c.R.FormValue("ingredients"),
 			Directions:  c.R.FormValue("directions"),
 			Notes:       c.R.FormValue("notes"),
@@ -132,13 +301,140 @@ func PostRecipeDelete(c *utils.Context) (err error) {
 	return
 }
 
+func GetRecipeHistory(c *utils.Context) (err error) {
+	var RID int
+	var recipe database.Recipe
+	var revisions []database.Revision
+
+	if RID, err = getRID(c); err == nil {
+		if recipe, err = c.U.Recipes().GetOne(RID); err == nil {
+			if revisions, err = c.U.Recipes().GetRevisions(RID); err == nil {
+				utils.RenderComponent(c, components.RecipeHistory(recipe, revisions))
+			}
+		}
+	}
+
+	return
+}
+
+func GetRecipeRevision(c *utils.Context) (err error) {
+	var RID, number int
+	var diff database.RevisionDiff
+
+	if RID, number, err = getRevision(c); err == nil {
+		if diff, err = c.U.Recipes().CompareRevision(RID, number); err == nil {
+			utils.RenderComponent(c, components.RecipeRevision(diff))
+		}
+	}
+
+	return
+}
+
+func PostRecipeRevisionRestore(c *utils.Context) (err error) {
+	var RID, number int
+
+	if RID, number, err = getRevision(c); err == nil {
+		if err = c.U.Recipes().RestoreRevision(RID, number); err == nil {
+			utils.Redirect(c, "/recipes/"+strconv.Itoa(RID))
+		}
+	}
+
+	return
+}
+
+func GetRecipeLog(c *utils.Context) (err error) {
+	var RID int
+	var recipe database.Recipe
+	var log []database.Cooking
+
+	if RID, err = getRID(c); err == nil {
+		if recipe, err = c.U.Recipes().GetOne(RID); err == nil {
+			if log, err = c.U.Recipes().GetCookingLog(RID); err == nil {
+				utils.RenderComponent(c, components.RecipeLog(recipe, log, c.U.Username))
+			}
+		}
+	}
+
+	return
+}
+
+func PostRecipeLog(c *utils.Context) (err error) {
+	var RID int
+
+	var cooking database.Cooking
+
+	if RID, err = getRID(c); err == nil {
+		cooking, err = database.StringCooking{
+			Date:     c.R.FormValue("date"),
+			Cook:     c.R.FormValue("cook"),
+			Stars:    c.R.FormValue("stars"),
+			Comments: c.R.FormValue("comments"),
+		}.Parse()
+		if err != nil {
+			return
+		}
+
+		if _, err = c.U.Recipes().AddCooking(RID, cooking); err == nil {
+			utils.Redirect(c, "/recipes/"+strconv.Itoa(RID)+"/log")
+		}
+	}
+
+	return
+}
+
+func PostRecipeLogDelete(c *utils.Context) (err error) {
+	var RID, CID int
+
+	if RID, CID, err = getCooking(c); err == nil {
+		if err = c.U.Recipes().DeleteCooking(RID, CID); err == nil {
+			utils.Redirect(c, "/recipes/"+strconv.Itoa(RID)+"/log")
+		}
+	}
+
+	return
+}
+
+func GetRecipePull(c *utils.Context) (err error) {
+	var RID int
+	var update database.ForkUpdate
+
+	if RID, err = getRID(c); err == nil {
+		if update, err = c.U.Recipes().GetForkUpdate(RID); err == nil {
+			utils.RenderComponent(c, components.RecipePull(update))
+		}
+	}
+
+	return
+}
+
+func PostRecipePull(c *utils.Context) (err error) {
+	var RID int
+
+	if RID, err = getRID(c); err == nil {
+		ingredients := c.R.FormValue("ingredients")
+		directions := c.R.FormValue("directions")
+		version := c.R.FormValue("version")
+		if err = c.U.Recipes().PullFork(RID, version, ingredients, directions); err == nil {
+			utils.Redirect(c, "/recipes/"+strconv.Itoa(RID))
+		}
+	}
+
+	return
+}
+
 func GetRecipeShare(c *utils.Context) (err error) {
 	var RID int
 	var recipe database.Recipe
 
 	if RID, err = getRID(c); err == nil {
 		if recipe, err = c.U.Recipes().GetOne(RID); err == nil {
-			utils.RenderComponent(c, components.RecipeShare(recipe, configs.BaseURL))
+			if share, errS := c.U.Recipes().GetShare(RID); errS == nil {
+				utils.RenderComponent(c, components.RecipeShare(recipe, &share, configs.BaseURL))
+			} else if errS == database.ERR_SHARE_NOT_FOUND {
+				utils.RenderComponent(c, components.RecipeShare(recipe, nil, configs.BaseURL))
+			} else {
+				err = errS
+			}
 		}
 	}
 
@@ -147,9 +443,27 @@ func GetRecipeShare(c *utils.Context) (err error) {
 
 func PostRecipeShare(c *utils.Context) (err error) {
 	var RID int
+	var expiration *time.Time
+	var maxViews *int
 
 	if RID, err = getRID(c); err == nil {
-		if _, err = c.U.Recipes().Share(RID); err == nil {
+		if value := c.R.FormValue("expiration"); value != "" {
+			if parsed, errP := time.Parse(time.DateOnly, value); errP == nil {
+				expiration = &parsed
+			} else {
+				return database.ERR_SHARE_LIMITS_INVALID
+			}
+		}
+
+		if value := c.R.FormValue("max-views"); value != "" {
+			if parsed, errP := strconv.Atoi(value); errP == nil {
+				maxViews = &parsed
+			} else {
+				return database.ERR_SHARE_LIMITS_INVALID
+			}
+		}
+
+		if _, err = c.U.Recipes().ShareLimited(RID, expiration, maxViews); err == nil {
 			utils.Redirect(c, "/recipes/"+strconv.Itoa(RID)+"/share")
 		}
 	}
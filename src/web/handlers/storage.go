@@ -28,26 +28,108 @@ func getSID(c *utils.Context) (int, error) {
 	return getID(c, "SID", database.ERR_SECTION_NOT_FOUND)
 }
 
+// getParent parses the parent section, which is 0 if empty
+func getParent(c *utils.Context) (int, error) {
+	value := c.R.FormValue("parent")
+	if value == "" {
+		return 0, nil
+	}
+
+	parent, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, database.ERR_SECTION_NOT_FOUND
+	}
+
+	return parent, nil
+}
+
+// getChildren returns the summaries of the subsections of a section
+func getChildren(summaries []database.SectionSummary, SID int) []database.SectionSummary {
+	var children []database.SectionSummary
+	for _, summary := range summaries {
+		if summary.Parent == SID {
+			children = append(children, summary)
+		}
+	}
+
+	return children
+}
+
 func GetStorage(c *utils.Context) (err error) {
-	var list []database.Section
+	var summaries []database.SectionSummary
 
-	if list, err = c.U.Storage().GetSections(); err == nil {
-		utils.RenderComponent(c, components.Storage(list))
+	if summaries, err = c.U.Storage().GetSummaries(); err == nil {
+		utils.RenderComponent(c, components.Storage(getChildren(summaries, 0)))
 	}
 
 	return
 }
 
 func GetStorageNew(c *utils.Context) (err error) {
-	utils.RenderComponent(c, components.StorageNew())
+	var parent int
+	var path []database.Section
+
+	if parent, err = getParent(c); err == nil {
+		if parent != 0 {
+			path, err = c.U.Storage().GetPath(parent)
+		}
+
+		if err == nil {
+			utils.RenderComponent(c, components.StorageNew(path))
+		}
+	}
+
 	return
 }
 
 func PostStorageNew(c *utils.Context) (err error) {
-	var SID int
+	var parent, SID int
+
+	if parent, err = getParent(c); err == nil {
+		if SID, err = c.U.Storage().NewSubsection(parent, c.R.FormValue("name")); err == nil {
+			utils.Redirect(c, "/storage/"+strconv.Itoa(SID))
+		}
+	}
+
+	return
+}
 
-	if SID, err = c.U.Storage().NewSection(c.R.FormValue("name")); err == nil {
-		utils.Redirect(c, "/storage/"+strconv.Itoa(SID))
+func GetStorageShelfLives(c *utils.Context) (err error) {
+	var shelfLives database.ShelfLives
+
+	if shelfLives, err = c.U.GetShelfLives(); err == nil {
+		utils.RenderComponent(c, components.StorageShelfLives(shelfLives))
+	}
+
+	return
+}
+
+func PostStorageShelfLives(c *utils.Context) (err error) {
+	for _, category := range database.Categories {
+		for _, storage := range database.StorageTypes {
+			// An empty value means that the article should not be kept there
+			var days *int
+			if value := strings.TrimSpace(c.R.FormValue(string(category) + "-" + string(storage))); value != "" {
+				n, errP := strconv.Atoi(value)
+				if errP != nil {
+					return database.ERR_SHELF_LIFE_INVALID
+				}
+				days = &n
+			}
+
+			if err = c.U.SetShelfLife(category, storage, days); err != nil {
+				return
+			}
+		}
+	}
+
+	utils.Redirect(c, "/storage/shelflives")
+	return
+}
+
+func PostStorageShelfLivesReset(c *utils.Context) (err error) {
+	if err = c.U.ResetShelfLives(); err == nil {
+		utils.Redirect(c, "/storage/shelflives")
 	}
 
 	return
@@ -56,19 +138,32 @@ func PostStorageNew(c *utils.Context) (err error) {
 func GetStorageSection(c *utils.Context) (err error) {
 	var SID int
 	var section database.Section
+	var path []database.Section
+	var summaries []database.SectionSummary
 	sn := make(map[int]string)
 
 	if SID, err = getSID(c); err == nil {
 		search := c.R.URL.Query().Get("search")
 		if section, err = c.U.Storage().GetArticles(SID, search); err == nil {
-			if SID == 0 {
-				sections, _ := c.U.Storage().GetSections()
-				for _, s := range sections {
-					sn[s.SID] = s.Name
-				}
+			if SID != 0 {
+				path, err = c.U.Storage().GetPath(SID)
 			}
+			if err == nil {
+				summaries, err = c.U.Storage().GetSummaries()
+			}
+
+			if err == nil {
+				for _, s := range summaries {
+					sn[s.SID] = s.FullName
+				}
 
-			utils.RenderComponent(c, components.StorageSection(section, search, sn))
+				var children []database.SectionSummary
+				if SID != 0 && search == "" {
+					children = getChildren(summaries, SID)
+				}
+
+				utils.RenderComponent(c, components.StorageSection(section, search, sn, path, children))
+			}
 		}
 	}
 
@@ -77,11 +172,11 @@ func GetStorageSection(c *utils.Context) (err error) {
 
 func GetStorageSectionAdd(c *utils.Context) (err error) {
 	var SID int
-	var sections []database.Section
+	var sections []database.SectionSummary
 
 	if SID, err = getSID(c); err == nil {
 		if SID == 0 {
-			sections, _ = c.U.Storage().GetSections()
+			sections, _ = c.U.Storage().GetSummaries()
 		}
 
 		utils.RenderComponent(c, components.StorageSectionAdd(SID, sections))
@@ -135,10 +230,24 @@ func PostStorageSectionDelete(c *utils.Context) (err error) {
 func GetStorageSectionEdit(c *utils.Context) (err error) {
 	var SID int
 	var section database.Section
+	var summaries []database.SectionSummary
 
 	if SID, err = getSID(c); err == nil {
 		if section, err = c.U.Storage().GetSection(SID); err == nil {
-			utils.RenderComponent(c, components.StorageSectionEdit(section))
+			if summaries, err = c.U.Storage().GetSummaries(); err == nil {
+				// Only the sections outside of this one can be its parent
+				var parents []database.SectionSummary
+				var fullName string
+				for _, s := range summaries {
+					if s.SID == SID {
+						fullName = s.FullName
+					} else if fullName == "" || !strings.HasPrefix(s.FullName, fullName+" / ") {
+						parents = append(parents, s)
+					}
+				}
+
+				utils.RenderComponent(c, components.StorageSectionEdit(section, parents))
+			}
 		}
 	}
 
@@ -146,11 +255,18 @@ func GetStorageSectionEdit(c *utils.Context) (err error) {
 }
 
 func PostStorageSectionEdit(c *utils.Context) (err error) {
-	var SID int
+	var SID, parent int
 
 	if SID, err = getSID(c); err == nil {
-		if err = c.U.Storage().EditSection(SID, c.R.FormValue("name")); err == nil {
-			utils.Redirect(c, "/storage/"+strconv.Itoa(SID))
+		if parent, err = getParent(c); err == nil {
+			if err = c.U.Storage().EditSection(SID, c.R.FormValue("name")); err == nil {
+				if err = c.U.Storage().MoveSection(SID, parent); err == nil {
+					storage := database.StorageType(c.R.FormValue("storage"))
+					if err = c.U.Storage().SetSectionType(SID, storage); err == nil {
+						utils.Redirect(c, "/storage/"+strconv.Itoa(SID))
+					}
+				}
+			}
 		}
 	}
 
@@ -181,7 +297,7 @@ func GetStorageArticle(c *utils.Context) (err error) {
 				prev, next = c.U.Storage().GetNeighbours(SID, AID)
 			}
 
-			sections, _ := c.U.Storage().GetSections()
+			sections, _ := c.U.Storage().GetSummaries()
 
 			utils.RenderComponent(c, components.StorageArticle(SID, article, prev, next, search, sections))
 		}
@@ -200,6 +316,9 @@ func PostStorageArticle(c *utils.Context) (err error) {
 			Name:       c.R.PostFormValue("name"),
 			Expiration: c.R.PostFormValue("expiration"),
 			Quantity:   c.R.PostFormValue("quantity"),
+			Category:   c.R.PostFormValue("category"),
+			Opened:     c.R.PostFormValue("opened"),
+			Frozen:     c.R.PostFormValue("frozen"),
 		}
 
 		search := c.R.URL.Query().Get("search")
@@ -1,16 +1,87 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/url"
+	"time"
+
 	"cucinassistant/configs"
 	"cucinassistant/database"
 	"cucinassistant/email"
 	"cucinassistant/langs"
+	"cucinassistant/oidc"
 	"cucinassistant/web/components"
 	"cucinassistant/web/utils"
 )
 
+// getRelyingParty returns the relying party of the passkeys,
+// read from configs.BaseURL
+func getRelyingParty() database.RelyingParty {
+	base, err := url.Parse(configs.BaseURL)
+	if err != nil {
+		return database.RelyingParty{}
+	}
+
+	return database.RelyingParty{ID: base.Hostname(), Origin: base.Scheme + "://" + base.Host}
+}
+
+// getBase64URL returns the decoded value of a base64url-encoded field.
+// If it cannot be decoded, nil is returned.
+func getBase64URL(c *utils.Context, name string) []byte {
+	value, _ := base64.RawURLEncoding.DecodeString(c.R.FormValue(name))
+	return value
+}
+
+// passwordsDisabled returns true, after showing an error,
+// if the local passwords have been disabled
+func passwordsDisabled(c *utils.Context) bool {
+	if configs.PasswordsDisabled {
+		utils.ShowError(c, langs.STR_PASSWORDS_DISABLED, "/user/signin", http.StatusForbidden)
+	}
+
+	return configs.PasswordsDisabled
+}
+
+// completeSignIn signs the user in, or asks for the two-factor
+// authentication code if it's enabled
+func completeSignIn(c *utils.Context, user database.User, msg langs.String) (err error) {
+	var totp bool
+
+	if totp, err = user.HasTOTP(); err == nil {
+		if totp {
+			utils.SavePendingUID(c, user.UID)
+		} else {
+			utils.SaveUID(c, user.UID, msg)
+		}
+	}
+
+	return
+}
+
+// sendEmailVerification sends the link to verify the email
+// to the address that is waiting to be verified
+func sendEmailVerification(user *database.User) error {
+	token, err := user.EmailVerificationToken(time.Now())
+	if err != nil {
+		return err
+	}
+
+	recipient := *user
+	if user.PendingEmail != nil {
+		recipient.Email = *user.PendingEmail
+	}
+
+	go email.VerifyEmail.Write(
+		&recipient,
+		configs.BaseURL+"/user/verify_email?token="+token,
+	).Send()
+	return nil
+}
+
 func GetUserChangeEmail(c *utils.Context) (err error) {
-	utils.RenderComponent(c, components.UserChangeEmail(c.U.Email))
+	utils.RenderComponent(c, components.UserChangeEmail(c.U))
 	return
 }
 
@@ -18,7 +89,43 @@ func PostUserChangeEmail(c *utils.Context) (err error) {
 	newEmail := c.R.FormValue("email-new")
 
 	if err = c.U.ChangeEmail(newEmail); err == nil {
-		utils.ShowMessage(c, langs.STR_EMAIL_CHANGED, "/user/settings")
+		if c.U.PendingEmail != nil {
+			if err = sendEmailVerification(c.U); err == nil {
+				utils.ShowMessage(c, langs.STR_EMAIL_VERIFICATION_SENT, "/user/settings")
+			}
+		} else {
+			utils.ShowMessage(c, langs.STR_SETTINGS_SAVED, "/user/settings")
+		}
+	}
+
+	return
+}
+
+func PostUserVerifyEmailResend(c *utils.Context) (err error) {
+	if err = sendEmailVerification(c.U); err == nil {
+		utils.ShowMessage(c, langs.STR_EMAIL_SENT, "/user/settings")
+	}
+
+	return
+}
+
+func GetUserVerifyEmail(c *utils.Context) (err error) {
+	utils.RenderComponent(c, components.UserVerifyEmail(c.R.URL.Query().Get("token")))
+	return
+}
+
+func PostUserVerifyEmail(c *utils.Context) (err error) {
+	var user database.User
+	var oldEmail string
+
+	if user, oldEmail, err = database.VerifyEmail(c.R.FormValue("token"), time.Now()); err == nil {
+		if oldEmail != "" {
+			recipient := user
+			recipient.Email = oldEmail
+			go email.EmailChanged.Write(&recipient, "").Send()
+			utils.LogEvent(c, user, database.EVENT_EMAIL_CHANGED)
+		}
+		utils.ShowMessage(c, langs.STR_EMAIL_VERIFIED, "/")
 	}
 
 	return
@@ -66,17 +173,212 @@ func GetDisableNewsletter(c *utils.Context) (err error) {
 }
 
 func GetUserChangePassword(c *utils.Context) (err error) {
+	if passwordsDisabled(c) {
+		return
+	}
+
 	utils.RenderComponent(c, components.UserChangePassword())
 	return
 }
 
 func PostUserChangePassword(c *utils.Context) (err error) {
+	if passwordsDisabled(c) {
+		return
+	}
+
 	oldPassword := c.R.FormValue("old-password")
 	newPassword := c.R.FormValue("new-password")
 
 	if err = c.U.ChangePassword(oldPassword, newPassword); err == nil {
-		go email.PasswordChanged.Write(c.U, "").Send()
-		utils.ShowMessage(c, langs.STR_PASSWORD_CHANGED, "/user/settings")
+		if err = c.U.RevokeOtherSessions(utils.SessionKey(c)); err == nil {
+			utils.LogEvent(c, *c.U, database.EVENT_PASSWORD_CHANGED)
+			go email.PasswordChanged.Write(c.U, "").Send()
+			utils.ShowMessage(c, langs.STR_PASSWORD_CHANGED, "/user/settings")
+		}
+	}
+
+	return
+}
+
+func GetUserDiet(c *utils.Context) (err error) {
+	var profile database.DietaryProfile
+
+	if profile, err = c.U.GetDietaryProfile(); err == nil {
+		utils.RenderComponent(c, components.UserDiet(profile))
+	}
+
+	return
+}
+
+func PostUserDiet(c *utils.Context) (err error) {
+	var profile database.DietaryProfile
+	c.R.ParseForm()
+
+	for _, a := range c.R.PostForm["allergen"] {
+		profile.Allergens = append(profile.Allergens, database.Allergen(a))
+	}
+	for _, d := range c.R.PostForm["diet"] {
+		profile.Diets = append(profile.Diets, database.Diet(d))
+	}
+
+	if err = c.U.SetDietaryProfile(profile); err == nil {
+		utils.ShowMessage(c, langs.STR_SETTINGS_SAVED, "/user/settings")
+	}
+
+	return
+}
+
+func GetUserProfile(c *utils.Context) (err error) {
+	var profile database.Profile
+
+	if profile, err = c.U.GetProfile(); err == nil {
+		utils.RenderComponent(c, components.UserProfile(true, profile, configs.BaseURL))
+	} else if err == database.ERR_PROFILE_NOT_FOUND {
+		utils.RenderComponent(c, components.UserProfile(false, profile, configs.BaseURL))
+		err = nil
+	}
+
+	return
+}
+
+func PostUserProfile(c *utils.Context) (err error) {
+	if c.R.FormValue("public") == "on" {
+		err = c.U.SetProfile(c.R.FormValue("bio"))
+	} else {
+		err = c.U.DeleteProfile()
+	}
+
+	if err == nil {
+		utils.ShowMessage(c, langs.STR_SETTINGS_SAVED, "/user/settings")
+	}
+
+	return
+}
+
+func GetPublicProfile(c *utils.Context) (err error) {
+	var profile database.Profile
+
+	if profile, err = database.GetPublicProfile(mux.Vars(c.R)["username"]); err == nil {
+		utils.RenderComponent(c, components.PublicProfile(profile, configs.BaseURL))
+	}
+
+	return
+}
+
+func GetUserPasskeys(c *utils.Context) (err error) {
+	var passkeys []database.Passkey
+
+	if passkeys, err = c.U.GetPasskeys(); err == nil {
+		challenge := utils.NewChallenge(c)
+		utils.RenderComponent(c, components.UserPasskeys(passkeys, *c.U, challenge, getRelyingParty().ID))
+	}
+
+	return
+}
+
+func PostUserPasskeysNew(c *utils.Context) (err error) {
+	name := c.R.FormValue("name")
+	challenge := utils.PopChallenge(c)
+
+	if _, err = c.U.NewPasskey(name, getRelyingParty(), challenge,
+		getBase64URL(c, "client-data"), getBase64URL(c, "attestation-object")); err == nil {
+		utils.LogEvent(c, *c.U, database.EVENT_PASSKEY_ADDED)
+		utils.ShowMessage(c, langs.STR_PASSKEY_ADDED, "/user/passkeys")
+	}
+
+	return
+}
+
+func PostUserPasskeyDelete(c *utils.Context) (err error) {
+	var PKID int
+
+	if PKID, err = getID(c, "PKID", database.ERR_PASSKEY_NOT_FOUND); err == nil {
+		if err = c.U.DeletePasskey(PKID); err == nil {
+			utils.LogEvent(c, *c.U, database.EVENT_PASSKEY_DELETED)
+			utils.Redirect(c, "/user/passkeys")
+		}
+	}
+
+	return
+}
+
+func GetUserEvents(c *utils.Context) (err error) {
+	var events []database.Event
+
+	if events, err = c.U.GetEvents(); err == nil {
+		utils.RenderComponent(c, components.UserEvents(events))
+	}
+
+	return
+}
+
+func GetUserSessions(c *utils.Context) (err error) {
+	var sessions []database.Session
+
+	if sessions, err = c.U.GetSessions(utils.SessionKey(c)); err == nil {
+		utils.RenderComponent(c, components.UserSessions(sessions))
+	}
+
+	return
+}
+
+func PostUserSessionRevoke(c *utils.Context) (err error) {
+	var SID int
+
+	if SID, err = getID(c, "SID", database.ERR_SESSION_NOT_FOUND); err == nil {
+		if err = c.U.RevokeSession(SID); err == nil {
+			utils.LogEvent(c, *c.U, database.EVENT_SESSION_REVOKED)
+			utils.ShowMessage(c, langs.STR_SESSION_REVOKED, "/user/sessions")
+		}
+	}
+
+	return
+}
+
+func PostUserSessionsRevoke(c *utils.Context) (err error) {
+	if err = c.U.RevokeOtherSessions(utils.SessionKey(c)); err == nil {
+		utils.LogEvent(c, *c.U, database.EVENT_SESSION_REVOKED)
+		utils.ShowMessage(c, langs.STR_SESSIONS_REVOKED, "/user/sessions")
+	}
+
+	return
+}
+
+func GetUserTOTP(c *utils.Context) (err error) {
+	var totp database.TOTP
+
+	if totp, err = c.U.GetTOTP(); err == nil {
+		utils.RenderComponent(c, components.UserTOTP(totp, database.TOTPURI(c.U.Username, totp.Secret)))
+	}
+
+	return
+}
+
+func PostUserTOTPStart(c *utils.Context) (err error) {
+	if _, err = c.U.StartTOTP(); err == nil {
+		utils.Redirect(c, "/user/2fa")
+	}
+
+	return
+}
+
+func PostUserTOTPEnable(c *utils.Context) (err error) {
+	var codes []string
+
+	if codes, err = c.U.EnableTOTP(c.R.FormValue("code"), time.Now()); err == nil {
+		utils.LogEvent(c, *c.U, database.EVENT_TOTP_ENABLED)
+		go email.TOTPEnabled.Write(c.U, "").Send()
+		utils.RenderComponent(c, components.UserRecoveryCodes(codes))
+	}
+
+	return
+}
+
+func PostUserTOTPDisable(c *utils.Context) (err error) {
+	if err = c.U.DisableTOTP(c.R.FormValue("code"), time.Now()); err == nil {
+		utils.LogEvent(c, *c.U, database.EVENT_TOTP_DISABLED)
+		go email.TOTPDisabled.Write(c.U, "").Send()
+		utils.ShowMessage(c, langs.STR_TOTP_DISABLED, "/user/settings")
 	}
 
 	return
@@ -89,8 +391,12 @@ func GetUserChangeUsername(c *utils.Context) (err error) {
 
 func PostUserChangeUsername(c *utils.Context) (err error) {
 	newUsername := c.R.FormValue("username-new")
+	oldUsername := c.U.Username
 
 	if err = c.U.ChangeUsername(newUsername); err == nil {
+		if c.U.Username != oldUsername {
+			utils.LogEvent(c, *c.U, database.EVENT_USERNAME_CHANGED)
+		}
 		utils.ShowMessage(c, langs.STR_USERNAME_CHANGED, "/user/settings")
 	}
 
@@ -105,7 +411,7 @@ func GetUserDelete1(c *utils.Context) (err error) {
 func PostUserDelete1(c *utils.Context) (err error) {
 	var token string
 
-	if token, err = c.U.GenerateToken(); err == nil {
+	if token, err = c.U.GenerateToken(database.TOKEN_DELETE); err == nil {
 		go email.DeleteConfirm.Write(
 			c.U,
 			configs.BaseURL+"/user/delete_2?token="+token,
@@ -125,6 +431,7 @@ func PostUserDelete2(c *utils.Context) (err error) {
 	token := c.R.FormValue("token")
 
 	if err = c.U.Delete(token); err == nil {
+		utils.LogEvent(c, *c.U, database.EVENT_DELETED)
 		go email.Goodbye.Write(c.U, "").Send()
 		utils.DropUID(c, langs.STR_USER_DELETED)
 	}
@@ -133,6 +440,10 @@ func PostUserDelete2(c *utils.Context) (err error) {
 }
 
 func GetForgotPassword(c *utils.Context) (err error) {
+	if passwordsDisabled(c) {
+		return
+	}
+
 	utils.RenderComponent(c, components.UserForgotPassword())
 	return
 }
@@ -141,9 +452,13 @@ func PostForgotPassword(c *utils.Context) (err error) {
 	var user database.User
 	var token string
 
+	if passwordsDisabled(c) {
+		return
+	}
+
 	userEmail := c.R.FormValue("email")
 	if user, err = database.GetUser("email", userEmail); err == nil {
-		if token, err = user.GenerateToken(); err == nil {
+		if token, err = user.GenerateToken(database.TOKEN_RESET_PASSWORD); err == nil {
 			go email.ResetPassword.Write(
 				&user,
 				configs.BaseURL+"/user/reset_password?token="+token,
@@ -156,6 +471,10 @@ func PostForgotPassword(c *utils.Context) (err error) {
 }
 
 func GetResetPassword(c *utils.Context) (err error) {
+	if passwordsDisabled(c) {
+		return
+	}
+
 	utils.RenderComponent(c, components.UserResetPassword(c.R.URL.Query().Get("token")))
 	return
 }
@@ -165,10 +484,15 @@ func PostResetPassword(c *utils.Context) (err error) {
 	newPassword := c.R.FormValue("password")
 	user := database.User{Email: c.R.FormValue("email")}
 
+	if passwordsDisabled(c) {
+		return
+	}
+
 	if err = user.ResetPassword(token, newPassword); err == nil {
 		if user, err = database.GetUser("UID", user.UID); err == nil {
+			utils.LogEvent(c, user, database.EVENT_PASSWORD_RESET)
 			go email.PasswordChanged.Write(&user, "").Send()
-			utils.SaveUID(c, user.UID, langs.STR_PASSWORD_CHANGED)
+			err = completeSignIn(c, user, langs.STR_PASSWORD_CHANGED)
 		}
 	}
 
@@ -176,21 +500,83 @@ func PostResetPassword(c *utils.Context) (err error) {
 }
 
 func GetUserSettings(c *utils.Context) (err error) {
-	utils.RenderComponent(c, components.UserSettings(configs.SupportEmail))
+	utils.RenderComponent(c, components.UserSettings(configs.SupportEmail, oidc.Enabled(), !configs.PasswordsDisabled, c.U.Admin))
 	return
 }
 
 func GetUserSignIn(c *utils.Context) (err error) {
-	utils.RenderComponent(c, components.UserSignIn())
+	var oidcName string
+	if oidc.Enabled() {
+		oidcName = configs.OIDCName
+	}
+
+	utils.RenderComponent(c, components.UserSignIn(oidcName, !configs.PasswordsDisabled))
 	return
 }
 
 func PostUserSignIn(c *utils.Context) (err error) {
 	var user database.User
 
+	if passwordsDisabled(c) {
+		return
+	}
+
 	username := c.R.FormValue("username")
 	password := c.R.FormValue("password")
 	if user, err = database.SignIn(username, password); err == nil {
+		err = completeSignIn(c, user, langs.STR_NONE)
+	} else if u, errU := database.GetUser("username", username); errU == nil {
+		utils.LogEvent(c, u, database.EVENT_SIGNIN_FAILED)
+	}
+
+	return
+}
+
+func GetUserSignIn2FA(c *utils.Context) (err error) {
+	if _, found := utils.GetPendingUID(c); found {
+		utils.RenderComponent(c, components.UserSignIn2FA())
+	} else {
+		utils.Redirect(c, "/user/signin")
+	}
+
+	return
+}
+
+func PostUserSignIn2FA(c *utils.Context) (err error) {
+	UID, found := utils.GetPendingUID(c)
+	if !found {
+		utils.Redirect(c, "/user/signin")
+		return
+	}
+
+	// The attempts are limited by the throttle of the endpoint, and the
+	// sign in is dropped after a few wrong codes
+	user := database.User{UID: UID}
+	if err = user.CheckTOTP(c.R.FormValue("code"), time.Now()); err == nil {
+		utils.SaveUID(c, UID, langs.STR_NONE)
+	} else if err == database.ERR_TOTP_WRONG_CODE {
+		utils.LogEvent(c, user, database.EVENT_SIGNIN_FAILED)
+		utils.FailPendingUID(c)
+	}
+
+	return
+}
+
+func GetUserSignInPasskey(c *utils.Context) (err error) {
+	challenge := utils.NewChallenge(c)
+	utils.RenderComponent(c, components.UserSignInPasskey(challenge, getRelyingParty().ID))
+	return
+}
+
+func PostUserSignInPasskey(c *utils.Context) (err error) {
+	var user database.User
+
+	// The passkeys already verify the user, so the two-factor
+	// authentication is not required
+	challenge := utils.PopChallenge(c)
+	if user, err = database.SignInWithPasskey(getRelyingParty(), challenge,
+		c.R.FormValue("credential"), c.R.FormValue("user-handle"), getBase64URL(c, "client-data"),
+		getBase64URL(c, "authenticator-data"), getBase64URL(c, "signature")); err == nil {
 		utils.SaveUID(c, user.UID, langs.STR_NONE)
 	}
 
@@ -203,6 +589,10 @@ func PostUserSignOut(c *utils.Context) (err error) {
 }
 
 func GetUserSignUp(c *utils.Context) (err error) {
+	if passwordsDisabled(c) {
+		return
+	}
+
 	utils.RenderComponent(c, components.UserSignUp())
 	return
 }
@@ -210,6 +600,10 @@ func GetUserSignUp(c *utils.Context) (err error) {
 func PostUserSignUp(c *utils.Context) (err error) {
 	var user database.User
 
+	if passwordsDisabled(c) {
+		return
+	}
+
 	username := c.R.FormValue("username")
 	email_ := c.R.FormValue("email")
 	password := c.R.FormValue("password")
@@ -217,7 +611,9 @@ func PostUserSignUp(c *utils.Context) (err error) {
 	if user, err = database.SignUp(username, email_, password); err == nil {
 		user.ChangeEmailLang(c.L)
 		go email.Welcome.Write(&user, "").Send()
-		utils.SaveUID(c, user.UID, langs.STR_USER_CREATED)
+		if err = sendEmailVerification(&user); err == nil {
+			utils.SaveUID(c, user.UID, langs.STR_USER_CREATED)
+		}
 	}
 
 	return
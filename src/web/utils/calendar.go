@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cucinassistant/configs"
+)
+
+// Event is an event of an iCalendar feed
+type Event struct {
+	// UID identifies the event, and must not change when the
+	// event is updated
+	UID string
+
+	// Summary is the title of the event
+	Summary string
+
+	// Description is an optional longer text
+	Description string
+
+	// Start is when the event starts. If AllDay is set, only
+	// the date is used
+	Start time.Time
+
+	// End is when the event ends. It is ignored if AllDay is set
+	End time.Time
+
+	// AllDay is true if the event lasts the whole day
+	AllDay bool
+
+	// Modified is when the event has been last changed
+	Modified time.Time
+}
+
+// escapeText escapes a text value of an iCalendar property
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeLine writes a content line of an iCalendar feed, folding it
+// so that no line is longer than 75 octets, including the space that
+// starts the continuation lines (without breaking any UTF-8 sequence)
+func writeLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+
+	b.WriteString(line + "\r\n")
+}
+
+// RenderCalendar writes an iCalendar (RFC 5545) feed containing
+// the given events. The timed events use the floating time, so they
+// are shown at the same hour in every timezone.
+func RenderCalendar(c *Context, name string, events []Event) {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//CucinAssistant//"+fmt.Sprint(configs.VersionCode)+"//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escapeText(name))
+
+	for _, e := range events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+e.UID)
+		writeLine(&b, "DTSTAMP:"+e.Modified.UTC().Format("20060102T150405Z"))
+		writeLine(&b, "LAST-MODIFIED:"+e.Modified.UTC().Format("20060102T150405Z"))
+		if e.AllDay {
+			writeLine(&b, "DTSTART;VALUE=DATE:"+e.Start.Format("20060102"))
+			writeLine(&b, "DTEND;VALUE=DATE:"+e.Start.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			writeLine(&b, "DTSTART:"+e.Start.Format("20060102T150405"))
+			writeLine(&b, "DTEND:"+e.End.Format("20060102T150405"))
+		}
+		writeLine(&b, "SUMMARY:"+escapeText(e.Summary))
+		if e.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	c.W.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	c.W.Header().Set("Content-Disposition", "inline; filename=\"cucinassistant.ics\"")
+	c.W.Write([]byte(b.String()))
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWriteLine(t *testing.T) {
+	cases := []struct {
+		Message string
+		Line    string
+
+		ExpectedLines int
+	}{
+		{"(short)", "SUMMARY:Pasta", 1},
+		{"(exactly 75 octets)", "SUMMARY:" + strings.Repeat("a", 67), 1},
+		{"(long)", "SUMMARY:" + strings.Repeat("a", 200), 3},
+		{"(multibyte)", "SUMMARY:" + strings.Repeat("è", 60) + strings.Repeat("🍝", 20), 3},
+	}
+
+	for _, tc := range cases {
+		var b strings.Builder
+		writeLine(&b, tc.Line)
+
+		lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+		if len(lines) != tc.ExpectedLines {
+			t.Errorf("%s: expected <%d> lines, got <%d>", tc.Message, tc.ExpectedLines, len(lines))
+		}
+
+		unfolded := lines[0]
+		for i, line := range lines {
+			if len(line) > 75 {
+				t.Errorf("%s: line %d is <%d> octets long", tc.Message, i, len(line))
+			} else if !utf8.ValidString(line) {
+				t.Errorf("%s: line %d breaks an UTF-8 sequence", tc.Message, i)
+			}
+
+			if i > 0 {
+				if !strings.HasPrefix(line, " ") {
+					t.Errorf("%s: line %d doesn't start with a space", tc.Message, i)
+				}
+				unfolded += strings.TrimPrefix(line, " ")
+			}
+		}
+
+		if unfolded != tc.Line {
+			t.Errorf("%s: expected unfolded <%s>, got <%s>", tc.Message, tc.Line, unfolded)
+		}
+	}
+}
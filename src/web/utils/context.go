@@ -58,7 +58,15 @@ func buildContext(w http.ResponseWriter, r *http.Request, protected bool) *Conte
 	if protected {
 		if rawUID, found := c.s.Values["UID"]; found {
 			u, _ := database.GetUser("UID", rawUID.(int))
-			c.U = &u
+
+			// Makes sure the session hasn't been revoked
+			// and the user hasn't been disabled
+			if err := u.TouchSession(c.s.ID, r.UserAgent(), GetIP(r)); err == nil && !u.Disabled {
+				c.U = &u
+			} else {
+				delete(c.s.Values, "UID")
+				c.s.Save(r, w)
+			}
 		}
 	}
 
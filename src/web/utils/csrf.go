@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"cucinassistant/configs"
+	"cucinassistant/langs"
+)
+
+// csrfHeader is the header in which htmx sends the CSRF token
+const csrfHeader = "X-CSRF-Token"
+
+// csrfField is the form field in which the other forms send the CSRF token
+const csrfField = "csrf_token"
+
+// CSRFToken returns the CSRF token of the session, generating it
+// if it doesn't have one yet
+func CSRFToken(c *Context) string {
+	token, _ := c.s.Values["CSRF"].(string)
+
+	if token == "" {
+		buffer := make([]byte, 32)
+		rand.Read(buffer)
+		token = base64.RawURLEncoding.EncodeToString(buffer)
+
+		c.s.Values["CSRF"] = token
+		if err := c.s.Save(c.R, c.W); err != nil {
+			slog.Error("while saving session:", "err", err)
+		}
+	}
+
+	return token
+}
+
+// baseOrigin returns the origin of configs.BaseURL,
+// or an empty string if it can't be parsed
+func baseOrigin() string {
+	u, err := url.Parse(configs.BaseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+
+	return u.Scheme + "://" + u.Host
+}
+
+// checkCSRF returns true if a request comes from the website itself:
+// it must not be marked as cross-site by the browser, and it must
+// contain the expected token
+func checkCSRF(r *http.Request, expected string) bool {
+	// Checks what the browser says about the request
+	if site := r.Header.Get("Sec-Fetch-Site"); site == "cross-site" || site == "same-site" {
+		return false
+	} else if origin := r.Header.Get("Origin"); origin != "" && baseOrigin() != "" && origin != baseOrigin() {
+		return false
+	}
+
+	// Compares the tokens
+	token := r.Header.Get(csrfHeader)
+	if token == "" {
+		token = r.PostFormValue(csrfField)
+	}
+
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// CSRFProtect returns a handler that rejects the requests that
+// don't pass checkCSRF, then runs the given one
+func CSRFProtect(h Handler) Handler {
+	return func(c *Context) error {
+		expected, _ := c.s.Values["CSRF"].(string)
+		if !checkCSRF(c.R, expected) {
+			ShowError(c, langs.STR_PAGE_EXPIRED, "", http.StatusForbidden)
+			return nil
+		}
+
+		return h(c)
+	}
+}
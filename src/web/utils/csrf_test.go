@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/sessions"
+
+	"cucinassistant/configs"
+)
+
+const testToken = "token"
+
+// newCSRFRequest returns a POST request with the given headers and form
+func newCSRFRequest(headers map[string]string, form url.Values) *http.Request {
+	r := httptest.NewRequest("POST", "https://cucinassistant.com/user/settings", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+
+	return r
+}
+
+func TestCheckCSRF(t *testing.T) {
+	configs.BaseURL = "https://cucinassistant.com"
+
+	cases := []struct {
+		Message  string
+		Headers  map[string]string
+		Form     url.Values
+		Expected string
+
+		Allowed bool
+	}{
+		{
+			"without token",
+			nil, nil, testToken, false,
+		},
+		{
+			"with wrong token",
+			map[string]string{csrfHeader: "wrong"}, nil, testToken, false,
+		},
+		{
+			"without token in the session",
+			map[string]string{csrfHeader: ""}, url.Values{csrfField: {""}}, "", false,
+		},
+		{
+			"from another website",
+			map[string]string{csrfHeader: testToken, "Sec-Fetch-Site": "cross-site"}, nil, testToken, false,
+		},
+		{
+			"from a subdomain",
+			map[string]string{csrfHeader: testToken, "Sec-Fetch-Site": "same-site"}, nil, testToken, false,
+		},
+		{
+			"from another origin",
+			map[string]string{csrfHeader: testToken, "Origin": "https://evil.com"}, nil, testToken, false,
+		},
+		{
+			"(header)",
+			map[string]string{csrfHeader: testToken, "Sec-Fetch-Site": "same-origin"}, nil, testToken, true,
+		},
+		{
+			"(form)",
+			map[string]string{"Origin": "https://cucinassistant.com"}, url.Values{csrfField: {testToken}}, testToken, true,
+		},
+	}
+
+	for _, tc := range cases {
+		if got := checkCSRF(newCSRFRequest(tc.Headers, tc.Form), tc.Expected); got != tc.Allowed {
+			t.Errorf("%s: expected <%v>, got <%v>", tc.Message, tc.Allowed, got)
+		}
+	}
+}
+
+func TestCSRFProtect(t *testing.T) {
+	configs.BaseURL = "https://cucinassistant.com"
+
+	cases := []struct {
+		Message string
+		Headers map[string]string
+
+		ExpectedCalled bool
+	}{
+		{
+			"cross-site request",
+			map[string]string{csrfHeader: testToken, "Origin": "https://evil.com", "HX-Request": "true"},
+			false,
+		},
+		{
+			"request without token",
+			map[string]string{"HX-Request": "true"},
+			false,
+		},
+		{
+			"",
+			map[string]string{csrfHeader: testToken, "HX-Request": "true"},
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		r := newCSRFRequest(tc.Headers, nil)
+		w := httptest.NewRecorder()
+		s := sessions.NewSession(nil, sessionName)
+		s.Values["CSRF"] = testToken
+		c := &Context{W: w, R: r, L: "en", s: s, h: r.Header.Get("HX-Request") != ""}
+
+		called := false
+		CSRFProtect(func(c *Context) error {
+			called = true
+			return nil
+		})(c)
+
+		if called != tc.ExpectedCalled {
+			t.Errorf("%s: expected called <%v>, got <%v>", tc.Message, tc.ExpectedCalled, called)
+		} else if !called && w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected status <%d>, got <%d>", tc.Message, http.StatusForbidden, w.Code)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package utils
 import (
 	"github.com/gorilla/mux"
 	"net/http"
+	"time"
 
+	"cucinassistant/configs"
 	"cucinassistant/langs"
 )
 
@@ -24,6 +26,29 @@ type Endpoint struct {
 	// PostHandler is the function executed on POST requests
 	// If not set, the endpoint will show an error on POST requests
 	PostHandler func(*Context) error
+
+	// Throttle, if set, protects the POST handler from brute-force attacks
+	Throttle *Throttle
+
+	// RateLimited indicates whether the GET requests are limited by the
+	// client's IP address (see configs.PublicRateLimit)
+	RateLimited bool
+
+	// Admin indicates whether the endpoint is reserved to the admins
+	Admin bool
+}
+
+// AdminOnly makes a handler respond as if the page didn't exist,
+// unless the user is an admin
+func AdminOnly(h Handler) Handler {
+	return func(c *Context) error {
+		if c.U == nil || !c.U.Admin {
+			ShowError(c, langs.STR_PAGE_NOT_FOUND, "/", http.StatusNotFound)
+			return nil
+		}
+
+		return h(c)
+	}
 }
 
 // Register adds the endpoint to the router
@@ -41,6 +66,9 @@ func (e Endpoint) Register(router *mux.Router) {
 	} else {
 		get = unknownHandler
 	}
+	if e.RateLimited {
+		get = NewRateLimiter(configs.PublicRateLimit, time.Minute).Wrap(get)
+	}
 
 	// Prepares the POST handler
 	var post Handler
@@ -49,6 +77,14 @@ func (e Endpoint) Register(router *mux.Router) {
 	} else {
 		post = unknownHandler
 	}
+	if e.Throttle != nil {
+		post = e.Throttle.Wrap(post)
+	}
+	if e.Admin {
+		get = AdminOnly(get)
+		post = AdminOnly(post)
+	}
+	post = CSRFProtect(post)
 
 	// Registers them
 	if e.Unprotected {
@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cucinassistant/configs"
+	"cucinassistant/langs"
+)
+
+// RateLimiter limits the number of requests that can be made with the
+// same key (usually the client's IP address) in a time window
+type RateLimiter struct {
+	// Limit is the maximum number of requests in a window
+	Limit int
+
+	// Window is the duration of a window
+	Window time.Duration
+
+	// windows contains the current window of every key
+	windows map[string]rateWindow
+
+	// mutex protects windows
+	mutex sync.Mutex
+}
+
+// rateWindow is the window of a single key
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter returns a RateLimiter that allows limit
+// requests per window
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{Limit: limit, Window: window, windows: make(map[string]rateWindow)}
+}
+
+// Allow counts a request made with the given key, and returns
+// false if the limit has been exceeded
+func (rl *RateLimiter) Allow(key string) bool {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	// Drops the expired windows, from time to time
+	now := time.Now()
+	if len(rl.windows) > 10000 {
+		for k, w := range rl.windows {
+			if now.Sub(w.start) >= rl.Window {
+				delete(rl.windows, k)
+			}
+		}
+	}
+
+	// Counts the request
+	w := rl.windows[key]
+	if now.Sub(w.start) >= rl.Window {
+		w = rateWindow{start: now}
+	}
+	w.count++
+	rl.windows[key] = w
+
+	return w.count <= rl.Limit
+}
+
+// Wrap returns a handler that shows an error instead of running the
+// given one, if the client's IP address has exceeded the limit
+func (rl *RateLimiter) Wrap(h Handler) Handler {
+	return func(c *Context) error {
+		if !rl.Allow("IP:" + GetIP(c.R)) {
+			ShowError(c, langs.STR_TOO_MANY_REQUESTS, "", http.StatusTooManyRequests)
+			return nil
+		}
+
+		return h(c)
+	}
+}
+
+// GetIP returns the IP address of the client that made a request.
+// If configs.TrustProxy is set, it is read from the X-Forwarded-For header
+// (the last address is used, since it is the one added by the proxy).
+func GetIP(r *http.Request) string {
+	if configs.TrustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			addresses := strings.Split(forwarded, ",")
+			return strings.TrimSpace(addresses[len(addresses)-1])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// maxLockout is the longest time a key can be locked out for
+const maxLockout = 24 * time.Hour
+
+// Lockout locks out the keys (usually the client's IP address or the
+// targeted account) that have failed too many times in a row. Every
+// further failure doubles the duration of the lockout.
+type Lockout struct {
+	// Threshold is the number of failures after which a key is locked out
+	Threshold int
+
+	// Duration is the duration of the first lockout
+	Duration time.Duration
+
+	// entries contains the failures of every key
+	entries map[string]lockoutEntry
+
+	// mutex protects entries
+	mutex sync.Mutex
+}
+
+// lockoutEntry contains the failures of a single key
+type lockoutEntry struct {
+	failures int
+	until    time.Time
+	last     time.Time
+}
+
+// NewLockout returns a Lockout that locks out a key for duration
+// after threshold failures
+func NewLockout(threshold int, duration time.Duration) *Lockout {
+	return &Lockout{Threshold: threshold, Duration: duration, entries: make(map[string]lockoutEntry)}
+}
+
+// Locked returns true if the key is locked out
+func (l *Lockout) Locked(key string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return time.Now().Before(l.entries[key].until)
+}
+
+// Fail counts a failure of the key, locking it out if the
+// threshold has been reached
+func (l *Lockout) Fail(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Drops the forgotten entries, from time to time
+	now := time.Now()
+	if len(l.entries) > 10000 {
+		for k, e := range l.entries {
+			if now.Sub(e.last) >= maxLockout && now.After(e.until) {
+				delete(l.entries, k)
+			}
+		}
+	}
+
+	// Forgets the old failures
+	e := l.entries[key]
+	if now.Sub(e.last) >= maxLockout {
+		e = lockoutEntry{}
+	}
+	e.failures++
+	e.last = now
+
+	// Locks out the key
+	if e.failures >= l.Threshold {
+		duration := l.Duration
+		for i := l.Threshold; i < e.failures && duration < maxLockout; i++ {
+			duration *= 2
+		}
+		e.until = now.Add(min(duration, maxLockout))
+	}
+
+	l.entries[key] = e
+}
+
+// Reset forgets the failures of the key
+func (l *Lockout) Reset(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.entries, key)
+}
+
+// Throttle protects an endpoint from brute-force attacks: the requests
+// are limited by the client's IP address and by the targeted account,
+// which are locked out after too many failures in a row. If neither
+// AccountField nor PendingAccount is set, the targeted account is the
+// signed in user (if any). The limits are read from configs.
+type Throttle struct {
+	// AccountField is the form field that contains the targeted account
+	// (like the username or the email)
+	AccountField string
+
+	// PendingAccount indicates whether the targeted account is the one
+	// that has still to give the two-factor authentication code
+	// (see SavePendingUID), instead of AccountField
+	PendingAccount bool
+
+	// Email indicates whether the endpoint sends an email to the targeted
+	// account: if so, only a request every configs.EmailInterval minutes
+	// is allowed for the same account
+	Email bool
+
+	// limiter limits the requests
+	limiter *RateLimiter
+
+	// emails limits the requests that send an email
+	emails *RateLimiter
+
+	// lockout counts the failures
+	lockout *Lockout
+}
+
+// account returns the account targeted by the request, if any
+func (t *Throttle) account(c *Context) string {
+	if t.PendingAccount {
+		if UID, found := GetPendingUID(c); found {
+			return "UID:" + strconv.Itoa(UID)
+		}
+
+		return ""
+	} else if t.AccountField == "" {
+		if c.U != nil {
+			return "UID:" + strconv.Itoa(c.U.UID)
+		}
+
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(c.R.FormValue(t.AccountField)))
+}
+
+// Wrap returns a handler that applies the throttle to the given one.
+// A request fails if the handler returns an error.
+func (t *Throttle) Wrap(h Handler) Handler {
+	t.limiter = NewRateLimiter(configs.RateLimit, time.Minute)
+	t.lockout = NewLockout(configs.LockoutThreshold, time.Duration(configs.LockoutDuration)*time.Minute)
+	if t.Email {
+		t.emails = NewRateLimiter(1, time.Duration(configs.EmailInterval)*time.Minute)
+	}
+
+	return func(c *Context) error {
+		// Prepares the keys
+		keys := []string{"IP:" + GetIP(c.R)}
+		account := t.account(c)
+		if account != "" {
+			keys = append(keys, "account:"+account)
+		}
+
+		// Ensures they are allowed
+		for _, key := range keys {
+			if t.lockout.Locked(key) || !t.limiter.Allow(key) {
+				ShowError(c, langs.STR_TOO_MANY_REQUESTS, "", http.StatusTooManyRequests)
+				return nil
+			}
+		}
+
+		// Ensures no email has been sent to the account recently
+		if t.Email && account != "" && !t.emails.Allow("account:"+account) {
+			ShowError(c, langs.STR_TOO_MANY_REQUESTS, "", http.StatusTooManyRequests)
+			return nil
+		}
+
+		// Runs the handler, then counts the failure
+		err := h(c)
+		if err != nil {
+			for _, key := range keys {
+				t.lockout.Fail(key)
+			}
+		} else if account != "" {
+			// The failures of the IP address are kept until they are
+			// forgotten: otherwise, whoever is guessing could reset them
+			// by signing in to their own account
+			t.lockout.Reset("account:" + account)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+
+	"cucinassistant/configs"
+	"cucinassistant/database"
+)
+
+// errFailed is returned by the handlers that fail in the tests
+var errFailed = errors.New("failed")
+
+// newLimitedContext returns the context of a POST request made from
+// the given IP address, for the given username
+func newLimitedContext(IP string, username string) (*Context, *httptest.ResponseRecorder) {
+	form := url.Values{"username": {username}}
+	r := httptest.NewRequest("POST", "https://cucinassistant.com/user/signin", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("HX-Request", "true")
+	r.RemoteAddr = IP + ":1234"
+
+	w := httptest.NewRecorder()
+	s := sessions.NewSession(nil, sessionName)
+	s.Values["CSRF"] = testToken
+	return &Context{W: w, R: r, L: "en", s: s, h: true}, w
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	rl := NewRateLimiter(2, time.Minute)
+
+	cases := []struct {
+		Message string
+		Key     string
+		Expired bool
+
+		Expected bool
+	}{
+		{"(first)", "a", false, true},
+		{"(second)", "a", false, true},
+		{"exceeded limit", "a", false, false},
+		{"(other key)", "b", false, true},
+		{"(new window)", "a", true, true},
+	}
+
+	for _, tc := range cases {
+		if tc.Expired {
+			w := rl.windows[tc.Key]
+			w.start = w.start.Add(-rl.Window)
+			rl.windows[tc.Key] = w
+		}
+
+		if got := rl.Allow(tc.Key); got != tc.Expected {
+			t.Errorf("%s: expected <%v>, got <%v>", tc.Message, tc.Expected, got)
+		}
+	}
+}
+
+func TestRateLimiterWrap(t *testing.T) {
+	h := NewRateLimiter(1, time.Minute).Wrap(func(c *Context) error { return nil })
+
+	cases := []struct {
+		Message string
+		IP      string
+
+		ExpectedStatus int
+	}{
+		{"(first)", "10.0.0.1", http.StatusOK},
+		{"exceeded limit", "10.0.0.1", http.StatusTooManyRequests},
+		{"(other IP)", "10.0.0.2", http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		c, w := newLimitedContext(tc.IP, "")
+		h(c)
+
+		if w.Code != tc.ExpectedStatus {
+			t.Errorf("%s: expected status <%d>, got <%d>", tc.Message, tc.ExpectedStatus, w.Code)
+		}
+	}
+}
+
+func TestLockout(t *testing.T) {
+	cases := []struct {
+		Message  string
+		Failures int
+
+		ExpectedLocked   bool
+		ExpectedDuration time.Duration
+	}{
+		{"below threshold", 2, false, 0},
+		{"(threshold)", 3, true, time.Minute},
+		{"(doubled)", 4, true, 2 * time.Minute},
+		{"(doubled twice)", 5, true, 4 * time.Minute},
+		{"(capped)", 20, true, maxLockout},
+	}
+
+	for _, tc := range cases {
+		l := NewLockout(3, time.Minute)
+		for n := 0; n < tc.Failures; n++ {
+			l.Fail("key")
+		}
+
+		if got := l.Locked("key"); got != tc.ExpectedLocked {
+			t.Errorf("%s: expected locked <%v>, got <%v>", tc.Message, tc.ExpectedLocked, got)
+		} else if got {
+			if remaining := time.Until(l.entries["key"].until); remaining > tc.ExpectedDuration || remaining < tc.ExpectedDuration-time.Second {
+				t.Errorf("%s: expected duration <%v>, got <%v>", tc.Message, tc.ExpectedDuration, remaining)
+			}
+		}
+
+		if l.Locked("other") {
+			t.Errorf("%s: other key locked out", tc.Message)
+		}
+	}
+}
+
+func TestLockoutReset(t *testing.T) {
+	l := NewLockout(1, time.Minute)
+	l.Fail("key")
+	l.Fail("other")
+	l.Reset("key")
+
+	if l.Locked("key") {
+		t.Errorf("expected key not to be locked out")
+	} else if !l.Locked("other") {
+		t.Errorf("expected other key to be still locked out")
+	}
+}
+
+func TestThrottleEmail(t *testing.T) {
+	configs.RateLimit = 100
+	configs.LockoutThreshold = 100
+	configs.EmailInterval = 5
+
+	throttle := &Throttle{AccountField: "username", Email: true}
+	h := throttle.Wrap(func(c *Context) error { return nil })
+
+	cases := []struct {
+		Message  string
+		IP       string
+		Username string
+
+		ExpectedStatus int
+	}{
+		{"(first)", "10.0.0.1", "victim", http.StatusOK},
+		{"email sent again", "10.0.0.2", "victim", http.StatusTooManyRequests},
+		{"(other account)", "10.0.0.2", "other", http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		c, w := newLimitedContext(tc.IP, tc.Username)
+		h(c)
+
+		if w.Code != tc.ExpectedStatus {
+			t.Errorf("%s: expected status <%d>, got <%d>", tc.Message, tc.ExpectedStatus, w.Code)
+		}
+	}
+}
+
+func TestThrottleEmailSignedIn(t *testing.T) {
+	configs.RateLimit = 100
+	configs.LockoutThreshold = 100
+	configs.EmailInterval = 5
+
+	throttle := &Throttle{Email: true}
+	h := throttle.Wrap(func(c *Context) error { return nil })
+
+	cases := []struct {
+		Message string
+		IP      string
+		UID     int
+
+		ExpectedStatus int
+	}{
+		{"(first)", "10.0.0.1", 1, http.StatusOK},
+		{"email sent again", "10.0.0.2", 1, http.StatusTooManyRequests},
+		{"(other user)", "10.0.0.1", 2, http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		c, w := newLimitedContext(tc.IP, "")
+		c.U = &database.User{UID: tc.UID}
+		h(c)
+
+		if w.Code != tc.ExpectedStatus {
+			t.Errorf("%s: expected status <%d>, got <%d>", tc.Message, tc.ExpectedStatus, w.Code)
+		}
+	}
+}
+
+func TestThrottleWrap(t *testing.T) {
+	configs.RateLimit = 100
+	configs.LockoutThreshold = 2
+	configs.LockoutDuration = 5
+
+	// step is a request made before the tested one
+	type step struct {
+		IP       string
+		Username string
+		Fail     bool
+	}
+
+	cases := []struct {
+		Message  string
+		Steps    []step
+		IP       string
+		Username string
+
+		ExpectedCalled bool
+	}{
+		{
+			"(first)",
+			nil, "10.0.0.1", "victim", true,
+		},
+		{
+			"account locked out",
+			[]step{{"10.0.0.1", "victim", true}, {"10.0.0.2", "victim", true}},
+			"10.0.0.3", "victim", false,
+		},
+		{
+			"IP locked out",
+			[]step{{"10.0.0.1", "a", true}, {"10.0.0.1", "b", true}},
+			"10.0.0.1", "c", false,
+		},
+		{
+			"IP reset by signing in",
+			[]step{{"10.0.0.1", "a", true}, {"10.0.0.1", "own", false}, {"10.0.0.1", "b", true}},
+			"10.0.0.1", "c", false,
+		},
+		{
+			"(account reset)",
+			[]step{{"10.0.0.1", "victim", true}, {"10.0.0.2", "victim", false}, {"10.0.0.3", "victim", true}},
+			"10.0.0.4", "victim", true,
+		},
+	}
+
+	for _, tc := range cases {
+		throttle := &Throttle{AccountField: "username"}
+		called := false
+		h := throttle.Wrap(func(c *Context) error {
+			called = true
+			if c.R.FormValue("fail") != "" {
+				return errFailed
+			}
+
+			return nil
+		})
+
+		for _, s := range tc.Steps {
+			c, _ := newLimitedContext(s.IP, s.Username)
+			if s.Fail {
+				c.R.ParseForm()
+				c.R.Form.Set("fail", "true")
+			}
+			h(c)
+		}
+
+		called = false
+		c, w := newLimitedContext(tc.IP, tc.Username)
+		h(c)
+
+		if called != tc.ExpectedCalled {
+			t.Errorf("%s: expected called <%v>, got <%v>", tc.Message, tc.ExpectedCalled, called)
+		} else if !called && w.Code != http.StatusTooManyRequests {
+			t.Errorf("%s: expected status <%d>, got <%d>", tc.Message, http.StatusTooManyRequests, w.Code)
+		}
+	}
+}
@@ -16,10 +16,10 @@ import (
 func render(c *Context, body, message, content templ.Component) {
 	if !c.h {
 		tutorial := fmt.Sprintf("%s/%d_%s.pdf", configs.TutorialsURL, configs.VersionCode, c.L)
-		content = components.TemplateBase(c.U != nil, c.L, body, message, tutorial)
+		content = components.TemplateBase(c.U != nil, c.L, CSRFToken(c), body, message, tutorial)
 	}
 
-	content.Render(langs.Get(&c.L).Ctx(), c.W)
+	content.Render(components.WithCSRF(langs.Get(&c.L).Ctx(), CSRFToken(c)), c.W)
 }
 
 // RenderComponent renders a component
@@ -41,14 +41,28 @@ func RenderSide(c *Context, side templ.Component) {
 // ShowMessage shows a popup message to the user.
 // If path is set, it will redirects it to the given path
 func ShowMessage(c *Context, msg langs.String, path string) {
-	ShowError(c, msg, path, http.StatusCreated)
+	showMessage(c, msg, "", path, http.StatusCreated)
+}
+
+// ShowMessageArg is like ShowMessage, but it replaces the
+// placeholder of the message with the given argument
+func ShowMessageArg(c *Context, msg langs.String, arg string, path string) {
+	showMessage(c, msg, arg, path, http.StatusCreated)
 }
 
 // ShowError is like ShowMessage, but it also sets a status code
 func ShowError(c *Context, msg langs.String, path string, status int) {
+	showMessage(c, msg, "", path, status)
+}
+
+// showMessage is used by ShowMessage, ShowMessageArg and ShowError
+func showMessage(c *Context, msg langs.String, arg string, path string, status int) {
+	// Makes sure the CSRF token is saved before writing the headers
+	CSRFToken(c)
+
 	c.W.Header().Add("HX-Retarget", "#message-container")
 	c.W.WriteHeader(status)
-	page := components.TemplateMessage(msg, path, c.h)
+	page := components.TemplateMessage(msg, arg, path, c.h)
 	render(c, components.TemplateEmpty(), page, page)
 }
 
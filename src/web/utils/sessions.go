@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"log/slog"
 	"net/http"
 	"os"
@@ -11,6 +13,7 @@ import (
 	"github.com/gorilla/sessions"
 
 	"cucinassistant/configs"
+	"cucinassistant/database"
 	"cucinassistant/langs"
 )
 
@@ -20,6 +23,14 @@ var store *pgstore.PGStore
 // sessionName is the session's name
 var sessionName = "ca_session"
 
+// pendingDuration is how long an user has to complete the sign in
+// after giving the right password (see SavePendingUID)
+var pendingDuration = 5 * time.Minute
+
+// pendingAttempts is the number of wrong two-factor authentication
+// codes after which the sign in has to be started again
+const pendingAttempts = 3
+
 // InitSessionStore initializes the cookie session store.
 // It lasts 90 days.
 func InitSessionStore() {
@@ -47,7 +58,18 @@ func InitSessionStore() {
 // It also redirects to /, with an optional message
 func SaveUID(c *Context, UID int, msg langs.String) {
 	c.s.Values["UID"] = UID
-	c.s.Save(c.R, c.W)
+	delete(c.s.Values, "PendingUID")
+	delete(c.s.Values, "PendingSince")
+	delete(c.s.Values, "PendingFailures")
+	if err := c.s.Save(c.R, c.W); err != nil {
+		slog.Error("while saving session:", "err", err)
+	}
+
+	// Starts tracking the session (its ID is generated when it's saved)
+	if err := (database.User{UID: UID}).NewSession(c.s.ID, c.R.UserAgent(), GetIP(c.R)); err != nil {
+		slog.Error("while tracking session:", "err", err)
+	}
+	LogEvent(c, database.User{UID: UID}, database.EVENT_SIGNIN)
 
 	if msg != langs.STR_NONE {
 		ShowMessage(c, msg, "/")
@@ -56,6 +78,118 @@ func SaveUID(c *Context, UID int, msg langs.String) {
 	}
 }
 
+// LogEvent records a security event of the user, with the
+// IP address and the user agent of the request
+func LogEvent(c *Context, u database.User, event database.SecurityEvent) {
+	if err := u.LogEvent(event, GetIP(c.R), c.R.UserAgent()); err != nil {
+		slog.Error("while logging event:", "err", err)
+	}
+}
+
+// SavePendingUID adds to the session an user that has given the right
+// password, but still has to give the two-factor authentication code.
+// It also redirects to /user/signin/2fa
+func SavePendingUID(c *Context, UID int) {
+	c.s.Values["PendingUID"] = UID
+	c.s.Values["PendingSince"] = time.Now().Unix()
+	delete(c.s.Values, "PendingFailures")
+	if err := c.s.Save(c.R, c.W); err != nil {
+		slog.Error("while saving session:", "err", err)
+	}
+
+	Redirect(c, "/user/signin/2fa")
+}
+
+// GetPendingUID returns the UID added with SavePendingUID,
+// if the sign in hasn't expired
+func GetPendingUID(c *Context) (int, bool) {
+	UID, found := c.s.Values["PendingUID"].(int)
+	since, _ := c.s.Values["PendingSince"].(int64)
+
+	if !found || time.Since(time.Unix(since, 0)) > pendingDuration {
+		return 0, false
+	}
+
+	return UID, true
+}
+
+// FailPendingUID counts a wrong two-factor authentication code.
+// After pendingAttempts, the UID added with SavePendingUID is dropped,
+// so that the password has to be given again.
+func FailPendingUID(c *Context) {
+	failures, _ := c.s.Values["PendingFailures"].(int)
+	if failures+1 >= pendingAttempts {
+		delete(c.s.Values, "PendingUID")
+		delete(c.s.Values, "PendingSince")
+		delete(c.s.Values, "PendingFailures")
+	} else {
+		c.s.Values["PendingFailures"] = failures + 1
+	}
+
+	if err := c.s.Save(c.R, c.W); err != nil {
+		slog.Error("while saving session:", "err", err)
+	}
+}
+
+// NewChallenge generates a random challenge for the passkeys, saves it
+// in the session and returns it (base64url-encoded)
+func NewChallenge(c *Context) string {
+	buffer := make([]byte, 32)
+	rand.Read(buffer)
+	challenge := base64.RawURLEncoding.EncodeToString(buffer)
+
+	c.s.Values["Challenge"] = challenge
+	if err := c.s.Save(c.R, c.W); err != nil {
+		slog.Error("while saving session:", "err", err)
+	}
+
+	return challenge
+}
+
+// PopChallenge returns the challenge saved with NewChallenge and
+// drops it from the session, so that it can be used only once
+func PopChallenge(c *Context) string {
+	challenge, _ := c.s.Values["Challenge"].(string)
+
+	delete(c.s.Values, "Challenge")
+	if err := c.s.Save(c.R, c.W); err != nil {
+		slog.Error("while saving session:", "err", err)
+	}
+
+	return challenge
+}
+
+// SaveOIDCState saves in the session the values needed to complete
+// a sign in with the OpenID Connect provider. If UID is not 0, the
+// identity will be linked to that user.
+func SaveOIDCState(c *Context, state string, nonce string, verifier string, UID int) {
+	c.s.Values["OIDCState"] = state
+	c.s.Values["OIDCNonce"] = nonce
+	c.s.Values["OIDCVerifier"] = verifier
+	c.s.Values["OIDCLink"] = UID
+	if err := c.s.Save(c.R, c.W); err != nil {
+		slog.Error("while saving session:", "err", err)
+	}
+}
+
+// PopOIDCState returns the values saved with SaveOIDCState and
+// drops them from the session, so that they can be used only once
+func PopOIDCState(c *Context) (state string, nonce string, verifier string, UID int) {
+	state, _ = c.s.Values["OIDCState"].(string)
+	nonce, _ = c.s.Values["OIDCNonce"].(string)
+	verifier, _ = c.s.Values["OIDCVerifier"].(string)
+	UID, _ = c.s.Values["OIDCLink"].(int)
+
+	for _, key := range []string{"OIDCState", "OIDCNonce", "OIDCVerifier", "OIDCLink"} {
+		delete(c.s.Values, key)
+	}
+	if err := c.s.Save(c.R, c.W); err != nil {
+		slog.Error("while saving session:", "err", err)
+	}
+
+	return
+}
+
 // DropUID drops the UID from the session.
 // It also redirects to /user/signin, with an optional message
 func DropUID(c *Context, msg langs.String) {
@@ -63,6 +197,9 @@ func DropUID(c *Context, msg langs.String) {
 	if err := c.s.Save(c.R, c.W); err != nil {
 		slog.Error("while saving session:", "err", err)
 	}
+	if err := database.DropSession(c.s.ID); err != nil {
+		slog.Error("while dropping session:", "err", err)
+	}
 
 	if msg != langs.STR_NONE {
 		ShowMessage(c, msg, "/user/signin")
@@ -71,6 +208,12 @@ func DropUID(c *Context, msg langs.String) {
 	}
 }
 
+// SessionKey returns the ID of the current session, that is
+// used to recognize it among the sessions of the user
+func SessionKey(c *Context) string {
+	return c.s.ID
+}
+
 // SetLang sets the session language
 func SetLang(c *Context, lang string) {
 	if _, found := langs.Available[lang]; !found {